@@ -0,0 +1,285 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/app"
+	"github.com/mattermost/mattermost-plugin-user-survey/server/i18n"
+	surveyModel "github.com/mattermost/mattermost-plugin-user-survey/server/model"
+	"github.com/mattermost/mattermost-plugin-user-survey/server/utils"
+)
+
+const (
+	surveyAdminCommand = "survey-admin"
+
+	surveyAdminSubCommandList    = "list"
+	surveyAdminSubCommandStatus  = "status"
+	surveyAdminSubCommandStop    = "stop"
+	surveyAdminSubCommandStats   = "stats"
+	surveyAdminSubCommandExport  = "export"
+	surveyAdminSubCommandPreview = "preview"
+
+	surveyAdminCommandListLimit = 20
+
+	surveyAdminCommandHelpTranslationID = "command.survey_admin.help"
+)
+
+func (p *Plugin) registerAdminCommands() error {
+	T := p.app.GetUserTranslations(p.getDefaultServerLocale())
+
+	autocompleteData := model.NewAutocompleteData(surveyAdminCommand, "[command]", T("command.survey_admin.autocomplete.description"))
+	autocompleteData.RoleID = model.SystemAdminRoleId
+	autocompleteData.AddCommand(model.NewAutocompleteData(surveyAdminSubCommandList, "", T("command.survey_admin.autocomplete.list")))
+
+	for _, subCommand := range []struct{ trigger, helpTextTranslationID string }{
+		{surveyAdminSubCommandStatus, "command.survey_admin.autocomplete.status"},
+		{surveyAdminSubCommandStop, "command.survey_admin.autocomplete.stop"},
+		{surveyAdminSubCommandStats, "command.survey_admin.autocomplete.stats"},
+		{surveyAdminSubCommandExport, "command.survey_admin.autocomplete.export"},
+	} {
+		subCommandData := model.NewAutocompleteData(subCommand.trigger, "[survey ID]", T(subCommand.helpTextTranslationID))
+		subCommandData.AddTextArgument(T("command.survey_admin.autocomplete.survey_id"), "[survey ID]", "")
+		autocompleteData.AddCommand(subCommandData)
+	}
+
+	autocompleteData.AddCommand(model.NewAutocompleteData(surveyAdminSubCommandPreview, "", T("command.survey_admin.autocomplete.preview")))
+
+	err := p.API.RegisterCommand(&model.Command{
+		Trigger:          surveyAdminCommand,
+		DisplayName:      "User Survey Admin",
+		Description:      T("command.survey_admin.autocomplete.description"),
+		AutoComplete:     true,
+		AutoCompleteDesc: T("command.survey_admin.autocomplete.available"),
+		AutoCompleteHint: "[command]",
+		AutocompleteData: autocompleteData,
+	})
+
+	if err != nil {
+		p.API.LogError("registerAdminCommands: failed to register survey admin command", "error", err.Error())
+		return errors.Wrap(err, "registerAdminCommands: failed to register survey admin command")
+	}
+
+	return nil
+}
+
+func (p *Plugin) executeSurveyAdminCommand(_ *plugin.Context, args *model.CommandArgs, params []string) (*model.CommandResponse, *model.AppError) {
+	user, appErr := p.API.GetUser(args.UserId)
+	if appErr != nil {
+		p.API.LogError("executeSurveyAdminCommand: failed to get user by id", "userID", args.UserId, "error", appErr.Error())
+		return &model.CommandResponse{Text: p.app.GetUserTranslations("")(commandErrorTranslationID)}, nil
+	}
+
+	T := p.app.GetUserTranslations(user.Locale)
+
+	if !user.IsSystemAdmin() {
+		return &model.CommandResponse{Text: T("command.survey_admin.not_admin")}, nil
+	}
+
+	if len(params) == 0 {
+		return &model.CommandResponse{Text: T(surveyAdminCommandHelpTranslationID)}, nil
+	}
+
+	var message string
+	var err error
+
+	subCommand := params[0]
+
+	switch subCommand {
+	case surveyAdminSubCommandList:
+		message, err = p.executeSurveyAdminListCommand(T)
+	case surveyAdminSubCommandPreview:
+		message = p.executeSurveyAdminPreviewCommand(T)
+	case surveyAdminSubCommandStatus, surveyAdminSubCommandStop, surveyAdminSubCommandStats, surveyAdminSubCommandExport:
+		if len(params) < 2 {
+			message = T("command.survey_admin.missing_survey_id", map[string]interface{}{"Command": surveyAdminCommand, "SubCommand": subCommand})
+			break
+		}
+
+		message, err = p.executeSurveyAdminSurveyCommand(T, args.UserId, subCommand, params[1])
+	default:
+		message = T(surveyAdminCommandHelpTranslationID)
+	}
+
+	if err != nil {
+		p.API.LogError("executeSurveyAdminCommand: failed to execute command", "command", args.Command, "userID", args.UserId, "error", err.Error())
+		return &model.CommandResponse{Text: T(commandErrorTranslationID)}, nil
+	}
+
+	return &model.CommandResponse{Text: message}, nil
+}
+
+func (p *Plugin) executeSurveyAdminListCommand(T i18n.TranslateFunc) (string, error) {
+	surveyStats, err := p.app.GetSurveyStatList()
+	if err != nil {
+		return "", errors.Wrap(err, "executeSurveyAdminListCommand: failed to get survey stats")
+	}
+
+	if len(surveyStats) == 0 {
+		return T("command.survey_admin.list.no_surveys"), nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(T("command.survey_admin.list.header") + "\n")
+	sb.WriteString("|:---|:---|:---|:---|---:|---:|\n")
+
+	for i, surveyStat := range surveyStats {
+		if i == surveyAdminCommandListLimit {
+			break
+		}
+
+		nps := fmt.Sprintf("%.1f", utils.CalculateNPS(surveyStat.PromoterCount, surveyStat.DetractorCount, surveyStat.PassiveCount))
+		if surveyStat.ResultsWithheld {
+			nps = "-"
+		}
+
+		fmt.Fprintf(
+			&sb,
+			"| `%s` | %s | %s | %s | %d / %d | %s |\n",
+			surveyStat.ID,
+			utils.FormatUnixTimeMillis(surveyStat.StartTime),
+			utils.FormatUnixTimeMillis(surveyStat.GetEndTime().UnixMilli()),
+			surveyStat.Status,
+			surveyStat.ResponseCount,
+			surveyStat.ReceiptCount,
+			nps,
+		)
+	}
+
+	if len(surveyStats) > surveyAdminCommandListLimit {
+		sb.WriteString("\n" + T("command.survey_admin.list.truncated", map[string]interface{}{"Limit": surveyAdminCommandListLimit, "Count": len(surveyStats)}))
+	}
+
+	return sb.String(), nil
+}
+
+func (p *Plugin) executeSurveyAdminSurveyCommand(T i18n.TranslateFunc, userID, subCommand, surveyID string) (string, error) {
+	surveyStat, err := p.app.GetSurveyStat(surveyID)
+	if err != nil {
+		return "", errors.Wrap(err, "executeSurveyAdminSurveyCommand: failed to get survey stat")
+	}
+
+	if surveyStat == nil {
+		return T("command.survey_admin.survey_not_found", map[string]interface{}{"SurveyID": surveyID}), nil
+	}
+
+	switch subCommand {
+	case surveyAdminSubCommandStatus:
+		return formatSurveyStatus(T, surveyStat), nil
+	case surveyAdminSubCommandStats:
+		return formatSurveyStats(T, surveyStat), nil
+	case surveyAdminSubCommandStop:
+		if surveyStat.Status != surveyModel.SurveyStatusInProgress {
+			return T("command.survey_admin.stop.not_running"), nil
+		}
+
+		if err := p.app.StopSurvey(surveyID); err != nil {
+			return "", errors.Wrap(err, "executeSurveyAdminSurveyCommand: failed to stop survey")
+		}
+
+		return T("command.survey_admin.stop.success", map[string]interface{}{"SurveyID": surveyID}), nil
+	case surveyAdminSubCommandExport:
+		err := p.app.SendSurveyReportToUser(userID, surveyID)
+		if errors.Is(err, app.ErrSurveyResultsWithheld) {
+			return T("command.survey_admin.export.results_withheld", map[string]interface{}{"SurveyID": surveyID, "MinGroupSize": surveyStat.Anonymity.GetMinGroupSize()}), nil
+		}
+
+		if err != nil {
+			return "", errors.Wrap(err, "executeSurveyAdminSurveyCommand: failed to send survey report")
+		}
+
+		return T("command.survey_admin.export.success"), nil
+	}
+
+	return T(surveyAdminCommandHelpTranslationID), nil
+}
+
+func (p *Plugin) executeSurveyAdminPreviewCommand(T i18n.TranslateFunc) string {
+	survey := p.app.GetConfiguredSurveyPreview()
+	if len(survey.SurveyQuestions.Questions) == 0 {
+		return T("command.survey_admin.preview.no_questions")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("###### " + T("command.survey_admin.preview.title") + "\n")
+
+	if survey.SurveyQuestions.SurveyMessageText != "" {
+		fmt.Fprintf(&sb, "%s\n\n", survey.SurveyQuestions.SurveyMessageText)
+	}
+
+	for i, question := range survey.SurveyQuestions.Questions {
+		var details []string
+		if question.Type == surveyModel.QuestionTypeLinearScale {
+			details = append(details, T("command.survey_admin.preview.rating"))
+		} else {
+			details = append(details, T("command.survey_admin.preview.text"))
+		}
+
+		if question.Mandatory {
+			details = append(details, T("command.survey_admin.preview.required"))
+		}
+
+		fmt.Fprintf(&sb, "%d. %s _(%s)_\n", i+1, question.Text, strings.Join(details, ", "))
+	}
+
+	if survey.StartTime > 0 {
+		sb.WriteString("\n" + T("command.survey_admin.preview.schedule", map[string]interface{}{"StartDate": utils.FormatUnixTimeMillis(survey.StartTime), "Duration": survey.Duration}))
+	}
+
+	return sb.String()
+}
+
+func formatSurveyStatus(T i18n.TranslateFunc, surveyStat *surveyModel.SurveyStat) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "###### %s\n", T("command.survey_admin.status.title", map[string]interface{}{"SurveyID": surveyStat.ID}))
+	fmt.Fprintf(&sb, "* **%s:** %s\n", T("command.survey_admin.status.status"), surveyStat.Status)
+	fmt.Fprintf(&sb, "* **%s:** %s\n", T("command.survey_admin.status.start_date"), utils.FormatUnixTimeMillis(surveyStat.StartTime))
+	fmt.Fprintf(&sb, "* **%s:** %s\n", T("command.survey_admin.status.end_date"), utils.FormatUnixTimeMillis(surveyStat.GetEndTime().UnixMilli()))
+	fmt.Fprintf(&sb, "* **%s:** %s\n", T("command.survey_admin.status.duration"), T("command.survey_admin.status.duration_days", map[string]interface{}{"Duration": surveyStat.Duration}))
+	fmt.Fprintf(&sb, "* **%s:** %s\n", T("command.survey_admin.status.team_filter"), T("command.survey_admin.status.team_filter_teams", map[string]interface{}{"TeamFilterType": surveyStat.TeamFilterType, "TeamCount": len(surveyStat.FilterTeamIDs)}))
+	fmt.Fprintf(&sb, "* **%s:** %d", T("command.survey_admin.status.questions"), len(surveyStat.SurveyQuestions.Questions))
+
+	if surveyStat.Anonymity.Enabled {
+		fmt.Fprintf(&sb, "\n* **%s:** %s", T("command.survey_admin.status.anonymous"), T("command.survey_admin.status.anonymous_min_group_size", map[string]interface{}{"MinGroupSize": surveyStat.Anonymity.GetMinGroupSize()}))
+	}
+
+	return sb.String()
+}
+
+func formatSurveyStats(T i18n.TranslateFunc, surveyStat *surveyModel.SurveyStat) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "###### %s\n", T("command.survey_admin.stats.title", map[string]interface{}{"SurveyID": surveyStat.ID}))
+	fmt.Fprintf(&sb, "* **%s:** %s\n", T("command.survey_admin.stats.sent_to"), T("command.survey_admin.stats.sent_to_users", map[string]interface{}{"Count": surveyStat.ReceiptCount}))
+	fmt.Fprintf(&sb, "* **%s:** %d\n", T("command.survey_admin.stats.opened"), surveyStat.OpenedCount)
+	fmt.Fprintf(&sb, "* **%s:** %d\n", T("command.survey_admin.stats.responses"), surveyStat.ResponseCount)
+	fmt.Fprintf(&sb, "* **%s:** %d", T("command.survey_admin.stats.completed"), surveyStat.CompletedCount)
+
+	if surveyStat.RedactionCount > 0 {
+		fmt.Fprintf(&sb, "\n* **%s:** %d", T("command.survey_admin.stats.redactions"), surveyStat.RedactionCount)
+	}
+
+	if surveyStat.ResultsWithheld {
+		sb.WriteString("\n\n" + T("command.survey_admin.stats.results_withheld", map[string]interface{}{"MinGroupSize": surveyStat.Anonymity.GetMinGroupSize()}))
+		return sb.String()
+	}
+
+	fmt.Fprintf(&sb, "\n* **%s:** %d\n", T("command.survey_admin.stats.promoters"), surveyStat.PromoterCount)
+	fmt.Fprintf(&sb, "* **%s:** %d\n", T("command.survey_admin.stats.passives"), surveyStat.PassiveCount)
+	fmt.Fprintf(&sb, "* **%s:** %d\n", T("command.survey_admin.stats.detractors"), surveyStat.DetractorCount)
+	fmt.Fprintf(&sb, "* **NPS:** %.1f ± %.1f", utils.CalculateNPS(surveyStat.PromoterCount, surveyStat.DetractorCount, surveyStat.PassiveCount), utils.CalculateNPSMarginOfError(surveyStat.PromoterCount, surveyStat.DetractorCount, surveyStat.PassiveCount))
+
+	if scoreType := surveyStat.GetRatingScale().GetScoreType(); scoreType != surveyModel.RatingScoreTypeNet {
+		fmt.Fprintf(&sb, "\n* **%s:** %.1f", T("command.survey_admin.stats.score", map[string]interface{}{"ScoreType": scoreType}), surveyStat.GetScore())
+	}
+
+	return sb.String()
+}
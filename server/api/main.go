@@ -16,6 +16,7 @@ import (
 
 const (
 	headerMattermostUserID = "Mattermost-User-ID"
+	headerIdempotencyKey   = "Idempotency-Key"
 
 	// TODO - potential improvement - use Mattermost's configured payload
 	//  size limit if available, else this value default
@@ -45,9 +46,20 @@ func (api *Handlers) initRoutes() {
 	root.HandleFunc("/ping", api.handlePing).Methods(http.MethodGet)
 	root.HandleFunc("/connected", api.handleConnected).Methods(http.MethodPost)
 	root.HandleFunc("/survey/{surveyID:[a-z0-9]{26}}/response", api.handleSubmitSurveyResponse).Methods(http.MethodPost)
+	root.HandleFunc("/survey/{surveyID:[a-z0-9]{26}}/response", api.handleWithdrawSurveyResponse).Methods(http.MethodDelete)
+	root.HandleFunc("/survey/{surveyID:[a-z0-9]{26}}/response/{responseID:[a-z0-9]{26}}/revisions", api.handleGetSurveyResponseRevisions).Methods(http.MethodGet)
 	root.HandleFunc("/survey/{surveyID:[a-z0-9]{26}}/end", api.handleStopSurvey).Methods(http.MethodPost)
 	root.HandleFunc("/survey/{surveyID:[a-z0-9]{26}}/report", api.handleGenerateSurveyReport).Methods(http.MethodGet)
+	root.HandleFunc("/survey/{surveyID:[a-z0-9]{26}}/question_stats", api.handleGetSurveyQuestionStats).Methods(http.MethodGet)
 	root.HandleFunc("/survey_stats", api.handleGetSurveyStats).Methods(http.MethodGet)
+	root.HandleFunc("/survey_stats/{surveyID:[a-z0-9]{26}}", api.handleGetSurveyStat).Methods(http.MethodGet)
+	root.HandleFunc("/survey_stats/{surveyID:[a-z0-9]{26}}/team_nps", api.handleGetSurveyTeamNPS).Methods(http.MethodGet)
+	root.HandleFunc("/survey_stats/{surveyID:[a-z0-9]{26}}/time_series", api.handleGetSurveyTimeSeries).Methods(http.MethodGet)
+	root.HandleFunc("/survey_stats/{surveyID:[a-z0-9]{26}}/text_analytics", api.handleGetSurveyTextAnalytics).Methods(http.MethodGet)
+	root.HandleFunc("/survey_stats/{surveyID:[a-z0-9]{26}}/sentiment", api.handleGetSurveySentiment).Methods(http.MethodGet)
+	root.HandleFunc("/survey_stats/{surveyID:[a-z0-9]{26}}/compare/{baseSurveyID:[a-z0-9]{26}}", api.handleCompareSurveys).Methods(http.MethodGet)
+	root.HandleFunc("/survey_stats/reconcile", api.handleReconcileSurveyCounts).Methods(http.MethodPost)
+	root.HandleFunc("/survey_stats/nps_trend", api.handleGetNPSTrend).Methods(http.MethodGet)
 	root.HandleFunc("/survey_post/{postID:[A-Za-z0-9]{26}}/refresh", api.handleRefreshPost).Methods(http.MethodPost)
 }
 
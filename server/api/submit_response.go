@@ -6,10 +6,14 @@ package api
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
 
+	"github.com/mattermost/mattermost-plugin-user-survey/server/app"
 	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+	"github.com/mattermost/mattermost-plugin-user-survey/server/utils"
 )
 
 func (api *Handlers) handleSubmitSurveyResponse(w http.ResponseWriter, r *http.Request) {
@@ -43,6 +47,16 @@ func (api *Handlers) handleSubmitSurveyResponse(w http.ResponseWriter, r *http.R
 
 	response.SurveyID = surveyID
 	response.UserID = userID
+	response.IdempotencyKey = r.Header.Get(headerIdempotencyKey)
+	if len(response.IdempotencyKey) > model.MaxIdempotencyKeyLength {
+		http.Error(w, "idempotency key is too long", http.StatusBadRequest)
+		return
+	}
+
+	// the metadata is captured by the server, only the client type comes from the request
+	response.Metadata = model.ResponseMetadata{
+		ClientType: model.GetClientType(r.UserAgent()),
+	}
 
 	survey, err := api.app.GetInProgressSurvey()
 	if err != nil {
@@ -62,7 +76,31 @@ func (api *Handlers) handleSubmitSurveyResponse(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	if err := api.app.SaveSurveyResponse(response); err != nil {
+	// acquire lock to prevent concurrent submissions from the same user from both being saved
+	key := utils.KeyUserSubmitResponseLock(userID)
+	utcNow := time.Now().UTC()
+	locked, err := api.app.AcquireUserSurveyLock(key, utcNow)
+	if err != nil {
+		http.Error(w, "Failed to acquire lock", http.StatusInternalServerError)
+		return
+	}
+
+	if !locked {
+		http.Error(w, app.ErrSubmissionConflict.Error(), http.StatusConflict)
+		return
+	}
+
+	defer func() {
+		_, _ = api.app.ReleaseUserSurveyLock(key, utcNow)
+	}()
+
+	err = api.app.SaveSurveyResponse(response)
+	if errors.Is(err, app.ErrSubmissionConflict) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	if err != nil {
 		api.pluginAPI.LogError("handleSubmitSurveyResponse: failed to save survey response", "error", err.Error())
 		http.Error(w, "failed to save response", http.StatusInternalServerError)
 		return
@@ -0,0 +1,94 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/app"
+	"github.com/mattermost/mattermost-plugin-user-survey/server/utils"
+)
+
+func (api *Handlers) handleGetSurveyResponseRevisions(w http.ResponseWriter, r *http.Request) {
+	if err := api.RequireAuthentication(w, r); err != nil {
+		return
+	}
+
+	if err := api.RequireSystemAdmin(w, r); err != nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	surveyID, ok := vars["surveyID"]
+	if !ok {
+		http.Error(w, "missing survey ID in request", http.StatusBadRequest)
+		return
+	}
+
+	responseID, ok := vars["responseID"]
+	if !ok {
+		http.Error(w, "missing response ID in request", http.StatusBadRequest)
+		return
+	}
+
+	revisions, err := api.app.GetSurveyResponseRevisions(surveyID, responseID)
+	if err != nil {
+		api.pluginAPI.LogError("handleGetSurveyResponseRevisions: failed to get survey response revisions", "surveyID", surveyID, "responseID", responseID, "error", err.Error())
+		http.Error(w, "Failed to get survey response revisions", http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, revisions)
+}
+
+func (api *Handlers) handleWithdrawSurveyResponse(w http.ResponseWriter, r *http.Request) {
+	if err := api.RequireAuthentication(w, r); err != nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	surveyID, ok := vars["surveyID"]
+	if !ok {
+		http.Error(w, "missing survey ID in request", http.StatusBadRequest)
+		return
+	}
+
+	userID := r.Header.Get(headerMattermostUserID)
+
+	// the response is withdrawn under the same lock as it's saved with,
+	// so a withdrawal can't run while a submission of the response is being saved.
+	key := utils.KeyUserSubmitResponseLock(userID)
+	utcNow := time.Now().UTC()
+	locked, err := api.app.AcquireUserSurveyLock(key, utcNow)
+	if err != nil {
+		http.Error(w, "Failed to acquire lock", http.StatusInternalServerError)
+		return
+	}
+
+	if !locked {
+		http.Error(w, app.ErrSubmissionConflict.Error(), http.StatusConflict)
+		return
+	}
+
+	defer func() {
+		_, _ = api.app.ReleaseUserSurveyLock(key, utcNow)
+	}()
+
+	withdrawn, err := api.app.WithdrawSurveyResponse(userID, surveyID)
+	if err != nil {
+		api.pluginAPI.LogError("handleWithdrawSurveyResponse: failed to withdraw survey response", "userID", userID, "surveyID", surveyID, "error", err.Error())
+		http.Error(w, "Failed to withdraw survey response", http.StatusInternalServerError)
+		return
+	}
+
+	if !withdrawn {
+		http.Error(w, "No response found for the survey", http.StatusNotFound)
+		return
+	}
+
+	ReturnStatusOK(w)
+}
@@ -6,9 +6,14 @@ package api
 import (
 	"net/http"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/app"
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
 )
 
 func (api *Handlers) handleGetSurveyStats(w http.ResponseWriter, r *http.Request) {
@@ -30,6 +35,323 @@ func (api *Handlers) handleGetSurveyStats(w http.ResponseWriter, r *http.Request
 	jsonResponse(w, http.StatusOK, surveyStats)
 }
 
+// handleGetSurveyStat returns the stats of a single survey,
+// including the per-question drop-off of its funnel.
+func (api *Handlers) handleGetSurveyStat(w http.ResponseWriter, r *http.Request) {
+	if err := api.RequireAuthentication(w, r); err != nil {
+		return
+	}
+
+	if err := api.RequireSystemAdmin(w, r); err != nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	surveyID, ok := vars["surveyID"]
+	if !ok {
+		http.Error(w, "missing survey ID in request", http.StatusBadRequest)
+		return
+	}
+
+	surveyStat, err := api.app.GetSurveyStat(surveyID)
+	if err != nil {
+		api.pluginAPI.LogError("handleGetSurveyStat: failed to get survey stat", "surveyID", surveyID, "error", err.Error())
+		http.Error(w, "Failed to get survey stat", http.StatusInternalServerError)
+		return
+	}
+
+	if surveyStat == nil {
+		http.Error(w, "survey not found", http.StatusNotFound)
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, surveyStat)
+}
+
+// handleGetSurveyQuestionStats returns the aggregates of the answers to each of the survey's questions.
+func (api *Handlers) handleGetSurveyQuestionStats(w http.ResponseWriter, r *http.Request) {
+	if err := api.RequireAuthentication(w, r); err != nil {
+		return
+	}
+
+	if err := api.RequireSystemAdmin(w, r); err != nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	surveyID, ok := vars["surveyID"]
+	if !ok {
+		http.Error(w, "missing survey ID in request", http.StatusBadRequest)
+		return
+	}
+
+	surveyStat, err := api.app.GetSurveyStat(surveyID)
+	if err != nil {
+		api.pluginAPI.LogError("handleGetSurveyQuestionStats: failed to get survey stat", "surveyID", surveyID, "error", err.Error())
+		http.Error(w, "Failed to get survey question stats", http.StatusInternalServerError)
+		return
+	}
+
+	if surveyStat == nil {
+		http.Error(w, "survey not found", http.StatusNotFound)
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, model.SurveyQuestionStats{
+		SurveyID:        surveyStat.ID,
+		ResultsWithheld: surveyStat.ResultsWithheld,
+		Questions:       surveyStat.QuestionStats,
+	})
+}
+
+// handleGetSurveyTeamNPS returns the survey's NPS by the respondents' teams.
+func (api *Handlers) handleGetSurveyTeamNPS(w http.ResponseWriter, r *http.Request) {
+	if err := api.RequireAuthentication(w, r); err != nil {
+		return
+	}
+
+	if err := api.RequireSystemAdmin(w, r); err != nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	surveyID, ok := vars["surveyID"]
+	if !ok {
+		http.Error(w, "missing survey ID in request", http.StatusBadRequest)
+		return
+	}
+
+	surveyTeamNPS, err := api.app.GetSurveyTeamNPS(surveyID)
+	if err != nil {
+		api.pluginAPI.LogError("handleGetSurveyTeamNPS: failed to get survey team NPS", "surveyID", surveyID, "error", err.Error())
+		http.Error(w, "Failed to get survey team NPS", http.StatusInternalServerError)
+		return
+	}
+
+	if surveyTeamNPS == nil {
+		http.Error(w, "survey not found", http.StatusNotFound)
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, surveyTeamNPS)
+}
+
+// handleGetSurveyTimeSeries returns the survey's activity per day, or per hour
+// if the interval query parameter is "hour" and the survey isn't anonymous.
+func (api *Handlers) handleGetSurveyTimeSeries(w http.ResponseWriter, r *http.Request) {
+	if err := api.RequireAuthentication(w, r); err != nil {
+		return
+	}
+
+	if err := api.RequireSystemAdmin(w, r); err != nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	surveyID, ok := vars["surveyID"]
+	if !ok {
+		http.Error(w, "missing survey ID in request", http.StatusBadRequest)
+		return
+	}
+
+	interval := r.URL.Query().Get("interval")
+	if interval == "" {
+		interval = model.TimeSeriesIntervalDay
+	}
+
+	if _, err := model.GetTimeSeriesIntervalMillis(interval); err != nil {
+		http.Error(w, "invalid interval in request", http.StatusBadRequest)
+		return
+	}
+
+	timeSeries, err := api.app.GetSurveyTimeSeries(surveyID, interval)
+	if errors.Is(err, app.ErrAnonymousSurveyHourlyTimeSeries) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err != nil {
+		api.pluginAPI.LogError("handleGetSurveyTimeSeries: failed to get survey time series", "surveyID", surveyID, "interval", interval, "error", err.Error())
+		http.Error(w, "Failed to get survey time series", http.StatusInternalServerError)
+		return
+	}
+
+	if timeSeries == nil {
+		http.Error(w, "survey not found", http.StatusNotFound)
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, timeSeries)
+}
+
+// handleGetSurveyTextAnalytics returns the keywords and phrases of the survey's text answers.
+// Surveys are analyzed by a background job once they end.
+func (api *Handlers) handleGetSurveyTextAnalytics(w http.ResponseWriter, r *http.Request) {
+	if err := api.RequireAuthentication(w, r); err != nil {
+		return
+	}
+
+	if err := api.RequireSystemAdmin(w, r); err != nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	surveyID, ok := vars["surveyID"]
+	if !ok {
+		http.Error(w, "missing survey ID in request", http.StatusBadRequest)
+		return
+	}
+
+	analytics, err := api.app.GetSurveyTextAnalytics(surveyID)
+	if err != nil {
+		api.pluginAPI.LogError("handleGetSurveyTextAnalytics: failed to get survey text analytics", "surveyID", surveyID, "error", err.Error())
+		http.Error(w, "Failed to get survey text analytics", http.StatusInternalServerError)
+		return
+	}
+
+	if analytics == nil {
+		http.Error(w, "survey text analytics not found", http.StatusNotFound)
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, analytics)
+}
+
+// handleGetSurveySentiment returns the average sentiment of the survey's text answers,
+// overall, by rating group and by team.
+func (api *Handlers) handleGetSurveySentiment(w http.ResponseWriter, r *http.Request) {
+	if err := api.RequireAuthentication(w, r); err != nil {
+		return
+	}
+
+	if err := api.RequireSystemAdmin(w, r); err != nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	surveyID, ok := vars["surveyID"]
+	if !ok {
+		http.Error(w, "missing survey ID in request", http.StatusBadRequest)
+		return
+	}
+
+	surveySentiment, err := api.app.GetSurveySentiment(surveyID)
+	if err != nil {
+		api.pluginAPI.LogError("handleGetSurveySentiment: failed to get survey sentiment", "surveyID", surveyID, "error", err.Error())
+		http.Error(w, "Failed to get survey sentiment", http.StatusInternalServerError)
+		return
+	}
+
+	if surveySentiment == nil {
+		http.Error(w, "survey not found", http.StatusNotFound)
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, surveySentiment)
+}
+
+// handleCompareSurveys returns the difference of the survey's results from the base survey's results.
+func (api *Handlers) handleCompareSurveys(w http.ResponseWriter, r *http.Request) {
+	if err := api.RequireAuthentication(w, r); err != nil {
+		return
+	}
+
+	if err := api.RequireSystemAdmin(w, r); err != nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	surveyID, ok := vars["surveyID"]
+	if !ok {
+		http.Error(w, "missing survey ID in request", http.StatusBadRequest)
+		return
+	}
+
+	baseSurveyID, ok := vars["baseSurveyID"]
+	if !ok {
+		http.Error(w, "missing base survey ID in request", http.StatusBadRequest)
+		return
+	}
+
+	comparison, err := api.app.CompareSurveys(surveyID, baseSurveyID)
+	if err != nil {
+		api.pluginAPI.LogError("handleCompareSurveys: failed to compare surveys", "surveyID", surveyID, "baseSurveyID", baseSurveyID, "error", err.Error())
+		http.Error(w, "Failed to compare surveys", http.StatusInternalServerError)
+		return
+	}
+
+	if comparison == nil {
+		http.Error(w, "survey not found", http.StatusNotFound)
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, comparison)
+}
+
+// handleGetNPSTrend returns a page of the NPS of the ended surveys over time, optionally
+// restricted to the surveys with the question given as the question_id query parameter.
+func (api *Handlers) handleGetNPSTrend(w http.ResponseWriter, r *http.Request) {
+	if err := api.RequireAuthentication(w, r); err != nil {
+		return
+	}
+
+	if err := api.RequireSystemAdmin(w, r); err != nil {
+		return
+	}
+
+	query := r.URL.Query()
+	filter := model.NPSTrendFilter{
+		QuestionID: query.Get("question_id"),
+		PerPage:    model.DefaultNPSTrendPerPage,
+	}
+
+	if page := query.Get("page"); page != "" {
+		var err error
+		if filter.Page, err = strconv.Atoi(page); err != nil || filter.Page < 0 {
+			http.Error(w, "invalid page in request", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if perPage := query.Get("per_page"); perPage != "" {
+		var err error
+		if filter.PerPage, err = strconv.Atoi(perPage); err != nil || filter.PerPage <= 0 {
+			http.Error(w, "invalid per_page in request", http.StatusBadRequest)
+			return
+		}
+	}
+
+	trend, err := api.app.GetNPSTrend(filter)
+	if err != nil {
+		api.pluginAPI.LogError("handleGetNPSTrend: failed to get NPS trend", "questionID", filter.QuestionID, "page", filter.Page, "error", err.Error())
+		http.Error(w, "Failed to get NPS trend", http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, trend)
+}
+
+// handleReconcileSurveyCounts recomputes the survey counters,
+// returning the surveys whose counters had drifted and were fixed.
+func (api *Handlers) handleReconcileSurveyCounts(w http.ResponseWriter, r *http.Request) {
+	if err := api.RequireAuthentication(w, r); err != nil {
+		return
+	}
+
+	if err := api.RequireSystemAdmin(w, r); err != nil {
+		return
+	}
+
+	reconciliations, err := api.app.ReconcileSurveyCounts()
+	if err != nil {
+		api.pluginAPI.LogError("handleReconcileSurveyCounts: failed to reconcile survey counts", "error", err.Error())
+		http.Error(w, "Failed to reconcile survey counts", http.StatusInternalServerError)
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, reconciliations)
+}
+
 func (api *Handlers) handleGenerateSurveyReport(w http.ResponseWriter, r *http.Request) {
 	if err := api.RequireAuthentication(w, r); err != nil {
 		return
@@ -49,6 +371,11 @@ func (api *Handlers) handleGenerateSurveyReport(w http.ResponseWriter, r *http.R
 	}
 
 	file, err := api.app.GenerateSurveyReport(userID, surveyID)
+	if errors.Is(err, app.ErrSurveyResultsWithheld) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
 	if err != nil {
 		http.Error(w, "failed to generate survey report", http.StatusInternalServerError)
 		return
@@ -0,0 +1,82 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"crypto/rand"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+	"github.com/mattermost/mattermost-plugin-user-survey/server/utils"
+)
+
+const (
+	pseudonymSecretLength = 32
+)
+
+var ErrSurveyResultsWithheld = errors.New("the anonymous survey doesn't have enough responses to report its results")
+
+// getRespondentID returns the ID the user's response to the survey is stored under.
+// For anonymous surveys, this is a one-way pseudonym of the user ID. The pseudonym
+// is scoped to the survey so responses to different surveys can't be linked together.
+func (a *UserSurveyApp) getRespondentID(userID string, survey *model.Survey) (string, error) {
+	if !survey.Anonymity.Enabled {
+		return userID, nil
+	}
+
+	secret, err := a.getPseudonymSecret()
+	if err != nil {
+		return "", errors.Wrap(err, "getRespondentID: failed to get pseudonym secret")
+	}
+
+	return utils.NewPseudonymID(secret, survey.ID, userID), nil
+}
+
+// getPseudonymSecret returns the secret pseudonyms are derived with,
+// generating it the first time it's needed. The secret is kept in the plugin's KV store,
+// which lives in the same database as the responses, so anyone with access to the database
+// can recompute the pseudonyms of known users.
+func (a *UserSurveyApp) getPseudonymSecret() ([]byte, error) {
+	a.pseudonymSecretMutex.Lock()
+	defer a.pseudonymSecretMutex.Unlock()
+
+	if a.pseudonymSecret != nil {
+		return a.pseudonymSecret, nil
+	}
+
+	secret, appErr := a.api.KVGet(utils.KeyPseudonymSecret)
+	if appErr != nil {
+		a.api.LogError("getPseudonymSecret: failed to get pseudonym secret from KV store", "error", appErr.Error())
+		return nil, errors.Wrap(errors.New(appErr.Error()), "getPseudonymSecret: failed to get pseudonym secret from KV store")
+	}
+
+	if secret == nil {
+		newSecret := make([]byte, pseudonymSecretLength)
+		if _, err := rand.Read(newSecret); err != nil {
+			a.api.LogError("getPseudonymSecret: failed to generate pseudonym secret", "error", err.Error())
+			return nil, errors.Wrap(err, "getPseudonymSecret: failed to generate pseudonym secret")
+		}
+
+		saved, appErr := a.api.KVCompareAndSet(utils.KeyPseudonymSecret, nil, newSecret)
+		if appErr != nil {
+			a.api.LogError("getPseudonymSecret: failed to save pseudonym secret in KV store", "error", appErr.Error())
+			return nil, errors.Wrap(errors.New(appErr.Error()), "getPseudonymSecret: failed to save pseudonym secret in KV store")
+		}
+
+		if saved {
+			secret = newSecret
+		} else {
+			// another plugin instance generated the secret first
+			secret, appErr = a.api.KVGet(utils.KeyPseudonymSecret)
+			if appErr != nil {
+				a.api.LogError("getPseudonymSecret: failed to get pseudonym secret from KV store", "error", appErr.Error())
+				return nil, errors.Wrap(errors.New(appErr.Error()), "getPseudonymSecret: failed to get pseudonym secret from KV store")
+			}
+		}
+	}
+
+	a.pseudonymSecret = secret
+	return secret, nil
+}
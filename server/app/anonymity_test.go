@@ -0,0 +1,102 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+func TestGetRespondentID(t *testing.T) {
+	t.Run("should use user ID for surveys that aren't anonymous", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		respondentID, err := th.App.getRespondentID("user_id", &model.Survey{ID: "survey_id"})
+		require.NoError(t, err)
+		require.Equal(t, "user_id", respondentID)
+	})
+
+	t.Run("should generate the pseudonym secret once", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		var savedSecret []byte
+		th.MockedPluginAPI.On("KVGet", "survey_pseudonym_secret").Return(nil, nil).Once()
+		th.MockedPluginAPI.On("KVCompareAndSet", "survey_pseudonym_secret", []byte(nil), mock.Anything).Run(func(args mock.Arguments) {
+			savedSecret = args.Get(2).([]byte)
+		}).Return(true, nil).Once()
+
+		survey := &model.Survey{ID: "survey_id", Anonymity: model.Anonymity{Enabled: true}}
+
+		respondentID, err := th.App.getRespondentID("user_id", survey)
+		require.NoError(t, err)
+		require.Len(t, savedSecret, pseudonymSecretLength)
+		require.NotEqual(t, "user_id", respondentID)
+		require.Len(t, respondentID, 26)
+
+		// the secret is cached, so the pseudonym is stable without reading the KV store again
+		sameRespondentID, err := th.App.getRespondentID("user_id", survey)
+		require.NoError(t, err)
+		require.Equal(t, respondentID, sameRespondentID)
+
+		otherRespondentID, err := th.App.getRespondentID("user_id", &model.Survey{ID: "other_survey_id", Anonymity: model.Anonymity{Enabled: true}})
+		require.NoError(t, err)
+		require.NotEqual(t, respondentID, otherRespondentID)
+	})
+
+	t.Run("should use the secret saved by another instance", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		th.MockedPluginAPI.On("KVGet", "survey_pseudonym_secret").Return(nil, nil).Once()
+		th.MockedPluginAPI.On("KVCompareAndSet", "survey_pseudonym_secret", []byte(nil), mock.Anything).Return(false, nil).Once()
+		th.MockedPluginAPI.On("KVGet", "survey_pseudonym_secret").Return([]byte("secret"), nil).Once()
+
+		secret, err := th.App.getPseudonymSecret()
+		require.NoError(t, err)
+		require.Equal(t, []byte("secret"), secret)
+	})
+}
+
+func TestGetSurveyStatWithholdsAnonymousResults(t *testing.T) {
+	testCases := []struct {
+		name             string
+		anonymity        model.Anonymity
+		responseCount    int64
+		expectedWithheld bool
+	}{
+		{name: "should report survey that isn't anonymous", anonymity: model.Anonymity{}, responseCount: 1, expectedWithheld: false},
+		{name: "should withhold anonymous survey below default group size", anonymity: model.Anonymity{Enabled: true}, responseCount: 4, expectedWithheld: true},
+		{name: "should report anonymous survey at default group size", anonymity: model.Anonymity{Enabled: true}, responseCount: 5, expectedWithheld: false},
+		{name: "should withhold anonymous survey below configured group size", anonymity: model.Anonymity{Enabled: true, MinGroupSize: 10}, responseCount: 9, expectedWithheld: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			th := SetupAppTest(t)
+
+			th.MockedStore.On("GetSurveyStat", "survey_id").Return(&model.SurveyStat{
+				Survey:         model.Survey{ID: "survey_id", Anonymity: tc.anonymity},
+				ResponseCount:  tc.responseCount,
+				PromoterCount:  tc.responseCount,
+				PassiveCount:   0,
+				DetractorCount: 0,
+			}, nil)
+			th.MockedStore.On("GetQuestionAnswerCounts", "survey_id", []string{}).Return(map[string]int64{}, nil)
+
+			surveyStat, err := th.App.GetSurveyStat("survey_id")
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedWithheld, surveyStat.ResultsWithheld)
+			require.Equal(t, tc.responseCount, surveyStat.ResponseCount)
+
+			if tc.expectedWithheld {
+				require.Zero(t, surveyStat.PromoterCount)
+			} else {
+				require.Equal(t, tc.responseCount, surveyStat.PromoterCount)
+			}
+		})
+	}
+}
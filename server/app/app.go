@@ -4,9 +4,14 @@
 package app
 
 import (
+	"sync"
+	"sync/atomic"
+
 	"github.com/mattermost/mattermost/server/public/plugin"
 	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/pkg/errors"
 
+	"github.com/mattermost/mattermost-plugin-user-survey/server/i18n"
 	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
 	"github.com/mattermost/mattermost-plugin-user-survey/server/store"
 )
@@ -18,6 +23,17 @@ type UserSurveyApp struct {
 	apiClient  *pluginapi.Client
 	botID      string
 	debugBuild bool
+	i18n       *i18n.Bundle
+
+	// customBots caches the survey specific bots as last updated, keyed by bot username
+	customBots sync.Map
+
+	// pseudonymSecret caches the secret the pseudonyms of anonymous respondents are derived with
+	pseudonymSecret      []byte
+	pseudonymSecretMutex sync.Mutex
+
+	// surveyDeliveriesBackfilled caches whether the survey deliveries in the KV store were backfilled
+	surveyDeliveriesBackfilled atomic.Bool
 }
 
 func New(
@@ -27,14 +43,25 @@ func New(
 	driver plugin.Driver,
 	debugBuild bool,
 ) (*UserSurveyApp, error) {
+	i18nBundle, err := i18n.NewBundle()
+	if err != nil {
+		return nil, errors.Wrap(err, "New: failed to load translations")
+	}
+
 	app := &UserSurveyApp{
 		api:        api,
 		store:      store,
 		getConfig:  getConfigFunc,
 		apiClient:  pluginapi.NewClient(api, driver),
 		debugBuild: debugBuild,
+		i18n:       i18nBundle,
 	}
 
-	err := app.ensureSurveyBot()
+	err = app.ensureSurveyBot()
 	return app, err
 }
+
+// GetUserTranslations returns the translate function for the user's locale.
+func (a *UserSurveyApp) GetUserTranslations(locale string) i18n.TranslateFunc {
+	return a.i18n.GetUserTranslations(locale)
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+const (
+	customBotDescription = "Created by User Survey plugin"
+)
+
+// customBot is a survey specific bot along with the settings it was last updated with.
+type customBot struct {
+	settings model.SurveyBot
+	botID    string
+}
+
+// getSurveyBotID returns the ID of the bot the survey is sent from. This is the
+// default survey bot unless the survey specifies its own bot.
+func (a *UserSurveyApp) getSurveyBotID(survey *model.Survey) (string, error) {
+	if survey == nil || !survey.Customization.HasCustomBot() {
+		if err := a.ensureSurveyBot(); err != nil {
+			return "", errors.Wrap(err, "getSurveyBotID: failed to ensure survey bot")
+		}
+
+		return a.botID, nil
+	}
+
+	// the bot is updated again if the survey's bot settings differ from the ones it was last updated with
+	settings := survey.Customization.Bot
+	if cached, ok := a.customBots.Load(settings.Username); ok && cached.(customBot).settings == settings {
+		return cached.(customBot).botID, nil
+	}
+
+	botID, err := a.ensureCustomSurveyBot(settings)
+	if err != nil {
+		return "", errors.Wrapf(err, "getSurveyBotID: failed to ensure custom survey bot, surveyID: %s", survey.ID)
+	}
+
+	a.customBots.Store(settings.Username, customBot{settings: settings, botID: botID})
+	return botID, nil
+}
+
+// ensureCustomSurveyBot creates the survey specific bot if it doesn't exist yet,
+// and updates its display name and profile image otherwise.
+// The plugin API's EnsureBotUser only supports a single bot per plugin,
+// so the custom bots are managed by username instead. Existing bots are
+// only used if they're owned by this plugin.
+func (a *UserSurveyApp) ensureCustomSurveyBot(surveyBot model.SurveyBot) (string, error) {
+	displayName := surveyBot.DisplayName
+	if displayName == "" {
+		displayName = surveyBot.Username
+	}
+
+	var botID string
+
+	user, appErr := a.api.GetUserByUsername(surveyBot.Username)
+	switch {
+	case appErr == nil && !user.IsBot:
+		return "", fmt.Errorf("ensureCustomSurveyBot: username is already taken by a user who is not a bot, username: %s", surveyBot.Username)
+	case appErr == nil:
+		bot, appErr := a.api.GetBot(user.Id, true)
+		if appErr != nil {
+			a.api.LogError("ensureCustomSurveyBot: failed to get bot", "botID", user.Id, "error", appErr.Error())
+			return "", errors.Wrap(errors.New(appErr.Error()), "ensureCustomSurveyBot: failed to get bot")
+		}
+
+		if bot.OwnerId != model.PluginID {
+			return "", fmt.Errorf("ensureCustomSurveyBot: username is already taken by a bot not owned by the plugin, username: %s", surveyBot.Username)
+		}
+
+		botID = bot.UserId
+
+		if bot.DeleteAt != 0 {
+			if _, appErr := a.api.UpdateBotActive(botID, true); appErr != nil {
+				a.api.LogError("ensureCustomSurveyBot: failed to activate bot", "botID", botID, "error", appErr.Error())
+				return "", errors.Wrap(errors.New(appErr.Error()), "ensureCustomSurveyBot: failed to activate bot")
+			}
+		}
+
+		if _, appErr := a.api.PatchBot(botID, &mmModel.BotPatch{DisplayName: &displayName}); appErr != nil {
+			a.api.LogError("ensureCustomSurveyBot: failed to update bot", "botID", botID, "error", appErr.Error())
+			return "", errors.Wrap(errors.New(appErr.Error()), "ensureCustomSurveyBot: failed to update bot")
+		}
+	case appErr.StatusCode == http.StatusNotFound:
+		bot, appErr := a.api.CreateBot(&mmModel.Bot{
+			Username:    surveyBot.Username,
+			DisplayName: displayName,
+			Description: customBotDescription,
+		})
+		if appErr != nil {
+			a.api.LogError("ensureCustomSurveyBot: failed to create bot", "username", surveyBot.Username, "error", appErr.Error())
+			return "", errors.Wrap(errors.New(appErr.Error()), "ensureCustomSurveyBot: failed to create bot")
+		}
+
+		botID = bot.UserId
+	default:
+		a.api.LogError("ensureCustomSurveyBot: failed to get bot user by username", "username", surveyBot.Username, "error", appErr.Error())
+		return "", errors.Wrap(errors.New(appErr.Error()), "ensureCustomSurveyBot: failed to get bot user by username")
+	}
+
+	if surveyBot.ProfileImage != "" {
+		profileImage, err := surveyBot.ProfileImageBytes()
+		if err != nil {
+			a.api.LogError("ensureCustomSurveyBot: failed to decode bot profile image", "username", surveyBot.Username, "error", err.Error())
+			return "", errors.Wrap(err, "ensureCustomSurveyBot: failed to decode bot profile image")
+		}
+
+		if appErr := a.api.SetProfileImage(botID, profileImage); appErr != nil {
+			a.api.LogError("ensureCustomSurveyBot: failed to set bot profile image", "botID", botID, "error", appErr.Error())
+			return "", errors.Wrap(errors.New(appErr.Error()), "ensureCustomSurveyBot: failed to set bot profile image")
+		}
+	}
+
+	return botID, nil
+}
+
+// renderMessage renders a custom message template for the user,
+// falling back to the default message if the template is empty or fails to render.
+func (a *UserSurveyApp) renderMessage(messageTemplate, defaultMessage string, user *mmModel.User) string {
+	if strings.TrimSpace(messageTemplate) == "" {
+		return defaultMessage
+	}
+
+	tmpl, err := template.New("message").Parse(messageTemplate)
+	if err != nil {
+		a.api.LogWarn("renderMessage: failed to parse message template, using default message", "error", err.Error())
+		return defaultMessage
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, model.NewMessageTemplateData(user)); err != nil {
+		a.api.LogWarn("renderMessage: failed to render message template, using default message", "error", err.Error())
+		return defaultMessage
+	}
+
+	return buf.String()
+}
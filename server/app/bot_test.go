@@ -0,0 +1,153 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"testing"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+func TestGetSurveyBotID(t *testing.T) {
+	t.Run("should use default bot when survey doesn't specify a bot", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		botID, err := th.App.getSurveyBotID(&model.Survey{ID: "survey_id"})
+		require.NoError(t, err)
+		require.Equal(t, "bot_user_id", botID)
+	})
+
+	t.Run("should create custom bot if it doesn't exist", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		th.MockedPluginAPI.On("GetUserByUsername", "hr_bot").Return(nil, &mmModel.AppError{StatusCode: http.StatusNotFound})
+		th.MockedPluginAPI.On("CreateBot", mock.MatchedBy(func(bot *mmModel.Bot) bool {
+			return bot.Username == "hr_bot" && bot.DisplayName == "HR"
+		})).Return(&mmModel.Bot{UserId: "hr_bot_id"}, nil).Once()
+
+		survey := &model.Survey{
+			ID: "survey_id",
+			Customization: model.Customization{
+				Bot: model.SurveyBot{Username: "hr_bot", DisplayName: "HR"},
+			},
+		}
+
+		botID, err := th.App.getSurveyBotID(survey)
+		require.NoError(t, err)
+		require.Equal(t, "hr_bot_id", botID)
+
+		// second call should be served from cache
+		botID, err = th.App.getSurveyBotID(survey)
+		require.NoError(t, err)
+		require.Equal(t, "hr_bot_id", botID)
+		th.MockedPluginAPI.AssertNumberOfCalls(t, "GetUserByUsername", 1)
+	})
+
+	t.Run("should reuse existing custom bot", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		th.MockedPluginAPI.On("GetUserByUsername", "hr_bot").Return(&mmModel.User{Id: "hr_bot_id", IsBot: true}, nil)
+		th.MockedPluginAPI.On("GetBot", "hr_bot_id", true).Return(&mmModel.Bot{UserId: "hr_bot_id", OwnerId: model.PluginID}, nil)
+		th.MockedPluginAPI.On("PatchBot", "hr_bot_id", mock.Anything).Return(&mmModel.Bot{UserId: "hr_bot_id"}, nil)
+
+		survey := &model.Survey{
+			ID: "survey_id",
+			Customization: model.Customization{
+				Bot: model.SurveyBot{Username: "hr_bot"},
+			},
+		}
+
+		botID, err := th.App.getSurveyBotID(survey)
+		require.NoError(t, err)
+		require.Equal(t, "hr_bot_id", botID)
+		th.MockedPluginAPI.AssertNotCalled(t, "CreateBot", mock.Anything)
+	})
+
+	t.Run("should update the custom bot when the survey's bot settings change", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		th.MockedPluginAPI.On("GetUserByUsername", "hr_bot").Return(&mmModel.User{Id: "hr_bot_id", IsBot: true}, nil)
+		th.MockedPluginAPI.On("GetBot", "hr_bot_id", true).Return(&mmModel.Bot{UserId: "hr_bot_id", OwnerId: model.PluginID}, nil)
+		th.MockedPluginAPI.On("PatchBot", "hr_bot_id", mock.Anything).Return(&mmModel.Bot{UserId: "hr_bot_id"}, nil)
+
+		survey := &model.Survey{
+			ID: "survey_id",
+			Customization: model.Customization{
+				Bot: model.SurveyBot{Username: "hr_bot", DisplayName: "HR"},
+			},
+		}
+
+		_, err := th.App.getSurveyBotID(survey)
+		require.NoError(t, err)
+
+		survey.Customization.Bot.DisplayName = "People Team"
+		_, err = th.App.getSurveyBotID(survey)
+		require.NoError(t, err)
+
+		th.MockedPluginAPI.AssertCalled(t, "PatchBot", "hr_bot_id", mock.MatchedBy(func(patch *mmModel.BotPatch) bool {
+			return *patch.DisplayName == "People Team"
+		}))
+		th.MockedPluginAPI.AssertNumberOfCalls(t, "PatchBot", 2)
+	})
+
+	t.Run("should not use a bot owned by someone else as the survey bot", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		th.MockedPluginAPI.On("GetUserByUsername", "jira").Return(&mmModel.User{Id: "jira_bot_id", IsBot: true}, nil)
+		th.MockedPluginAPI.On("GetBot", "jira_bot_id", true).Return(&mmModel.Bot{UserId: "jira_bot_id", OwnerId: "jira"}, nil)
+
+		survey := &model.Survey{
+			ID: "survey_id",
+			Customization: model.Customization{
+				Bot: model.SurveyBot{Username: "jira"},
+			},
+		}
+
+		_, err := th.App.getSurveyBotID(survey)
+		require.Error(t, err)
+		th.MockedPluginAPI.AssertNotCalled(t, "PatchBot", mock.Anything, mock.Anything)
+		th.MockedPluginAPI.AssertNotCalled(t, "UpdateBotActive", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should not use a regular user as the survey bot", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		th.MockedPluginAPI.On("GetUserByUsername", "hr").Return(&mmModel.User{Id: "user_id"}, nil)
+
+		survey := &model.Survey{
+			ID: "survey_id",
+			Customization: model.Customization{
+				Bot: model.SurveyBot{Username: "hr"},
+			},
+		}
+
+		_, err := th.App.getSurveyBotID(survey)
+		require.Error(t, err)
+	})
+}
+
+func TestRenderMessage(t *testing.T) {
+	user := &mmModel.User{Username: "john.doe", FirstName: "John"}
+
+	t.Run("should use default message when template is empty", func(t *testing.T) {
+		th := SetupAppTest(t)
+		require.Equal(t, "default", th.App.renderMessage("  ", "default", user))
+	})
+
+	t.Run("should render user fields", func(t *testing.T) {
+		th := SetupAppTest(t)
+		require.Equal(t, "Thank you **John**!", th.App.renderMessage("Thank you **{{.FirstName}}**!", "default", user))
+	})
+
+	t.Run("should use default message when template fails to render", func(t *testing.T) {
+		th := SetupAppTest(t)
+		require.Equal(t, "default", th.App.renderMessage("Hi {{.Unknown}}", "default", user))
+		require.Equal(t, "default", th.App.renderMessage("Hi {{.FirstName", "default", user))
+	})
+}
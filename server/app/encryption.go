@@ -0,0 +1,229 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+const reencryptPerPage = 1000
+
+func (a *UserSurveyApp) getAnswerCipher() (*model.AnswerCipher, error) {
+	answerCipher, err := a.getConfig().Encryption.NewAnswerCipher()
+	if err != nil {
+		a.api.LogError("getAnswerCipher: failed to create answer cipher from configured encryption settings", "error", err.Error())
+		return nil, errors.Wrap(err, "getAnswerCipher: failed to create answer cipher from configured encryption settings")
+	}
+
+	return answerCipher, nil
+}
+
+// CheckEncryptionKeysRemovable returns an error if the new encryption settings remove or replace
+// a configured key that stored answers are still encrypted with, as they couldn't be decrypted anymore.
+func (a *UserSurveyApp) CheckEncryptionKeysRemovable(newEncryption model.Encryption) error {
+	newKeys := map[string]string{}
+	for _, key := range newEncryption.Keys {
+		newKeys[key.ID] = key.Key
+	}
+
+	for _, key := range a.getConfig().Encryption.Keys {
+		if newKey, ok := newKeys[key.ID]; ok && newKey == key.Key {
+			continue
+		}
+
+		inUse, err := a.store.IsEncryptionKeyInUse(key.ID)
+		if err != nil {
+			return errors.Wrapf(err, "CheckEncryptionKeysRemovable: failed to check if encryption key is in use, keyID: %s", key.ID)
+		}
+
+		if inUse {
+			return errors.Errorf("encryption key %s can't be removed or changed while survey answers are encrypted with it. Make another key active and remove the key once the answers are re-encrypted", key.ID)
+		}
+	}
+
+	return nil
+}
+
+// encryptResponse returns a copy of the response with its text answers prepared
+// for storing, encrypted if encryption is enabled.
+func (a *UserSurveyApp) encryptResponse(survey *model.Survey, response *model.SurveyResponse) (*model.SurveyResponse, error) {
+	answerCipher, err := a.getAnswerCipher()
+	if err != nil {
+		return nil, errors.Wrap(err, "encryptResponse: failed to get answer cipher")
+	}
+
+	encrypted := *response
+	encrypted.Response, err = answerCipher.EncryptAnswers(response.Response, survey.SurveyQuestions)
+	if err != nil {
+		return nil, errors.Wrapf(err, "encryptResponse: failed to encrypt response answers, responseID: %s", response.ID)
+	}
+
+	return &encrypted, nil
+}
+
+// decryptResponses decrypts the encrypted answers of the responses in place.
+// Answers that can't be decrypted are logged and left out.
+func (a *UserSurveyApp) decryptResponses(responses []*model.SurveyResponse) error {
+	answerCipher, err := a.getAnswerCipher()
+	if err != nil {
+		return errors.Wrap(err, "decryptResponses: failed to get answer cipher")
+	}
+
+	for _, response := range responses {
+		response.Response, err = answerCipher.DecryptAnswers(response.Response)
+		if err != nil {
+			a.api.LogWarn("decryptResponses: skipping response answers that failed to decrypt", "responseID", response.ID, "error", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// decryptRevisions decrypts the encrypted answers of the revisions in place.
+// Answers that can't be decrypted are logged and left out.
+func (a *UserSurveyApp) decryptRevisions(revisions []*model.SurveyResponseRevision) error {
+	answerCipher, err := a.getAnswerCipher()
+	if err != nil {
+		return errors.Wrap(err, "decryptRevisions: failed to get answer cipher")
+	}
+
+	for _, revision := range revisions {
+		revision.Response, err = answerCipher.DecryptAnswers(revision.Response)
+		if err != nil {
+			a.api.LogWarn("decryptRevisions: skipping revision answers that failed to decrypt", "revisionID", revision.ID, "error", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// JobReencryptSurveyResponses is a scheduled job that encrypts the stored answers
+// with the active encryption key, such as after rotating the key.
+func (a *UserSurveyApp) JobReencryptSurveyResponses() error {
+	a.api.LogDebug("JobReencryptSurveyResponses: running")
+
+	count, err := a.ReencryptSurveyResponses()
+	if err != nil {
+		a.api.LogError("JobReencryptSurveyResponses: failed to re-encrypt survey responses", "error", err.Error())
+		return err
+	}
+
+	if count > 0 {
+		a.api.LogInfo("JobReencryptSurveyResponses: re-encrypted survey responses", "count", count)
+	}
+
+	return nil
+}
+
+// ReencryptSurveyResponses encrypts the answers of all responses and response revisions
+// that are in plaintext or encrypted with a previous key with the active encryption key.
+// It returns the number of responses and revisions re-encrypted.
+func (a *UserSurveyApp) ReencryptSurveyResponses() (int, error) {
+	answerCipher, err := a.getAnswerCipher()
+	if err != nil {
+		return 0, errors.Wrap(err, "ReencryptSurveyResponses: failed to get answer cipher")
+	}
+
+	if !answerCipher.IsEnabled() {
+		return 0, nil
+	}
+
+	count := 0
+	err = a.forEachSurvey(func(survey *model.Survey) error {
+		responseCount, err := a.reencryptResponses(answerCipher, survey)
+		count += responseCount
+		if err != nil {
+			return errors.Wrapf(err, "ReencryptSurveyResponses: failed to re-encrypt responses, surveyID: %s", survey.ID)
+		}
+
+		revisionCount, err := a.reencryptRevisions(answerCipher, survey)
+		count += revisionCount
+		if err != nil {
+			return errors.Wrapf(err, "ReencryptSurveyResponses: failed to re-encrypt response revisions, surveyID: %s", survey.ID)
+		}
+
+		return nil
+	})
+
+	return count, err
+}
+
+func (a *UserSurveyApp) reencryptResponses(answerCipher *model.AnswerCipher, survey *model.Survey) (int, error) {
+	count := 0
+	lastResponseID := ""
+
+	for {
+		responses, err := a.store.GetAllResponses(survey.ID, lastResponseID, reencryptPerPage)
+		if err != nil {
+			return count, errors.Wrap(err, "reencryptResponses: failed to get survey responses")
+		}
+
+		for _, response := range responses {
+			answers, err := answerCipher.ReencryptAnswers(response.Response, survey.SurveyQuestions)
+			if err != nil {
+				// the response is left as it is, so its answers aren't lost once the missing key is configured again
+				a.api.LogWarn("reencryptResponses: skipping response that failed to re-encrypt", "responseID", response.ID, "error", err.Error())
+				continue
+			}
+
+			if answers == nil {
+				continue
+			}
+
+			// a response updated since it was read is skipped, and re-encrypted on the next run if needed
+			updated, err := a.store.UpdateSurveyResponseAnswers(response, answers)
+			if err != nil {
+				return count, errors.Wrapf(err, "reencryptResponses: failed to update response answers, responseID: %s", response.ID)
+			}
+
+			if updated {
+				count++
+			}
+		}
+
+		if len(responses) < reencryptPerPage {
+			return count, nil
+		}
+
+		lastResponseID = responses[len(responses)-1].ID
+	}
+}
+
+func (a *UserSurveyApp) reencryptRevisions(answerCipher *model.AnswerCipher, survey *model.Survey) (int, error) {
+	count := 0
+	lastRevisionID := ""
+
+	for {
+		revisions, err := a.store.GetAllResponseRevisions(survey.ID, lastRevisionID, reencryptPerPage)
+		if err != nil {
+			return count, errors.Wrap(err, "reencryptRevisions: failed to get survey response revisions")
+		}
+
+		for _, revision := range revisions {
+			answers, err := answerCipher.ReencryptAnswers(revision.Response, survey.SurveyQuestions)
+			if err != nil {
+				a.api.LogWarn("reencryptRevisions: skipping revision that failed to re-encrypt", "revisionID", revision.ID, "error", err.Error())
+				continue
+			}
+
+			if answers == nil {
+				continue
+			}
+
+			if err := a.store.UpdateSurveyResponseRevisionAnswers(revision.ID, answers); err != nil {
+				return count, errors.Wrapf(err, "reencryptRevisions: failed to update revision answers, revisionID: %s", revision.ID)
+			}
+
+			count++
+		}
+
+		if len(revisions) < reencryptPerPage {
+			return count, nil
+		}
+
+		lastRevisionID = revisions[len(revisions)-1].ID
+	}
+}
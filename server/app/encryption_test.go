@@ -0,0 +1,357 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	mmModal "github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+func testEncryptionKey(b byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), model.EncryptionKeySize)))
+}
+
+func TestAnswerCipher(t *testing.T) {
+	questions := model.SurveyQuestions{
+		Questions: []model.Question{
+			{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale},
+			{ID: "question_id_2", Type: model.QuestionType},
+		},
+	}
+
+	oldKeyEncryption := model.Encryption{
+		Enabled:     true,
+		ActiveKeyID: "key1",
+		Keys:        []model.EncryptionKey{{ID: "key1", Key: testEncryptionKey('a')}},
+	}
+
+	rotatedEncryption := model.Encryption{
+		Enabled:     true,
+		ActiveKeyID: "key2",
+		Keys: []model.EncryptionKey{
+			{ID: "key1", Key: testEncryptionKey('a')},
+			{ID: "key2", Key: testEncryptionKey('b')},
+		},
+	}
+
+	t.Run("should only encrypt text answers and decrypt them back", func(t *testing.T) {
+		answerCipher, err := oldKeyEncryption.NewAnswerCipher()
+		require.NoError(t, err)
+
+		answers := map[string]string{"question_id_1": "9", "question_id_2": "Great product"}
+		encrypted, err := answerCipher.EncryptAnswers(answers, questions)
+		require.NoError(t, err)
+		require.Equal(t, "9", encrypted["question_id_1"])
+		require.True(t, strings.HasPrefix(encrypted["question_id_2"], "enc:v1:key1:"))
+		require.Equal(t, "Great product", answers["question_id_2"])
+
+		decrypted, err := answerCipher.DecryptAnswers(encrypted)
+		require.NoError(t, err)
+		require.Equal(t, answers, decrypted)
+	})
+
+	t.Run("should re-encrypt answers with the rotated key", func(t *testing.T) {
+		oldCipher, err := oldKeyEncryption.NewAnswerCipher()
+		require.NoError(t, err)
+
+		encrypted, err := oldCipher.EncryptAnswers(map[string]string{"question_id_1": "9", "question_id_2": "Great product"}, questions)
+		require.NoError(t, err)
+
+		rotatedCipher, err := rotatedEncryption.NewAnswerCipher()
+		require.NoError(t, err)
+
+		reencrypted, err := rotatedCipher.ReencryptAnswers(encrypted, questions)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(reencrypted["question_id_2"], "enc:v1:key2:"))
+
+		upToDate, err := rotatedCipher.ReencryptAnswers(reencrypted, questions)
+		require.NoError(t, err)
+		require.Nil(t, upToDate)
+
+		decrypted, err := rotatedCipher.DecryptAnswers(reencrypted)
+		require.NoError(t, err)
+		require.Equal(t, "Great product", decrypted["question_id_2"])
+	})
+
+	t.Run("should fail decrypting answers encrypted with a removed key", func(t *testing.T) {
+		oldCipher, err := oldKeyEncryption.NewAnswerCipher()
+		require.NoError(t, err)
+
+		encrypted, err := oldCipher.EncryptAnswers(map[string]string{"question_id_2": "Great product"}, questions)
+		require.NoError(t, err)
+
+		newCipher, err := model.Encryption{
+			Enabled:     true,
+			ActiveKeyID: "key2",
+			Keys:        []model.EncryptionKey{{ID: "key2", Key: testEncryptionKey('b')}},
+		}.NewAnswerCipher()
+		require.NoError(t, err)
+
+		decrypted, err := newCipher.DecryptAnswers(encrypted)
+		require.Error(t, err)
+		require.Empty(t, decrypted)
+	})
+
+	t.Run("should not take answers that look encrypted for encrypted answers", func(t *testing.T) {
+		answerCipher, err := oldKeyEncryption.NewAnswerCipher()
+		require.NoError(t, err)
+
+		disabledCipher, err := model.Encryption{Keys: oldKeyEncryption.Keys}.NewAnswerCipher()
+		require.NoError(t, err)
+
+		answers := map[string]string{"question_id_1": "9", "question_id_2": "enc:v1:key1:bm90IGVuY3J5cHRlZA=="}
+
+		encrypted, err := answerCipher.EncryptAnswers(answers, questions)
+		require.NoError(t, err)
+		require.NotEqual(t, answers["question_id_2"], encrypted["question_id_2"])
+		require.True(t, strings.HasPrefix(encrypted["question_id_2"], "enc:v1:key1:"))
+
+		escaped, err := disabledCipher.EncryptAnswers(answers, questions)
+		require.NoError(t, err)
+		require.Equal(t, "enc:plain:enc:v1:key1:bm90IGVuY3J5cHRlZA==", escaped["question_id_2"])
+
+		for _, stored := range []map[string]string{encrypted, escaped} {
+			decrypted, err := answerCipher.DecryptAnswers(stored)
+			require.NoError(t, err)
+			require.Equal(t, answers, decrypted)
+		}
+	})
+
+	t.Run("should reject invalid keys", func(t *testing.T) {
+		require.Error(t, model.Encryption{Enabled: true, ActiveKeyID: "missing"}.IsValid())
+		require.Error(t, model.Encryption{Keys: []model.EncryptionKey{{ID: "key1", Key: "c2hvcnQ="}}}.IsValid())
+		require.Error(t, model.Encryption{Keys: []model.EncryptionKey{{ID: "key:1", Key: testEncryptionKey('a')}}}.IsValid())
+		require.NoError(t, rotatedEncryption.IsValid())
+	})
+}
+
+func TestCheckEncryptionKeysRemovable(t *testing.T) {
+	encryption := model.Encryption{
+		Enabled:     true,
+		ActiveKeyID: "key2",
+		Keys: []model.EncryptionKey{
+			{ID: "key1", Key: testEncryptionKey('a')},
+			{ID: "key2", Key: testEncryptionKey('b')},
+		},
+	}
+
+	withoutKey1 := model.Encryption{
+		Enabled:     true,
+		ActiveKeyID: "key2",
+		Keys:        []model.EncryptionKey{{ID: "key2", Key: testEncryptionKey('b')}},
+	}
+
+	t.Run("should allow keeping the keys", func(t *testing.T) {
+		th := SetupAppTest(t)
+		th.App.getConfig = func() *model.Config {
+			return &model.Config{Encryption: encryption}
+		}
+
+		require.NoError(t, th.App.CheckEncryptionKeysRemovable(encryption))
+		th.MockedStore.AssertNotCalled(t, "IsEncryptionKeyInUse", mock.Anything)
+	})
+
+	t.Run("should allow removing a key no answers are encrypted with", func(t *testing.T) {
+		th := SetupAppTest(t)
+		th.App.getConfig = func() *model.Config {
+			return &model.Config{Encryption: encryption}
+		}
+		th.MockedStore.On("IsEncryptionKeyInUse", "key1").Return(false, nil)
+
+		require.NoError(t, th.App.CheckEncryptionKeysRemovable(withoutKey1))
+		th.MockedStore.AssertExpectations(t)
+	})
+
+	t.Run("should reject removing a key answers are encrypted with", func(t *testing.T) {
+		th := SetupAppTest(t)
+		th.App.getConfig = func() *model.Config {
+			return &model.Config{Encryption: encryption}
+		}
+		th.MockedStore.On("IsEncryptionKeyInUse", "key1").Return(true, nil)
+
+		err := th.App.CheckEncryptionKeysRemovable(withoutKey1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key1")
+	})
+
+	t.Run("should reject replacing a key answers are encrypted with", func(t *testing.T) {
+		th := SetupAppTest(t)
+		th.App.getConfig = func() *model.Config {
+			return &model.Config{Encryption: encryption}
+		}
+		th.MockedStore.On("IsEncryptionKeyInUse", "key1").Return(true, nil)
+
+		replaced := model.Encryption{
+			Enabled:     true,
+			ActiveKeyID: "key2",
+			Keys: []model.EncryptionKey{
+				{ID: "key1", Key: testEncryptionKey('c')},
+				{ID: "key2", Key: testEncryptionKey('b')},
+			},
+		}
+
+		require.Error(t, th.App.CheckEncryptionKeysRemovable(replaced))
+	})
+
+	t.Run("should reject removing keys when disabling encryption with encrypted answers", func(t *testing.T) {
+		th := SetupAppTest(t)
+		th.App.getConfig = func() *model.Config {
+			return &model.Config{Encryption: encryption}
+		}
+		th.MockedStore.On("IsEncryptionKeyInUse", "key1").Return(false, nil)
+		th.MockedStore.On("IsEncryptionKeyInUse", "key2").Return(true, nil)
+
+		require.Error(t, th.App.CheckEncryptionKeysRemovable(model.Encryption{}))
+	})
+}
+
+func TestReencryptSurveyResponses(t *testing.T) {
+	questions := model.SurveyQuestions{
+		Questions: []model.Question{
+			{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale},
+			{ID: "question_id_2", Type: model.QuestionType},
+		},
+	}
+
+	t.Run("should encrypt plaintext answers and skip up to date ones", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		encryption := model.Encryption{
+			Enabled:     true,
+			ActiveKeyID: "key1",
+			Keys:        []model.EncryptionKey{{ID: "key1", Key: testEncryptionKey('a')}},
+		}
+		th.App.getConfig = func() *model.Config {
+			return &model.Config{Encryption: encryption}
+		}
+
+		answerCipher, err := encryption.NewAnswerCipher()
+		require.NoError(t, err)
+		upToDateAnswers, err := answerCipher.EncryptAnswers(map[string]string{"question_id_1": "7", "question_id_2": "Fine"}, questions)
+		require.NoError(t, err)
+
+		plaintextResponse := &model.SurveyResponse{ID: "response_id_1", Response: map[string]string{"question_id_1": "9", "question_id_2": "Great"}}
+		upToDateResponse := &model.SurveyResponse{ID: "response_id_2", Response: upToDateAnswers}
+		plaintextRevision := &model.SurveyResponseRevision{ID: "revision_id_1", Response: map[string]string{"question_id_1": "8", "question_id_2": "Good"}}
+
+		th.MockedStore.On("GetAllSurveys", "", uint64(allSurveysPerPage)).Return([]*model.Survey{{ID: "survey_id_1", SurveyQuestions: questions}}, nil)
+		th.MockedStore.On("GetAllResponses", "survey_id_1", "", uint64(reencryptPerPage)).Return([]*model.SurveyResponse{plaintextResponse, upToDateResponse}, nil)
+		th.MockedStore.On("GetAllResponseRevisions", "survey_id_1", "", uint64(reencryptPerPage)).Return([]*model.SurveyResponseRevision{plaintextRevision}, nil)
+		th.MockedStore.On("UpdateSurveyResponseAnswers", plaintextResponse, mock.MatchedBy(func(answers map[string]string) bool {
+			return answers["question_id_1"] == "9" && strings.HasPrefix(answers["question_id_2"], "enc:v1:key1:")
+		})).Return(true, nil)
+		th.MockedStore.On("UpdateSurveyResponseRevisionAnswers", "revision_id_1", mock.MatchedBy(func(answers map[string]string) bool {
+			return strings.HasPrefix(answers["question_id_2"], "enc:v1:key1:")
+		})).Return(nil)
+
+		count, err := th.App.ReencryptSurveyResponses()
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+		th.MockedStore.AssertExpectations(t)
+		th.MockedStore.AssertNotCalled(t, "UpdateSurveyResponseAnswers", upToDateResponse, mock.Anything)
+	})
+
+	t.Run("should skip responses that fail to decrypt", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		encryption := model.Encryption{
+			Enabled:     true,
+			ActiveKeyID: "key2",
+			Keys:        []model.EncryptionKey{{ID: "key2", Key: testEncryptionKey('b')}},
+		}
+		th.App.getConfig = func() *model.Config {
+			return &model.Config{Encryption: encryption}
+		}
+
+		removedKeyCipher, err := model.Encryption{
+			Enabled:     true,
+			ActiveKeyID: "key1",
+			Keys:        []model.EncryptionKey{{ID: "key1", Key: testEncryptionKey('a')}},
+		}.NewAnswerCipher()
+		require.NoError(t, err)
+		removedKeyAnswers, err := removedKeyCipher.EncryptAnswers(map[string]string{"question_id_1": "7", "question_id_2": "Fine"}, questions)
+		require.NoError(t, err)
+
+		undecryptableResponse := &model.SurveyResponse{ID: "response_id_1", Response: removedKeyAnswers}
+		plaintextResponse := &model.SurveyResponse{ID: "response_id_2", Response: map[string]string{"question_id_1": "9", "question_id_2": "Great"}}
+
+		th.MockedStore.On("GetAllSurveys", "", uint64(allSurveysPerPage)).Return([]*model.Survey{{ID: "survey_id_1", SurveyQuestions: questions}}, nil)
+		th.MockedStore.On("GetAllResponses", "survey_id_1", "", uint64(reencryptPerPage)).Return([]*model.SurveyResponse{undecryptableResponse, plaintextResponse}, nil)
+		th.MockedStore.On("GetAllResponseRevisions", "survey_id_1", "", uint64(reencryptPerPage)).Return([]*model.SurveyResponseRevision{}, nil)
+		th.MockedStore.On("UpdateSurveyResponseAnswers", plaintextResponse, mock.Anything).Return(true, nil)
+
+		count, err := th.App.ReencryptSurveyResponses()
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+		th.MockedStore.AssertNotCalled(t, "UpdateSurveyResponseAnswers", undecryptableResponse, mock.Anything)
+	})
+
+	t.Run("should do nothing when encryption is disabled", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		count, err := th.App.ReencryptSurveyResponses()
+		require.NoError(t, err)
+		require.Zero(t, count)
+		th.MockedStore.AssertNotCalled(t, "GetAllSurveys", mock.Anything, mock.Anything)
+	})
+}
+
+func TestSaveSurveyResponseEncryption(t *testing.T) {
+	th := SetupAppTest(t)
+	th.App.getConfig = func() *model.Config {
+		return &model.Config{
+			Encryption: model.Encryption{
+				Enabled:     true,
+				ActiveKeyID: "key1",
+				Keys:        []model.EncryptionKey{{ID: "key1", Key: testEncryptionKey('a')}},
+			},
+		}
+	}
+
+	survey := &model.Survey{
+		ID: "survey_id_1",
+		SurveyQuestions: model.SurveyQuestions{
+			Questions: []model.Question{
+				{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale},
+				{ID: "question_id_2", Type: model.QuestionType},
+			},
+		},
+	}
+
+	th.MockedStore.On("GetSurveysByStatus", "in_progress").Return([]*model.Survey{survey}, nil)
+	th.MockedStore.On("GetSurveyDelivery", "user_1", "survey_id_1").Return(&model.SurveyDelivery{PostID: "post_id_1"}, nil)
+	th.MockedStore.On("GetSurveyResponse", "user_1", "survey_id_1").Return(nil, nil)
+	th.MockedStore.On("SaveSurveyResponse", mock.MatchedBy(func(save *model.SurveyResponseSave) bool {
+		return save.Response.Response["question_id_1"] == "10" &&
+			strings.HasPrefix(save.Response.Response["question_id_2"], "enc:v1:key1:")
+	})).Return(nil)
+
+	th.MockedPluginAPI.On("GetPost", "post_id_1").Return(&mmModal.Post{CreateAt: 1000}, nil)
+	th.MockedPluginAPI.On("UpdatePost", mock.MatchedBy(func(post *mmModal.Post) bool {
+		postResponse, ok := post.GetProp("survey_response").(string)
+		return ok && strings.Contains(postResponse, "Great product")
+	})).Return(&mmModal.Post{}, nil)
+	th.MockedPluginAPI.On("GetTeamsForUser", "user_1").Return([]*mmModal.Team{}, nil)
+	th.MockedPluginAPI.On("GetUser", "user_1").Return(&mmModal.User{Id: "user_1"}, nil)
+	th.MockedPluginAPI.On("GetDirectChannel", "user_1", "bot_user_id").Return(&mmModal.Channel{Id: "channel_id"}, nil)
+	th.MockedPluginAPI.On("CreatePost", mock.Anything).Return(&mmModal.Post{}, nil)
+
+	response := &model.SurveyResponse{
+		SurveyID: "survey_id_1",
+		UserID:   "user_1",
+		Response: map[string]string{"question_id_1": "10", "question_id_2": "Great product"},
+	}
+
+	require.NoError(t, th.App.SaveSurveyResponse(response))
+	require.Equal(t, "Great product", response.Response["question_id_2"])
+	th.MockedStore.AssertExpectations(t)
+	th.MockedPluginAPI.AssertExpectations(t)
+}
@@ -77,26 +77,7 @@ func (a *UserSurveyApp) startNewSurveyIfNeeded() error {
 		}
 
 		a.api.LogDebug("JobManageSurveyStatus: determined that the new survey should start")
-		now := mmModal.GetMillis()
-		startTime := config.ParsedTime()
-
-		surveyFromConfig := &model.Survey{
-			ID:              utils.NewID(),
-			FilterTeamIDs:   config.TeamFilter.FilteredTeamIDs,
-			TeamFilterType:  config.TeamFilter.FilterType,
-			CreateAt:        now,
-			UpdateAt:        now,
-			StartTime:       startTime.UnixMilli(),
-			Duration:        config.SurveyExpiry.Days,
-			SurveyQuestions: model.SurveyQuestions{SurveyMessageText: config.SurveyQuestions.SurveyMessageText},
-			Status:          model.SurveyStatusInProgress,
-		}
-
-		for _, question := range config.SurveyQuestions.Questions {
-			if question.Text != "" {
-				surveyFromConfig.SurveyQuestions.Questions = append(surveyFromConfig.SurveyQuestions.Questions, question)
-			}
-		}
+		surveyFromConfig := a.surveyFromConfig(config)
 
 		if surveyFromConfig.IsEqual(endedSurvey) {
 			a.api.LogDebug("JobManageSurveyStatus: not starting new survey as it is the same as latest ended survey")
@@ -127,3 +108,43 @@ func (a *UserSurveyApp) startNewSurveyIfNeeded() error {
 
 	return nil
 }
+
+// surveyFromConfig builds a new in-progress survey from the survey
+// configured in the plugin settings.
+func (a *UserSurveyApp) surveyFromConfig(config *model.Config) *model.Survey {
+	now := mmModal.GetMillis()
+	startTime := config.ParsedTime()
+
+	survey := &model.Survey{
+		ID:             utils.NewID(),
+		FilterTeamIDs:  config.TeamFilter.FilteredTeamIDs,
+		TeamFilterType: config.TeamFilter.FilterType,
+		CreateAt:       now,
+		UpdateAt:       now,
+		StartTime:      startTime.UnixMilli(),
+		Duration:       config.SurveyExpiry.Days,
+		SurveyQuestions: model.SurveyQuestions{
+			SurveyMessageText: config.SurveyQuestions.SurveyMessageText,
+			DefaultLocale:     config.SurveyQuestions.DefaultLocale,
+			Translations:      config.SurveyQuestions.Translations,
+		},
+		Status:            model.SurveyStatusInProgress,
+		Customization:     config.Customization,
+		Anonymity:         config.Anonymity,
+		ResponsesEditable: config.ResponsesEditable,
+	}
+
+	for _, question := range config.SurveyQuestions.Questions {
+		if question.Text != "" {
+			survey.SurveyQuestions.Questions = append(survey.SurveyQuestions.Questions, question)
+		}
+	}
+
+	return survey
+}
+
+// GetConfiguredSurveyPreview returns the survey that would be started
+// from the current plugin settings, without saving it.
+func (a *UserSurveyApp) GetConfiguredSurveyPreview() *model.Survey {
+	return a.surveyFromConfig(a.getConfig())
+}
@@ -127,3 +127,42 @@ func TestJobManageSurveyStatus(t *testing.T) {
 		th.MockedStore.AssertExpectations(t)
 	})
 }
+
+func TestGetConfiguredSurveyPreview(t *testing.T) {
+	t.Run("should build survey from config and skip empty questions", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		th.App.getConfig = func() *model.Config {
+			return &model.Config{
+				SurveyExpiry: model.SurveyExpiry{
+					Days: 10,
+				},
+				SurveyDateTime: model.SurveyDateTime{
+					Timestamp: 1138792800000, // 02/01/2006 15:04
+				},
+				SurveyQuestions: model.SurveyQuestions{
+					SurveyMessageText: "Survey message",
+					Questions: []model.Question{
+						{ID: "question_1", Text: "Foo", Type: "text"},
+						{ID: "question_2", Text: "", Type: "text"},
+					},
+					DefaultLocale: "en",
+					Translations: map[string]model.SurveyTranslation{
+						"de": {SurveyMessageText: "Umfragenachricht"},
+					},
+				},
+			}
+		}
+
+		survey := th.App.GetConfiguredSurveyPreview()
+		require.Equal(t, int64(1138792800000), survey.StartTime)
+		require.Equal(t, 10, survey.Duration)
+		require.Equal(t, "Survey message", survey.SurveyQuestions.SurveyMessageText)
+		require.Len(t, survey.SurveyQuestions.Questions, 1)
+		require.Equal(t, "question_1", survey.SurveyQuestions.Questions[0].ID)
+		require.Equal(t, "en", survey.SurveyQuestions.DefaultLocale)
+		require.Equal(t, "Umfragenachricht", survey.SurveyQuestions.Translations["de"].SurveyMessageText)
+
+		th.MockedStore.AssertNotCalled(t, "SaveSurvey", mock.Anything)
+	})
+}
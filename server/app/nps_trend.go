@@ -0,0 +1,66 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+// GetNPSTrend returns a page of the NPS, response rate and rating group counts
+// of the ended surveys, oldest survey first, for charting them over time.
+func (a *UserSurveyApp) GetNPSTrend(filter model.NPSTrendFilter) (*model.NPSTrend, error) {
+	if filter.Page < 0 {
+		filter.Page = 0
+	}
+
+	if filter.PerPage <= 0 {
+		filter.PerPage = model.DefaultNPSTrendPerPage
+	} else if filter.PerPage > model.MaxNPSTrendPerPage {
+		filter.PerPage = model.MaxNPSTrendPerPage
+	}
+
+	// one more survey than the page holds is fetched to tell whether there's a next page,
+	// along with the survey before the page to compare the page's first survey with
+	offset := uint64(filter.Page * filter.PerPage)
+	limit := uint64(filter.PerPage + 1)
+	if offset > 0 {
+		offset--
+		limit++
+	}
+
+	surveyStats, err := a.store.GetEndedSurveyStats(filter.QuestionID, offset, limit)
+	if err != nil {
+		return nil, errors.Wrapf(err, "GetNPSTrend: failed to get ended survey stats, questionID: %s, page: %d", filter.QuestionID, filter.Page)
+	}
+
+	for _, surveyStat := range surveyStats {
+		surveyStat.WithholdUnreportableResults()
+	}
+
+	var previous *model.SurveyStat
+	if filter.Page > 0 && len(surveyStats) > 0 {
+		previous = surveyStats[0]
+		surveyStats = surveyStats[1:]
+	}
+
+	trend := &model.NPSTrend{
+		Page:    filter.Page,
+		PerPage: filter.PerPage,
+		Points:  []model.NPSTrendPoint{},
+	}
+
+	if len(surveyStats) > filter.PerPage {
+		trend.HasMore = true
+		surveyStats = surveyStats[:filter.PerPage]
+	}
+
+	for _, surveyStat := range surveyStats {
+		trend.Points = append(trend.Points, surveyStat.ToNPSTrendPoint(previous))
+		previous = surveyStat
+	}
+
+	return trend, nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+func TestGetNPSTrend(t *testing.T) {
+	t.Run("should return a page of trend points and tell there are more", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		surveyStats := []*model.SurveyStat{
+			{
+				Survey:         model.Survey{ID: "survey_id_0", StartTime: 500, Duration: 1},
+				ReceiptCount:   1000,
+				ResponseCount:  400,
+				PromoterCount:  100,
+				PassiveCount:   100,
+				DetractorCount: 200,
+			},
+			{
+				Survey:         model.Survey{ID: "survey_id_1", StartTime: 1000, Duration: 1},
+				ReceiptCount:   1000,
+				ResponseCount:  400,
+				PromoterCount:  200,
+				PassiveCount:   100,
+				DetractorCount: 100,
+			},
+			{
+				Survey:        model.Survey{ID: "survey_id_2", StartTime: 2000, Duration: 1, Anonymity: model.Anonymity{Enabled: true}},
+				ReceiptCount:  10,
+				ResponseCount: 2,
+				PromoterCount: 2,
+			},
+			{Survey: model.Survey{ID: "survey_id_3", StartTime: 3000, Duration: 1}},
+		}
+
+		// the survey before the page is fetched to compare the page's first survey with
+		th.MockedStore.On("GetEndedSurveyStats", "question_id_1", uint64(1), uint64(4)).Return(surveyStats, nil)
+
+		trend, err := th.App.GetNPSTrend(model.NPSTrendFilter{QuestionID: "question_id_1", Page: 1, PerPage: 2})
+		require.NoError(t, err)
+		require.True(t, trend.HasMore)
+		require.Len(t, trend.Points, 2)
+
+		require.Equal(t, "survey_id_1", trend.Points[0].SurveyID)
+		require.Equal(t, int64(1000+24*60*60*1000), trend.Points[0].EndTime)
+		require.Equal(t, 40.0, trend.Points[0].ResponseRate)
+		require.NotNil(t, trend.Points[0].NPS)
+		require.Equal(t, 25.0, *trend.Points[0].NPS)
+		require.NotNil(t, trend.Points[0].NPSConfidence)
+		require.NotNil(t, trend.Points[0].ChangeFromPrevious)
+		require.Equal(t, 50.0, trend.Points[0].ChangeFromPrevious.Difference)
+		require.True(t, trend.Points[0].ChangeFromPrevious.Significant)
+
+		require.True(t, trend.Points[1].ResultsWithheld)
+		require.Nil(t, trend.Points[1].NPS)
+		require.Zero(t, trend.Points[1].PromoterCount)
+		require.Nil(t, trend.Points[1].ChangeFromPrevious)
+		require.Equal(t, 20.0, trend.Points[1].ResponseRate)
+	})
+
+	t.Run("should use the default page size and tell there are no more", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		th.MockedStore.On("GetEndedSurveyStats", "", uint64(0), uint64(model.DefaultNPSTrendPerPage+1)).Return([]*model.SurveyStat{}, nil)
+
+		trend, err := th.App.GetNPSTrend(model.NPSTrendFilter{})
+		require.NoError(t, err)
+		require.False(t, trend.HasMore)
+		require.Empty(t, trend.Points)
+		require.Equal(t, model.DefaultNPSTrendPerPage, trend.PerPage)
+	})
+}
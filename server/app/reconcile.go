@@ -0,0 +1,168 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+	"github.com/mattermost/mattermost-plugin-user-survey/server/utils"
+)
+
+// JobReconcileSurveyCounts is a scheduled job that fixes survey counters that drifted.
+func (a *UserSurveyApp) JobReconcileSurveyCounts() error {
+	a.api.LogDebug("JobReconcileSurveyCounts: running")
+
+	if _, err := a.ReconcileSurveyCounts(); err != nil {
+		a.api.LogError("JobReconcileSurveyCounts: failed to reconcile survey counts", "error", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// ReconcileSurveyCounts recomputes the counters of all surveys from the survey
+// deliveries and responses, fixing the counters that drifted. The question answer
+// and segment counts are checked along the way, and rebuilt if they drifted.
+// It returns the reconciliation of the surveys whose counters had discrepancies.
+func (a *UserSurveyApp) ReconcileSurveyCounts() ([]*model.SurveyCountsReconciliation, error) {
+	// the receipt and opened counts are recomputed from the survey deliveries, which
+	// can't be done until the deliveries in the KV store are backfilled into their table
+	deliveriesBackfilled, err := a.areSurveyDeliveriesBackfilled()
+	if err != nil {
+		return nil, errors.Wrap(err, "ReconcileSurveyCounts: failed to get survey deliveries backfill status")
+	}
+
+	if !deliveriesBackfilled {
+		a.api.LogInfo("ReconcileSurveyCounts: survey deliveries aren't backfilled yet, skipping the receipt and opened counts")
+	}
+
+	reconciliations := []*model.SurveyCountsReconciliation{}
+	err = a.forEachSurvey(func(survey *model.Survey) error {
+		rebuilt, err := a.store.ReconcileQuestionAnswerCounts(survey.ID, survey.SurveyQuestions.Questions)
+		if err != nil {
+			return errors.Wrapf(err, "ReconcileSurveyCounts: failed to reconcile question answer counts, surveyID: %s", survey.ID)
+		}
+
+		if rebuilt {
+			a.api.LogWarn("ReconcileSurveyCounts: rebuilt question answer counts that drifted", "surveyID", survey.ID)
+		}
+
+		// surveys without a rating question only have their sentiment scores segmented
+		ratingQuestion, ratingErr := survey.GetSystemRatingQuestion()
+
+		rebuilt, err = a.store.ReconcileSegmentCounts(survey.ID, ratingQuestion)
+		if err != nil {
+			return errors.Wrapf(err, "ReconcileSurveyCounts: failed to reconcile segment counts, surveyID: %s", survey.ID)
+		}
+
+		if rebuilt {
+			a.api.LogWarn("ReconcileSurveyCounts: rebuilt segment counts that drifted", "surveyID", survey.ID)
+		}
+
+		if ratingErr != nil {
+			a.api.LogWarn("ReconcileSurveyCounts: survey has no system rating question, skipping it", "surveyID", survey.ID, "error", ratingErr.Error())
+			return nil
+		}
+
+		reconciliation, err := a.store.ReconcileSurveyCounts(survey.ID, ratingQuestion, deliveriesBackfilled)
+		if err != nil {
+			return errors.Wrapf(err, "ReconcileSurveyCounts: failed to reconcile survey counts, surveyID: %s", survey.ID)
+		}
+
+		if reconciliation.HasDiscrepancies() {
+			a.api.LogWarn(
+				"ReconcileSurveyCounts: fixed survey counts that drifted",
+				"surveyID", survey.ID,
+				"stored", fmt.Sprintf("%+v", reconciliation.Stored),
+				"actual", fmt.Sprintf("%+v", reconciliation.Actual),
+			)
+			reconciliations = append(reconciliations, reconciliation)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reconciliations, nil
+}
+
+// BackfillQuestionAnswerCounts builds the question answer counts of the surveys
+// whose responses were saved before the counts were kept up to date on save.
+// It only runs once, subsequent calls are no-op.
+func (a *UserSurveyApp) BackfillQuestionAnswerCounts() error {
+	backfilled, appErr := a.api.KVGet(utils.KeyQuestionAnswerCountsBackfilled)
+	if appErr != nil {
+		a.api.LogError("BackfillQuestionAnswerCounts: failed to get backfill status from KV store", "error", appErr.Error())
+		return errors.Wrap(errors.New(appErr.Error()), "BackfillQuestionAnswerCounts: failed to get backfill status from KV store")
+	}
+
+	if string(backfilled) == "true" {
+		return nil
+	}
+
+	surveyCount := 0
+	err := a.forEachSurvey(func(survey *model.Survey) error {
+		if err := a.store.RebuildQuestionAnswerCounts(survey.ID, survey.SurveyQuestions.Questions); err != nil {
+			return errors.Wrapf(err, "BackfillQuestionAnswerCounts: failed to build question answer counts, surveyID: %s", survey.ID)
+		}
+
+		surveyCount++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if appErr := a.api.KVSet(utils.KeyQuestionAnswerCountsBackfilled, []byte("true")); appErr != nil {
+		a.api.LogError("BackfillQuestionAnswerCounts: failed to save backfill status in KV store", "error", appErr.Error())
+		return errors.Wrap(errors.New(appErr.Error()), "BackfillQuestionAnswerCounts: failed to save backfill status in KV store")
+	}
+
+	a.api.LogInfo("BackfillQuestionAnswerCounts: built question answer counts", "surveys", surveyCount)
+	return nil
+}
+
+// BackfillSegmentCounts builds the segment counts of the surveys whose responses
+// were saved before the counts were kept up to date on save.
+// It only runs once, subsequent calls are no-op.
+func (a *UserSurveyApp) BackfillSegmentCounts() error {
+	backfilled, appErr := a.api.KVGet(utils.KeySegmentCountsBackfilled)
+	if appErr != nil {
+		a.api.LogError("BackfillSegmentCounts: failed to get backfill status from KV store", "error", appErr.Error())
+		return errors.Wrap(errors.New(appErr.Error()), "BackfillSegmentCounts: failed to get backfill status from KV store")
+	}
+
+	if string(backfilled) == "true" {
+		return nil
+	}
+
+	surveyCount := 0
+	err := a.forEachSurvey(func(survey *model.Survey) error {
+		// surveys without a rating question are counted with an empty question, which has no answers
+		ratingQuestion, _ := survey.GetSystemRatingQuestion()
+
+		if err := a.store.RebuildSegmentCounts(survey.ID, ratingQuestion); err != nil {
+			return errors.Wrapf(err, "BackfillSegmentCounts: failed to build segment counts, surveyID: %s", survey.ID)
+		}
+
+		surveyCount++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if appErr := a.api.KVSet(utils.KeySegmentCountsBackfilled, []byte("true")); appErr != nil {
+		a.api.LogError("BackfillSegmentCounts: failed to save backfill status in KV store", "error", appErr.Error())
+		return errors.Wrap(errors.New(appErr.Error()), "BackfillSegmentCounts: failed to save backfill status in KV store")
+	}
+
+	a.api.LogInfo("BackfillSegmentCounts: built segment counts", "surveys", surveyCount)
+	return nil
+}
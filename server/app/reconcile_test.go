@@ -0,0 +1,280 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+func TestReconcileSurveyCounts(t *testing.T) {
+	th := SetupAppTest(t)
+
+	questions := model.SurveyQuestions{
+		Questions: []model.Question{
+			{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale},
+		},
+	}
+
+	th.MockedStore.On("GetAllSurveys", "", uint64(allSurveysPerPage)).Return([]*model.Survey{
+		{ID: "surveyid1", SurveyQuestions: questions},
+		{ID: "surveyid2", SurveyQuestions: questions},
+	}, nil)
+
+	th.MockedStore.On("ReconcileQuestionAnswerCounts", "surveyid1", questions.Questions).Return(true, nil)
+	th.MockedStore.On("ReconcileQuestionAnswerCounts", "surveyid2", questions.Questions).Return(false, nil)
+	th.MockedStore.On("ReconcileSegmentCounts", "surveyid1", questions.Questions[0]).Return(false, nil)
+	th.MockedStore.On("ReconcileSegmentCounts", "surveyid2", questions.Questions[0]).Return(true, nil)
+
+	drifted := &model.SurveyCountsReconciliation{
+		SurveyID: "surveyid1",
+		Stored:   model.SurveyCounts{Receipts: 3, Opened: 1, Responses: 1},
+		Actual:   model.SurveyCounts{Receipts: 2, Opened: 1, Responses: 1},
+	}
+	th.MockedStore.On("ReconcileSurveyCounts", "surveyid1", questions.Questions[0], true).Return(drifted, nil)
+	th.MockedStore.On("ReconcileSurveyCounts", "surveyid2", questions.Questions[0], true).Return(&model.SurveyCountsReconciliation{
+		SurveyID: "surveyid2",
+		Stored:   model.SurveyCounts{Receipts: 1},
+		Actual:   model.SurveyCounts{Receipts: 1},
+	}, nil)
+
+	reconciliations, err := th.App.ReconcileSurveyCounts()
+	require.NoError(t, err)
+	require.Equal(t, []*model.SurveyCountsReconciliation{drifted}, reconciliations)
+	th.MockedStore.AssertExpectations(t)
+}
+
+func TestReconcileSurveyCountsBeforeDeliveriesBackfill(t *testing.T) {
+	th := SetupAppTest(t)
+	th.App.surveyDeliveriesBackfilled.Store(false)
+
+	questions := []model.Question{{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale}}
+
+	th.MockedPluginAPI.On("KVGet", "survey_deliveries_backfilled").Return(nil, nil)
+	th.MockedStore.On("GetAllSurveys", "", uint64(allSurveysPerPage)).Return([]*model.Survey{
+		{ID: "surveyid1", SurveyQuestions: model.SurveyQuestions{Questions: questions}},
+	}, nil)
+	th.MockedStore.On("ReconcileQuestionAnswerCounts", "surveyid1", questions).Return(false, nil)
+	th.MockedStore.On("ReconcileSegmentCounts", "surveyid1", questions[0]).Return(false, nil)
+
+	// the receipt and opened counts are kept until the deliveries are backfilled
+	th.MockedStore.On("ReconcileSurveyCounts", "surveyid1", questions[0], false).Return(&model.SurveyCountsReconciliation{
+		SurveyID: "surveyid1",
+		Stored:   model.SurveyCounts{Receipts: 3},
+		Actual:   model.SurveyCounts{Receipts: 3},
+	}, nil)
+
+	reconciliations, err := th.App.ReconcileSurveyCounts()
+	require.NoError(t, err)
+	require.Empty(t, reconciliations)
+	th.MockedStore.AssertExpectations(t)
+}
+
+func TestSurveyCountsAddResponse(t *testing.T) {
+	t.Run("should group ratings on the NPS scale by default", func(t *testing.T) {
+		var counts model.SurveyCounts
+		ratingQuestion := model.Question{ID: "rating", Type: model.QuestionTypeLinearScale}
+
+		counts.AddResponse(&model.SurveyResponse{Response: map[string]string{"rating": "10"}, ResponseType: model.ResponseTypeComplete}, ratingQuestion)
+		counts.AddResponse(&model.SurveyResponse{Response: map[string]string{"rating": "7"}, ResponseType: model.ResponseTypePartial}, ratingQuestion)
+		counts.AddResponse(&model.SurveyResponse{Response: map[string]string{"rating": "3"}, ResponseType: model.ResponseTypeComplete}, ratingQuestion)
+		counts.AddResponse(&model.SurveyResponse{Response: map[string]string{"rating": "invalid"}, ResponseType: model.ResponseTypePartial}, ratingQuestion)
+
+		require.Equal(t, model.SurveyCounts{Responses: 4, Completed: 2, Promoters: 1, Passives: 1, Detractors: 1}, counts)
+	})
+
+	t.Run("should group ratings on the question's scale", func(t *testing.T) {
+		var counts model.SurveyCounts
+		ratingQuestion := model.Question{
+			ID:    "rating",
+			Type:  model.QuestionTypeLinearScale,
+			Scale: &model.RatingScale{Min: 1, Max: 5, DetractorMax: 2, PassiveMax: 3, ScoreType: model.RatingScoreTypeTopGroup},
+		}
+
+		for _, rating := range []string{"5", "4", "3", "2", "9"} {
+			counts.AddResponse(&model.SurveyResponse{Response: map[string]string{"rating": rating}, ResponseType: model.ResponseTypeComplete}, ratingQuestion)
+		}
+
+		// ratings outside of the scale aren't grouped
+		require.Equal(t, model.SurveyCounts{Responses: 5, Completed: 5, Promoters: 2, Passives: 1, Detractors: 1}, counts)
+	})
+}
+
+func TestBackfillQuestionAnswerCounts(t *testing.T) {
+	t.Run("should build the question answer counts of all surveys", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		questions := []model.Question{{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale}}
+
+		th.MockedPluginAPI.On("KVGet", "question_answer_counts_backfilled").Return(nil, nil)
+		th.MockedStore.On("GetAllSurveys", "", uint64(allSurveysPerPage)).Return([]*model.Survey{
+			{ID: "surveyid1", SurveyQuestions: model.SurveyQuestions{Questions: questions}},
+			{ID: "surveyid2"},
+		}, nil)
+		th.MockedStore.On("RebuildQuestionAnswerCounts", "surveyid1", questions).Return(nil)
+		th.MockedStore.On("RebuildQuestionAnswerCounts", "surveyid2", []model.Question(nil)).Return(nil)
+		th.MockedPluginAPI.On("KVSet", "question_answer_counts_backfilled", []byte("true")).Return(nil)
+
+		require.NoError(t, th.App.BackfillQuestionAnswerCounts())
+		th.MockedStore.AssertExpectations(t)
+		th.MockedPluginAPI.AssertExpectations(t)
+	})
+
+	t.Run("should not build the question answer counts twice", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		th.MockedPluginAPI.On("KVGet", "question_answer_counts_backfilled").Return([]byte("true"), nil)
+
+		require.NoError(t, th.App.BackfillQuestionAnswerCounts())
+		th.MockedStore.AssertNotCalled(t, "GetAllSurveys", mock.Anything, mock.Anything)
+	})
+}
+
+func TestReconcileSurveyCountsWithoutRatingQuestion(t *testing.T) {
+	th := SetupAppTest(t)
+
+	th.MockedStore.On("GetAllSurveys", "", uint64(allSurveysPerPage)).Return([]*model.Survey{{ID: "surveyid1"}}, nil)
+	th.MockedStore.On("ReconcileQuestionAnswerCounts", "surveyid1", []model.Question(nil)).Return(false, nil)
+
+	// the sentiment scores are still segmented, while the survey counts are skipped
+	th.MockedStore.On("ReconcileSegmentCounts", "surveyid1", model.Question{}).Return(false, nil)
+
+	reconciliations, err := th.App.ReconcileSurveyCounts()
+	require.NoError(t, err)
+	require.Empty(t, reconciliations)
+	th.MockedStore.AssertExpectations(t)
+	th.MockedStore.AssertNotCalled(t, "ReconcileSurveyCounts", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBackfillSegmentCounts(t *testing.T) {
+	t.Run("should build the segment counts of all surveys", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		questions := []model.Question{{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale}}
+
+		th.MockedPluginAPI.On("KVGet", "segment_counts_backfilled").Return(nil, nil)
+		th.MockedStore.On("GetAllSurveys", "", uint64(allSurveysPerPage)).Return([]*model.Survey{
+			{ID: "surveyid1", SurveyQuestions: model.SurveyQuestions{Questions: questions}},
+			{ID: "surveyid2"},
+		}, nil)
+		th.MockedStore.On("RebuildSegmentCounts", "surveyid1", questions[0]).Return(nil)
+		th.MockedStore.On("RebuildSegmentCounts", "surveyid2", model.Question{}).Return(nil)
+		th.MockedPluginAPI.On("KVSet", "segment_counts_backfilled", []byte("true")).Return(nil)
+
+		require.NoError(t, th.App.BackfillSegmentCounts())
+		th.MockedStore.AssertExpectations(t)
+		th.MockedPluginAPI.AssertExpectations(t)
+	})
+
+	t.Run("should not build the segment counts twice", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		th.MockedPluginAPI.On("KVGet", "segment_counts_backfilled").Return([]byte("true"), nil)
+
+		require.NoError(t, th.App.BackfillSegmentCounts())
+		th.MockedStore.AssertNotCalled(t, "GetAllSurveys", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetSegmentCountDeltas(t *testing.T) {
+	survey := &model.Survey{
+		SurveyQuestions: model.SurveyQuestions{
+			Questions: []model.Question{{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale}},
+		},
+	}
+
+	teamKey := model.SegmentKey{Type: model.SegmentTypeTeam, ID: "team_a"}
+	overallKey := model.SegmentKey{Type: model.SegmentTypeOverall}
+	promotersKey := model.SegmentKey{Type: model.SegmentTypeRatingGroup, ID: model.RatingGroupPromoters}
+	detractorsKey := model.SegmentKey{Type: model.SegmentTypeRatingGroup, ID: model.RatingGroupDetractors}
+
+	t.Run("should count a new response in its segments", func(t *testing.T) {
+		response := newTestSentimentResponse("response_id_1", "9", map[string]float64{"question_id_2": 0.8, "question_id_3": -0.25}, testTeamA)
+
+		require.Equal(t, []model.SegmentCountsDelta{
+			{SegmentKey: overallKey, SegmentCounts: model.SegmentCounts{SentimentRespondents: 1, SentimentAnswers: 2, SentimentTotal: 550}},
+			{SegmentKey: promotersKey, SegmentCounts: model.SegmentCounts{SentimentRespondents: 1, SentimentAnswers: 2, SentimentTotal: 550}},
+			{SegmentKey: teamKey, SegmentCounts: model.SegmentCounts{
+				TeamName:             "Team A",
+				Promoters:            1,
+				SentimentRespondents: 1,
+				SentimentAnswers:     2,
+				SentimentTotal:       550,
+			}},
+		}, model.GetSegmentCountDeltas(survey, nil, response))
+	})
+
+	t.Run("should move a replaced response between rating groups", func(t *testing.T) {
+		oldResponse := newTestSentimentResponse("response_id_1", "9", map[string]float64{"question_id_2": 0.8}, testTeamA)
+		newResponse := newTestSentimentResponse("response_id_1", "2", map[string]float64{"question_id_2": 0.8}, testTeamA)
+
+		// the team's name is kept up to date even though its sentiment is unchanged
+		require.Equal(t, []model.SegmentCountsDelta{
+			{SegmentKey: detractorsKey, SegmentCounts: model.SegmentCounts{SentimentRespondents: 1, SentimentAnswers: 1, SentimentTotal: 800}},
+			{SegmentKey: promotersKey, SegmentCounts: model.SegmentCounts{SentimentRespondents: -1, SentimentAnswers: -1, SentimentTotal: -800}},
+			{SegmentKey: teamKey, SegmentCounts: model.SegmentCounts{TeamName: "Team A", Promoters: -1, Detractors: 1}},
+		}, model.GetSegmentCountDeltas(survey, oldResponse, newResponse))
+	})
+
+	t.Run("should discount a withdrawn response without changing team names", func(t *testing.T) {
+		response := newTestResponse("response_id_1", "7", testTeamA)
+
+		require.Equal(t, []model.SegmentCountsDelta{
+			{SegmentKey: teamKey, SegmentCounts: model.SegmentCounts{Passives: -1}},
+		}, model.GetSegmentCountDeltas(survey, response, nil))
+	})
+}
+
+func TestGetQuestionAnswerCountDeltas(t *testing.T) {
+	questions := []model.Question{
+		{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale},
+		{ID: "question_id_2", Type: model.QuestionType},
+		{ID: "question_id_3", Type: model.QuestionTypeLinearScale},
+	}
+
+	key := func(questionID, answer string) model.QuestionAnswerKey {
+		return model.QuestionAnswerKey{QuestionID: questionID, Answer: answer}
+	}
+
+	t.Run("should count the answers of a new response, text answers without their text", func(t *testing.T) {
+		response := &model.SurveyResponse{Response: map[string]string{"question_id_1": "9", "question_id_2": "Great", "question_id_3": ""}}
+
+		require.Equal(t, []model.QuestionAnswerCountDelta{
+			{QuestionAnswerKey: key("question_id_1", "9"), Delta: 1},
+			{QuestionAnswerKey: key("question_id_2", ""), Delta: 1},
+		}, model.GetQuestionAnswerCountDeltas(questions, nil, response))
+	})
+
+	t.Run("should move the changed answers of a replaced response", func(t *testing.T) {
+		oldResponse := &model.SurveyResponse{Response: map[string]string{"question_id_1": "9", "question_id_2": "encrypted:key:abc", "question_id_3": "7"}}
+		newResponse := &model.SurveyResponse{Response: map[string]string{"question_id_1": "4", "question_id_2": "Slow", "question_id_3": "7"}}
+
+		require.Equal(t, []model.QuestionAnswerCountDelta{
+			{QuestionAnswerKey: key("question_id_1", "4"), Delta: 1},
+			{QuestionAnswerKey: key("question_id_1", "9"), Delta: -1},
+		}, model.GetQuestionAnswerCountDeltas(questions, oldResponse, newResponse))
+	})
+
+	t.Run("should discount the answers of a withdrawn response", func(t *testing.T) {
+		response := &model.SurveyResponse{Response: map[string]string{"question_id_3": "3"}}
+
+		require.Equal(t, []model.QuestionAnswerCountDelta{
+			{QuestionAnswerKey: key("question_id_3", "3"), Delta: -1},
+		}, model.GetQuestionAnswerCountDeltas(questions, response, nil))
+	})
+}
+
+func TestQuestionAnswerCountsEqual(t *testing.T) {
+	key := model.QuestionAnswerKey{QuestionID: "question_id_1", Answer: "9"}
+	otherKey := model.QuestionAnswerKey{QuestionID: "question_id_1", Answer: "4"}
+
+	require.True(t, model.QuestionAnswerCounts{key: 2}.Equal(model.QuestionAnswerCounts{key: 2}))
+	require.True(t, model.QuestionAnswerCounts{key: 2, otherKey: 0}.Equal(model.QuestionAnswerCounts{key: 2}))
+	require.False(t, model.QuestionAnswerCounts{key: 2}.Equal(model.QuestionAnswerCounts{key: 1}))
+	require.False(t, model.QuestionAnswerCounts{key: 2}.Equal(model.QuestionAnswerCounts{key: 2, otherKey: 1}))
+}
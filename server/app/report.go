@@ -8,9 +8,12 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path"
+	"path/filepath"
 
+	mmModel "github.com/mattermost/mattermost/server/public/model"
 	"github.com/pkg/errors"
 
 	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
@@ -19,6 +22,8 @@ import (
 
 const (
 	rawResponsePerPage = 500
+
+	reportPostMessageTranslationID = "app.report.post_message"
 )
 
 func (a *UserSurveyApp) GenerateSurveyReport(userID, surveyID string) (*os.File, error) {
@@ -36,12 +41,81 @@ func (a *UserSurveyApp) GenerateSurveyReport(userID, surveyID string) (*os.File,
 	return file, nil
 }
 
+// SendSurveyReportToUser generates the survey report and posts it
+// as an attachment in the user's DM with the survey bot.
+func (a *UserSurveyApp) SendSurveyReportToUser(userID, surveyID string) error {
+	file, err := a.GenerateSurveyReport(userID, surveyID)
+	if err != nil {
+		return errors.Wrap(err, "SendSurveyReportToUser: failed to generate survey report")
+	}
+
+	defer func() {
+		_ = file.Close()
+
+		// the report is no longer needed locally once it's uploaded
+		reportDir := filepath.Dir(file.Name())
+		if err := os.RemoveAll(reportDir); err != nil {
+			a.api.LogWarn("SendSurveyReportToUser: failed to delete report dir", "reportDir", reportDir, "error", err.Error())
+		}
+	}()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		a.api.LogError("SendSurveyReportToUser: failed to read survey report file", "filePath", file.Name(), "error", err.Error())
+		return errors.Wrapf(err, "SendSurveyReportToUser: failed to read survey report file, filePath: %s", file.Name())
+	}
+
+	user, appErr := a.api.GetUser(userID)
+	if appErr != nil {
+		a.api.LogError("SendSurveyReportToUser: failed to get user from ID", "userID", userID, "error", appErr.Error())
+		return errors.Wrap(appErr, "SendSurveyReportToUser: failed to get user from ID: "+userID)
+	}
+
+	botUserDM, appErr := a.api.GetDirectChannel(userID, a.botID)
+	if appErr != nil {
+		errMsg := fmt.Sprintf("SendSurveyReportToUser: failed to create DM between survey bot and user, botID: %s, userID: %s, error: %s", a.botID, userID, appErr.Error())
+		a.api.LogError(errMsg)
+		return errors.Wrap(errors.New(appErr.Error()), errMsg)
+	}
+
+	fileInfo, appErr := a.api.UploadFile(data, botUserDM.Id, fmt.Sprintf("survey_report_%s.zip", surveyID))
+	if appErr != nil {
+		a.api.LogError("SendSurveyReportToUser: failed to upload survey report file", "surveyID", surveyID, "error", appErr.Error())
+		return errors.Wrap(errors.New(appErr.Error()), "SendSurveyReportToUser: failed to upload survey report file")
+	}
+
+	post := &mmModel.Post{
+		UserId:    a.botID,
+		ChannelId: botUserDM.Id,
+		Message:   a.i18n.GetUserTranslations(user.Locale)(reportPostMessageTranslationID, map[string]interface{}{"SurveyID": surveyID}),
+		FileIds:   []string{fileInfo.Id},
+	}
+
+	if _, appErr := a.api.CreatePost(post); appErr != nil {
+		a.api.LogError("SendSurveyReportToUser: failed to create survey report post", "userID", userID, "surveyID", surveyID, "error", appErr.Error())
+		return errors.Wrap(errors.New(appErr.Error()), "SendSurveyReportToUser: failed to create survey report post")
+	}
+
+	return nil
+}
+
 func (a *UserSurveyApp) generateSurveyReport(surveyID string) (string, error) {
 	survey, err := a.store.GetSurveysByID(surveyID)
 	if err != nil {
 		return "", errors.Wrapf(err, "generateSurveyReport: failed to get survey by ID, surveyID: %s", surveyID)
 	}
 
+	if survey.Anonymity.Enabled {
+		surveyStat, err := a.store.GetSurveyStat(surveyID)
+		if err != nil {
+			return "", errors.Wrapf(err, "generateSurveyReport: failed to get survey stat, surveyID: %s", surveyID)
+		}
+
+		if surveyStat == nil || !survey.Anonymity.IsGroupReportable(surveyStat.ResponseCount) {
+			return "", ErrSurveyResultsWithheld
+		}
+	}
+
 	key := utils.NewID()
 
 	rawResponseCSVFilePath, err := a.generateRawResponseCSV(survey, key)
@@ -59,8 +133,34 @@ func (a *UserSurveyApp) generateSurveyReport(surveyID string) (string, error) {
 		return "", err
 	}
 
-	zipPath := path.Join(os.TempDir(), "survey_report", key, "survey_report.zip")
 	files := []string{rawResponseCSVFilePath, surveyMetadataFilePath, serverMetadataFilePath}
+
+	// anonymous surveys have no team breakdown as the respondents' teams aren't captured
+	if !survey.Anonymity.Enabled {
+		teamNPSCSVFilePath, err := a.generateTeamNPSCSV(survey, key)
+		if err != nil {
+			return "", err
+		}
+
+		files = append(files, teamNPSCSVFilePath)
+	}
+
+	// text analytics are only available once the survey ended and was analyzed
+	textAnalytics, err := a.store.GetSurveyTextAnalytics(surveyID)
+	if err != nil {
+		return "", errors.Wrapf(err, "generateSurveyReport: failed to get survey text analytics, surveyID: %s", surveyID)
+	}
+
+	if textAnalytics != nil {
+		textAnalyticsCSVFilePath, err := a.generateTextAnalyticsCSV(textAnalytics, key)
+		if err != nil {
+			return "", err
+		}
+
+		files = append(files, textAnalyticsCSVFilePath)
+	}
+
+	zipPath := path.Join(os.TempDir(), "survey_report", key, "survey_report.zip")
 	err = utils.CreateZip(zipPath, files)
 	if err != nil {
 		a.api.LogError("generateSurveyReport: failed to generate report zip file", "surveyID", surveyID, "error", err.Error())
@@ -83,7 +183,25 @@ func (a *UserSurveyApp) generateSurveyReport(surveyID string) (string, error) {
 func (a *UserSurveyApp) generateRawResponseCSV(survey *model.Survey, key string) (string, error) {
 	var lastResponseID string
 
-	headers := []string{"User ID", "Submitted At"}
+	headers := []string{"User ID", "Submitted At", "Locale", "Client", "Seconds To First Rating", "Seconds To Complete", "Teams", "Sentiment"}
+
+	// anonymous reports leave out the respondents, response metadata and submission dates,
+	// along with the locales of too few responses to keep them anonymous.
+	var reportableLocales map[string]bool
+	if survey.Anonymity.Enabled {
+		headers = []string{"Locale", "Sentiment"}
+
+		localeCounts, err := a.store.GetResponseCountByLocale(survey.ID)
+		if err != nil {
+			return "", errors.Wrapf(err, "generateRawResponseCSV: failed to get response count by locale, surveyID: %s", survey.ID)
+		}
+
+		reportableLocales = map[string]bool{}
+		for locale, count := range localeCounts {
+			reportableLocales[locale] = survey.Anonymity.IsGroupReportable(count)
+		}
+	}
+
 	for _, question := range survey.SurveyQuestions.Questions {
 		headers = append(headers, question.Text)
 	}
@@ -102,8 +220,12 @@ func (a *UserSurveyApp) generateRawResponseCSV(survey *model.Survey, key string)
 
 		lastResponseID = data[len(data)-1].ID
 
+		if err := a.decryptResponses(data); err != nil {
+			return "", errors.Wrapf(err, "generateRawResponseCSV: failed to decrypt survey responses, surveyID: %s", survey.ID)
+		}
+
 		// save them in a temp CSV
-		if err := a.saveTempCSVData(key, part, data, survey); err != nil {
+		if err := a.saveTempCSVData(key, part, data, survey, reportableLocales); err != nil {
 			return "", errors.Wrapf(err, "generateRawResponseCSV: surveyID: %s", survey.ID)
 		}
 
@@ -131,12 +253,17 @@ func (a *UserSurveyApp) generateRawResponseCSV(survey *model.Survey, key string)
 	return mergedFilePath, nil
 }
 
-func (a *UserSurveyApp) saveTempCSVData(key string, part int, surveyResponses []*model.SurveyResponse, survey *model.Survey) error {
+func (a *UserSurveyApp) saveTempCSVData(key string, part int, surveyResponses []*model.SurveyResponse, survey *model.Survey, reportableLocales map[string]bool) error {
 	var buf bytes.Buffer
 	csvWriter := csv.NewWriter(&buf)
 
 	for _, response := range surveyResponses {
-		err := csvWriter.Write(response.ToReportRow(survey.SurveyQuestions.Questions))
+		row := response.ToReportRow(survey.SurveyQuestions.Questions)
+		if survey.Anonymity.Enabled {
+			row = response.ToAnonymousReportRow(survey.SurveyQuestions.Questions, reportableLocales[response.Locale])
+		}
+
+		err := csvWriter.Write(row)
 		if err != nil {
 			a.api.LogError("saveTempCSVData: failed to write response row to CSV writer", "error", err.Error())
 			return errors.Wrap(err, "saveTempCSVData: failed to write response row to CSV writer")
@@ -201,7 +328,7 @@ func (a *UserSurveyApp) mergeParts(key string, headerRow []string, totalParts in
 }
 
 func (a *UserSurveyApp) generateSurveyMetadataFile(survey *model.Survey, key string) (string, error) {
-	surveyStat, err := a.store.GetSurveyStat(survey.ID)
+	surveyStat, err := a.GetSurveyStat(survey.ID)
 	if err != nil {
 		return "", errors.Wrapf(err, "generateSurveyMetadataFile: failed to get survey stat for survey, surveyID: %s", survey.ID)
 	}
@@ -229,6 +356,73 @@ func (a *UserSurveyApp) generateSurveyMetadataFile(survey *model.Survey, key str
 	return filePath, nil
 }
 
+func (a *UserSurveyApp) generateTeamNPSCSV(survey *model.Survey, key string) (string, error) {
+	surveyTeamNPS, err := a.GetSurveyTeamNPS(survey.ID)
+	if err != nil {
+		return "", errors.Wrapf(err, "generateTeamNPSCSV: failed to get survey team NPS, surveyID: %s", survey.ID)
+	}
+
+	var buf bytes.Buffer
+	csvWriter := csv.NewWriter(&buf)
+
+	headers := []string{"Team ID", "Team", "Respondents", "Promoters", "Passives", "Detractors", "NPS", "NPS Margin Of Error"}
+	if err := csvWriter.Write(headers); err != nil {
+		a.api.LogError("generateTeamNPSCSV: failed to write header row to CSV writer", "surveyID", survey.ID, "error", err.Error())
+		return "", errors.Wrapf(err, "generateTeamNPSCSV: failed to write header row to CSV writer, surveyID: %s", survey.ID)
+	}
+
+	for _, teamNPS := range surveyTeamNPS.Teams {
+		if err := csvWriter.Write(teamNPS.ToReportRow()); err != nil {
+			a.api.LogError("generateTeamNPSCSV: failed to write team row to CSV writer", "surveyID", survey.ID, "teamID", teamNPS.TeamID, "error", err.Error())
+			return "", errors.Wrapf(err, "generateTeamNPSCSV: failed to write team row to CSV writer, surveyID: %s, teamID: %s", survey.ID, teamNPS.TeamID)
+		}
+	}
+
+	csvWriter.Flush()
+	if err := csvWriter.Error(); err != nil {
+		a.api.LogError("generateTeamNPSCSV: csv writer reported some errors after flushing", "surveyID", survey.ID, "error", err.Error())
+		return "", errors.Wrapf(err, "generateTeamNPSCSV: csv writer reported some errors after flushing, surveyID: %s", survey.ID)
+	}
+
+	filePath := path.Join(os.TempDir(), "survey_report", key, "team_nps.csv")
+	if _, err := a.writeFileLocally(&buf, filePath); err != nil {
+		return "", errors.Wrapf(err, "generateTeamNPSCSV: failed to write team NPS CSV file, surveyID: %s, filePath: %s", survey.ID, filePath)
+	}
+
+	return filePath, nil
+}
+
+func (a *UserSurveyApp) generateTextAnalyticsCSV(analytics *model.SurveyTextAnalytics, key string) (string, error) {
+	var buf bytes.Buffer
+	csvWriter := csv.NewWriter(&buf)
+
+	headers := []string{"Question ID", "Question", "Rating Group", "Term Type", "Term", "Answers", "Occurrences"}
+	if err := csvWriter.Write(headers); err != nil {
+		a.api.LogError("generateTextAnalyticsCSV: failed to write header row to CSV writer", "surveyID", analytics.SurveyID, "error", err.Error())
+		return "", errors.Wrapf(err, "generateTextAnalyticsCSV: failed to write header row to CSV writer, surveyID: %s", analytics.SurveyID)
+	}
+
+	for _, question := range analytics.Questions {
+		if err := csvWriter.WriteAll(question.ToReportRows()); err != nil {
+			a.api.LogError("generateTextAnalyticsCSV: failed to write question rows to CSV writer", "surveyID", analytics.SurveyID, "questionID", question.QuestionID, "error", err.Error())
+			return "", errors.Wrapf(err, "generateTextAnalyticsCSV: failed to write question rows to CSV writer, surveyID: %s, questionID: %s", analytics.SurveyID, question.QuestionID)
+		}
+	}
+
+	csvWriter.Flush()
+	if err := csvWriter.Error(); err != nil {
+		a.api.LogError("generateTextAnalyticsCSV: csv writer reported some errors after flushing", "surveyID", analytics.SurveyID, "error", err.Error())
+		return "", errors.Wrapf(err, "generateTextAnalyticsCSV: csv writer reported some errors after flushing, surveyID: %s", analytics.SurveyID)
+	}
+
+	filePath := path.Join(os.TempDir(), "survey_report", key, "text_analytics.csv")
+	if _, err := a.writeFileLocally(&buf, filePath); err != nil {
+		return "", errors.Wrapf(err, "generateTextAnalyticsCSV: failed to write text analytics CSV file, surveyID: %s, filePath: %s", analytics.SurveyID, filePath)
+	}
+
+	return filePath, nil
+}
+
 func (a *UserSurveyApp) generateServerMetadataFile(key string) (string, error) {
 	fileDir := path.Join(os.TempDir(), "survey_report", key)
 
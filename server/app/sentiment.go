@@ -0,0 +1,31 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+// GetSurveySentiment computes the average sentiment of the survey's text answers, overall,
+// by rating group and by the teams the respondents were members of when answering.
+// The averages come from the segment counts, which are rolled up as responses are saved.
+func (a *UserSurveyApp) GetSurveySentiment(surveyID string) (*model.SurveySentiment, error) {
+	survey, err := a.store.GetSurveysByID(surveyID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "GetSurveySentiment: failed to get survey by ID, surveyID: %s", surveyID)
+	}
+
+	if survey == nil {
+		return nil, nil
+	}
+
+	segmentCounts, err := a.store.GetSurveySegmentCounts(surveyID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "GetSurveySentiment: failed to get survey segment counts, surveyID: %s", surveyID)
+	}
+
+	return model.NewSurveySentiment(survey, segmentCounts), nil
+}
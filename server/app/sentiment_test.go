@@ -0,0 +1,120 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+func TestScoreSentiment(t *testing.T) {
+	testCases := []struct {
+		name     string
+		text     string
+		expected float64
+	}{
+		{name: "positive", text: "Great app, I love it", expected: 0.84},
+		{name: "negative", text: "Search is slow and confusing", expected: -0.718},
+		{name: "no lexicon words", text: "I use it every day", expected: 0},
+		{name: "negation", text: "not bad", expected: 0.25},
+		{name: "negation doesn't span clauses", text: "Not now. Bad search", expected: -0.459},
+		{name: "intensifier", text: "very slow", expected: -0.612},
+		{name: "redaction placeholder", text: "Thanks [redacted mention]", expected: 0.459},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.expected, model.ScoreSentiment(testCase.text))
+		})
+	}
+}
+
+func TestGetSurveySentiment(t *testing.T) {
+	questions := model.SurveyQuestions{
+		Questions: []model.Question{
+			{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale},
+			{ID: "question_id_2", Type: model.QuestionType},
+			{ID: "question_id_3", Type: model.QuestionType},
+		},
+	}
+
+	responses := []*model.SurveyResponse{
+		newTestSentimentResponse("response_id_1", "10", map[string]float64{"question_id_2": 0.8, "question_id_3": 0.6}, testTeamA, testTeamB),
+		newTestSentimentResponse("response_id_2", "9", map[string]float64{"question_id_2": 0.4}, testTeamA),
+		newTestSentimentResponse("response_id_3", "2", map[string]float64{"question_id_2": -0.9}, testTeamA),
+		newTestSentimentResponse("response_id_4", "1", map[string]float64{}, testTeamB),
+	}
+
+	t.Run("should average sentiment overall, by rating group and by team", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		survey := &model.Survey{
+			ID:              "survey_id_1",
+			SurveyQuestions: questions,
+			Anonymity:       model.Anonymity{MinGroupSize: 2},
+		}
+
+		th.MockedStore.On("GetSurveysByID", "survey_id_1").Return(survey, nil)
+		th.MockedStore.On("GetSurveySegmentCounts", "survey_id_1").Return(newTestSegmentCounts(survey, responses...), nil)
+
+		surveySentiment, err := th.App.GetSurveySentiment("survey_id_1")
+		require.NoError(t, err)
+		require.False(t, surveySentiment.ResultsWithheld)
+		require.Equal(t, model.SentimentAverage{Respondents: 3, AnswerCount: 4, Average: 0.225}, surveySentiment.SentimentAverage)
+
+		require.Equal(t, []model.RatingGroupSentiment{
+			{Group: model.RatingGroupPromoters, SentimentAverage: model.SentimentAverage{Respondents: 2, AnswerCount: 3, Average: 0.6}},
+			{Group: model.RatingGroupPassives},
+			{Group: model.RatingGroupDetractors, SentimentAverage: model.SentimentAverage{Respondents: 1, AnswerCount: 1, Average: -0.9}},
+		}, surveySentiment.RatingGroups)
+
+		// responses without scored answers don't count towards the teams
+		require.Equal(t, []model.TeamSentiment{
+			{TeamID: "team_a", TeamName: "Team A", SentimentAverage: model.SentimentAverage{Respondents: 3, AnswerCount: 4, Average: 0.225}},
+		}, surveySentiment.Teams)
+		require.Equal(t, 1, surveySentiment.WithheldTeams)
+	})
+
+	t.Run("should withhold the sentiment of small groups of anonymous surveys", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		survey := &model.Survey{
+			ID:              "survey_id_1",
+			SurveyQuestions: questions,
+			Anonymity:       model.Anonymity{Enabled: true, MinGroupSize: 2},
+		}
+
+		th.MockedStore.On("GetSurveysByID", "survey_id_1").Return(survey, nil)
+		th.MockedStore.On("GetSurveySegmentCounts", "survey_id_1").Return(newTestSegmentCounts(survey, responses...), nil)
+
+		surveySentiment, err := th.App.GetSurveySentiment("survey_id_1")
+		require.NoError(t, err)
+		require.False(t, surveySentiment.ResultsWithheld)
+		require.False(t, surveySentiment.RatingGroups[0].Withheld)
+		require.True(t, surveySentiment.RatingGroups[1].Withheld)
+		require.True(t, surveySentiment.RatingGroups[2].Withheld)
+		require.Zero(t, surveySentiment.RatingGroups[2].Average)
+
+		survey.Anonymity.MinGroupSize = 5
+
+		surveySentiment, err = th.App.GetSurveySentiment("survey_id_1")
+		require.NoError(t, err)
+		require.True(t, surveySentiment.ResultsWithheld)
+		require.Zero(t, surveySentiment.Average)
+		require.Empty(t, surveySentiment.RatingGroups)
+	})
+
+	t.Run("should return nil for a missing survey", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		th.MockedStore.On("GetSurveysByID", "survey_id_1").Return(nil, nil)
+
+		surveySentiment, err := th.App.GetSurveySentiment("survey_id_1")
+		require.NoError(t, err)
+		require.Nil(t, surveySentiment)
+	})
+}
@@ -35,9 +35,44 @@ func SetupAppTest(t *testing.T) *AppTestHelper {
 	app, err := New(mockedAPI, &mockedStore, getConfig, &mockedDriver, true)
 	require.NoError(t, err)
 
+	// deliveries are only looked up in the KV store until they're backfilled
+	app.surveyDeliveriesBackfilled.Store(true)
+
 	return &AppTestHelper{
 		App:             app,
 		MockedStore:     &mockedStore,
 		MockedPluginAPI: mockedAPI,
 	}
 }
+
+var (
+	testTeamA = model.ResponseTeam{ID: "team_a", DisplayName: "Team A"}
+	testTeamB = model.ResponseTeam{ID: "team_b", DisplayName: "Team B"}
+)
+
+// newTestResponse builds a response rating the system question "question_id_1".
+func newTestResponse(id, rating string, teams ...model.ResponseTeam) *model.SurveyResponse {
+	return &model.SurveyResponse{
+		ID:       id,
+		Response: map[string]string{"question_id_1": rating},
+		Metadata: model.ResponseMetadata{Teams: teams},
+	}
+}
+
+func newTestSentimentResponse(id, rating string, sentiment map[string]float64, teams ...model.ResponseTeam) *model.SurveyResponse {
+	response := newTestResponse(id, rating, teams...)
+	response.Sentiment = sentiment
+	return response
+}
+
+// newTestSegmentCounts counts the responses in the survey's segments, as the store does when they're saved.
+func newTestSegmentCounts(survey *model.Survey, responses ...*model.SurveyResponse) model.SurveySegmentCounts {
+	ratingQuestion, _ := survey.GetSystemRatingQuestion()
+
+	segmentCounts := model.SurveySegmentCounts{}
+	for _, response := range responses {
+		segmentCounts.AddResponse(ratingQuestion, response, 1)
+	}
+
+	return segmentCounts
+}
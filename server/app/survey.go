@@ -24,7 +24,9 @@ const (
 
 	cacheValidityUserTeamFilter = 7200 // 2 hours in seconds
 
-	surveyPostMessage = "Help us improve your experience. Use Mattermost in a web browser or the desktop app to take the survey."
+	surveyPostMessageTranslationID = "app.survey.post_message"
+
+	allSurveysPerPage = 100
 )
 
 func (a *UserSurveyApp) SaveSurvey(survey *model.Survey) error {
@@ -36,6 +38,31 @@ func (a *UserSurveyApp) SaveSurvey(survey *model.Survey) error {
 	return a.store.SaveSurvey(survey)
 }
 
+// forEachSurvey calls f with every survey, a page of surveys at a time,
+// stopping at the first error f returns.
+func (a *UserSurveyApp) forEachSurvey(f func(survey *model.Survey) error) error {
+	lastSurveyID := ""
+
+	for {
+		surveys, err := a.store.GetAllSurveys(lastSurveyID, allSurveysPerPage)
+		if err != nil {
+			return errors.Wrap(err, "forEachSurvey: failed to get surveys")
+		}
+
+		for _, survey := range surveys {
+			if err := f(survey); err != nil {
+				return err
+			}
+		}
+
+		if len(surveys) < allSurveysPerPage {
+			return nil
+		}
+
+		lastSurveyID = surveys[len(surveys)-1].ID
+	}
+}
+
 func (a *UserSurveyApp) GetInProgressSurvey() (*model.Survey, error) {
 	surveys, err := a.store.GetSurveysByStatus(model.SurveyStatusInProgress)
 	if err != nil {
@@ -113,6 +140,15 @@ func (a *UserSurveyApp) ShouldSendSurvey(userID string, survey *model.Survey) (b
 		return false, nil
 	}
 
+	optedOut, err := a.HasUserOptedOutOfSurveys(userID)
+	if err != nil {
+		return false, errors.Wrap(err, "ShouldSendSurvey: failed to check if user has opted out of surveys")
+	}
+
+	if optedOut {
+		return false, nil
+	}
+
 	userPassesTeamFilter, err := a.userPassesSurveyTeamFilter(userID, survey)
 	if err != nil {
 		return false, errors.Wrap(err, "ShouldSendSurvey: failed to check if user passes team filter or not")
@@ -121,6 +157,13 @@ func (a *UserSurveyApp) ShouldSendSurvey(userID string, survey *model.Survey) (b
 	return userPassesTeamFilter, nil
 }
 
+// IsUserEligibleForSurvey checks if the user passes the survey's team filter.
+// Unlike ShouldSendSurvey, it doesn't consider whether the survey was already sent
+// to the user or whether the user has opted out of surveys.
+func (a *UserSurveyApp) IsUserEligibleForSurvey(userID string, survey *model.Survey) (bool, error) {
+	return a.userPassesSurveyTeamFilter(userID, survey)
+}
+
 func (a *UserSurveyApp) userPassesSurveyTeamFilter(userID string, survey *model.Survey) (bool, error) {
 	if survey.TeamFilterType == model.TeamFilterSendToAll {
 		// nothing to check if survey isn't filtering by teams
@@ -145,75 +188,146 @@ func (a *UserSurveyApp) userPassesSurveyTeamFilter(userID string, survey *model.
 }
 
 func (a *UserSurveyApp) GetSurveyPostIDSentToUser(userID, surveyID string) (string, error) {
-	postID, appErr := a.api.KVGet(utils.KeyUserSurveySentStatus(userID, surveyID))
-	if appErr != nil {
-		a.api.LogError("GetSurveySentToUser: Failed to get user survey sent status key from KV store", "userID", userID, "surveyID", surveyID, "error", appErr.Error())
-		return "", errors.Wrap(appErr, "GetSurveySentToUser: Failed to get user survey sent status key from KV store")
+	delivery, err := a.store.GetSurveyDelivery(userID, surveyID)
+	if err != nil {
+		return "", errors.Wrap(err, "GetSurveyPostIDSentToUser: failed to get survey delivery")
+	}
+
+	if delivery == nil {
+		// the backfill runs in the background, so deliveries from before
+		// the deliveries table existed may only be in the KV store for now.
+		postID, err := a.getKVSurveyPostIDSentToUser(userID, surveyID)
+		if err != nil {
+			return "", errors.Wrap(err, "GetSurveyPostIDSentToUser: failed to get survey post sent to user from KV store")
+		}
+
+		return postID, nil
+	}
+
+	return delivery.PostID, nil
+}
+
+func (a *UserSurveyApp) setSurveySentToUser(userID, surveyID string, post *mmModal.Post) error {
+	delivery := &model.SurveyDelivery{
+		UserID:      userID,
+		SurveyID:    surveyID,
+		PostID:      post.Id,
+		DeliveredAt: post.CreateAt,
+		Status:      model.SurveyDeliveryStatusDelivered,
 	}
 
-	if postID == nil {
-		return "", nil
+	if err := a.store.SaveSurveyDelivery(delivery); err != nil {
+		return errors.Wrap(err, "setSurveySentToUser: failed to save survey delivery")
 	}
 
-	return string(postID), nil
+	return nil
 }
 
-func (a *UserSurveyApp) setSurveySentToUser(userID, surveyID, postID string) error {
-	appErr := a.api.KVSet(utils.KeyUserSurveySentStatus(userID, surveyID), []byte(postID))
+func (a *UserSurveyApp) HasUserOptedOutOfSurveys(userID string) (bool, error) {
+	value, appErr := a.api.KVGet(utils.KeyUserSurveyOptOut(userID))
 	if appErr != nil {
-		a.api.LogError("setSurveySentToUser: Failed to set user survey sent status in KV store", "userID", userID, "surveyID", surveyID, "error", appErr.Error())
-		return errors.Wrap(appErr, "setSurveySentToUser: Failed to set user survey sent status in KV store")
+		a.api.LogError("HasUserOptedOutOfSurveys: failed to get user survey opt out status from KV store", "userID", userID, "error", appErr.Error())
+		return false, errors.Wrap(errors.New(appErr.Error()), "HasUserOptedOutOfSurveys: failed to get user survey opt out status from KV store")
+	}
+
+	return string(value) == "true", nil
+}
+
+func (a *UserSurveyApp) SetUserSurveyOptOut(userID string, optOut bool) error {
+	key := utils.KeyUserSurveyOptOut(userID)
+
+	var appErr *mmModal.AppError
+	if optOut {
+		appErr = a.api.KVSet(key, []byte("true"))
+	} else {
+		appErr = a.api.KVDelete(key)
+	}
+
+	if appErr != nil {
+		a.api.LogError("SetUserSurveyOptOut: failed to update user survey opt out status in KV store", "userID", userID, "optOut", optOut, "error", appErr.Error())
+		return errors.Wrap(errors.New(appErr.Error()), "SetUserSurveyOptOut: failed to update user survey opt out status in KV store")
 	}
 
 	return nil
 }
 
 func (a *UserSurveyApp) SendSurvey(userID string, survey *model.Survey) error {
+	if _, err := a.sendSurveyPost(userID, survey); err != nil {
+		return errors.Wrap(err, "SendSurvey: failed to send survey post")
+	}
+
+	if err := a.store.IncrementSurveyReceiptCount(survey.ID); err != nil {
+		return errors.Wrap(err, "SendSurvey: failed to increment survey receipt count")
+	}
+
+	return nil
+}
+
+// ResendSurvey posts the survey to the user again, for example when the original
+// survey post was deleted. The survey's receipt count is not incremented
+// as the user had already received the survey once.
+func (a *UserSurveyApp) ResendSurvey(userID string, survey *model.Survey) (string, error) {
+	postID, err := a.sendSurveyPost(userID, survey)
+	if err != nil {
+		return "", errors.Wrap(err, "ResendSurvey: failed to send survey post")
+	}
+
+	return postID, nil
+}
+
+func (a *UserSurveyApp) sendSurveyPost(userID string, survey *model.Survey) (string, error) {
 	user, appErr := a.api.GetUser(userID)
 	if appErr != nil {
-		a.api.LogError("SendSurvey: failed to get user from ID", "userID", user, "error", appErr.Error())
-		return errors.Wrap(appErr, "SendSurvey: failed to get user from ID: "+userID)
+		a.api.LogError("sendSurveyPost: failed to get user from ID", "userID", userID, "error", appErr.Error())
+		return "", errors.Wrap(appErr, "sendSurveyPost: failed to get user from ID: "+userID)
+	}
+
+	botID, err := a.getSurveyBotID(survey)
+	if err != nil {
+		return "", errors.Wrap(err, "sendSurveyPost: failed to get survey bot")
 	}
 
 	// open a DM between the bot and the user
-	botUserDM, appErr := a.api.GetDirectChannel(user.Id, a.botID)
+	botUserDM, appErr := a.api.GetDirectChannel(user.Id, botID)
 	if appErr != nil {
-		errMsg := fmt.Sprintf("SendSurvey: failed to create DM between survey bot and user, botID: %s, userID: %s, error: %s", a.botID, userID, appErr.Error())
+		errMsg := fmt.Sprintf("sendSurveyPost: failed to create DM between survey bot and user, botID: %s, userID: %s, error: %s", botID, userID, appErr.Error())
 		a.api.LogError(errMsg)
-		return errors.Wrap(errors.New(appErr.Error()), errMsg)
+		return "", errors.Wrap(errors.New(appErr.Error()), errMsg)
 	}
 
 	post := &mmModal.Post{
-		UserId:    a.botID,
-		Message:   surveyPostMessage,
+		UserId:    botID,
+		Message:   a.renderMessage(survey.Customization.PostMessage, a.i18n.GetUserTranslations(user.Locale)(surveyPostMessageTranslationID), user),
 		ChannelId: botUserDM.Id,
 		Type:      surveyPostType,
 	}
 
-	questionsJSON, err := json.Marshal(survey.SurveyQuestions)
+	questions, locale := survey.SurveyQuestions.Localize(user.Locale)
+	questionsJSON, err := json.Marshal(questions)
 	if err != nil {
-		a.api.LogError("SendSurvey: failed to marshal survey questions for inserting into post", "error", err.Error())
-		return errors.Wrap(err, "SendSurvey: failed to marshal survey questions for inserting into post")
+		a.api.LogError("sendSurveyPost: failed to marshal survey questions for inserting into post", "error", err.Error())
+		return "", errors.Wrap(err, "sendSurveyPost: failed to marshal survey questions for inserting into post")
 	}
 
 	post.AddProp(postPropKeySurveyQuestions, string(questionsJSON))
 	post.AddProp(postPropSurveyID, survey.ID)
+	post.AddProp(postPropSurveyLocale, locale)
+
+	if survey.ResponsesEditable {
+		post.AddProp(postPropResponsesEditable, true)
+	}
 
 	createdPost, appErr := a.api.CreatePost(post)
 	if appErr != nil {
-		a.api.LogError("SendSurvey: failed to create survey post for user", "userID", userID, "error", appErr.Error())
-		return errors.Wrap(appErr, "SendSurvey: failed to create survey post for user")
+		a.api.LogError("sendSurveyPost: failed to create survey post for user", "userID", userID, "error", appErr.Error())
+		return "", errors.Wrap(appErr, "sendSurveyPost: failed to create survey post for user")
 	}
 
-	if err := a.setSurveySentToUser(userID, survey.ID, createdPost.Id); err != nil {
-		return errors.Wrap(err, "SendSurvey: failed to mark survey set to user")
+	if err := a.setSurveySentToUser(userID, survey.ID, createdPost); err != nil {
+		return "", errors.Wrap(err, "sendSurveyPost: failed to mark survey set to user")
 	}
 
-	if err := a.store.IncrementSurveyReceiptCount(survey.ID); err != nil {
-		return errors.Wrap(err, "SendSurvey: failed to increment survey receipt count")
-	}
-
-	return nil
+	return createdPost.Id, nil
 }
 
 func (a *UserSurveyApp) ensureSurveyBot() error {
@@ -0,0 +1,40 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+// CompareSurveys compares the results of the survey with the results of the base survey,
+// such as a previous run of the same survey. It returns nil if either survey doesn't exist.
+func (a *UserSurveyApp) CompareSurveys(surveyID, baseSurveyID string) (*model.SurveyComparison, error) {
+	surveyStat, err := a.GetSurveyStat(surveyID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "CompareSurveys: failed to get survey stat, surveyID: %s", surveyID)
+	}
+
+	baseSurveyStat, err := a.GetSurveyStat(baseSurveyID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "CompareSurveys: failed to get base survey stat, baseSurveyID: %s", baseSurveyID)
+	}
+
+	if surveyStat == nil || baseSurveyStat == nil {
+		return nil, nil
+	}
+
+	surveyTeamNPS, err := a.GetSurveyTeamNPS(surveyID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "CompareSurveys: failed to get survey team NPS, surveyID: %s", surveyID)
+	}
+
+	baseSurveyTeamNPS, err := a.GetSurveyTeamNPS(baseSurveyID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "CompareSurveys: failed to get base survey team NPS, baseSurveyID: %s", baseSurveyID)
+	}
+
+	return model.NewSurveyComparison(surveyStat, baseSurveyStat, surveyTeamNPS, baseSurveyTeamNPS), nil
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+func TestCompareSurveys(t *testing.T) {
+	survey := model.Survey{
+		ID: "survey_id_2",
+		SurveyQuestions: model.SurveyQuestions{
+			Questions: []model.Question{
+				{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale},
+				{ID: "question_id_2", Text: "What could be better?", Type: model.QuestionType},
+				{ID: "question_id_3", Text: "How easy is the app to use?", Type: model.QuestionTypeLinearScale, Scale: &model.RatingScale{Min: 1, Max: 3, DetractorMax: 1, PassiveMax: 2}},
+				{ID: "question_id_4", Text: "Anything else?", Type: model.QuestionType},
+			},
+		},
+		Anonymity: model.Anonymity{MinGroupSize: 1},
+	}
+
+	baseSurvey := model.Survey{
+		ID: "survey_id_1",
+		SurveyQuestions: model.SurveyQuestions{
+			Questions: []model.Question{
+				{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale},
+				{ID: "question_id_3", Text: "How easy is the app to use?", Type: model.QuestionTypeLinearScale, Scale: &model.RatingScale{Min: 1, Max: 3, DetractorMax: 1, PassiveMax: 2}},
+				{ID: "question_id_5", Text: "What could be better? ", Type: model.QuestionType},
+				{ID: "question_id_6", Text: "Anything else?", Type: model.QuestionTypeLinearScale},
+			},
+		},
+		Anonymity: model.Anonymity{MinGroupSize: 1},
+	}
+
+	mockSurveyStats := func(th *AppTestHelper, survey, baseSurvey model.Survey) {
+		th.MockedStore.On("GetSurveyStat", "survey_id_2").Return(&model.SurveyStat{
+			Survey:         survey,
+			ReceiptCount:   20,
+			ResponseCount:  10,
+			PromoterCount:  5,
+			PassiveCount:   3,
+			DetractorCount: 2,
+		}, nil)
+		th.MockedStore.On("GetSurveyStat", "survey_id_1").Return(&model.SurveyStat{
+			Survey:         baseSurvey,
+			ReceiptCount:   20,
+			ResponseCount:  8,
+			PromoterCount:  2,
+			PassiveCount:   2,
+			DetractorCount: 4,
+		}, nil)
+
+		th.MockedStore.On("GetQuestionAnswerCounts", "survey_id_2", mock.Anything).Return(map[string]int64{"question_id_1": 10, "question_id_2": 6, "question_id_3": 10}, nil)
+		th.MockedStore.On("GetQuestionAnswerCounts", "survey_id_1", mock.Anything).Return(map[string]int64{"question_id_1": 8, "question_id_5": 4, "question_id_3": 8}, nil)
+		th.MockedStore.On("GetQuestionAnswerValueCounts", "survey_id_2", "question_id_1").Return(map[string]int64{"10": 5, "7": 3, "2": 2}, nil)
+		th.MockedStore.On("GetQuestionAnswerValueCounts", "survey_id_2", "question_id_3").Return(map[string]int64{"3": 6, "2": 4}, nil)
+		th.MockedStore.On("GetQuestionAnswerValueCounts", "survey_id_1", "question_id_1").Return(map[string]int64{"10": 2, "8": 2, "3": 4}, nil)
+		th.MockedStore.On("GetQuestionAnswerValueCounts", "survey_id_1", "question_id_3").Return(map[string]int64{"3": 4, "1": 4}, nil)
+		th.MockedStore.On("GetQuestionAnswerValueCounts", "survey_id_1", "question_id_6").Return(map[string]int64{}, nil)
+
+		th.MockedStore.On("GetSurveysByID", "survey_id_2").Return(&survey, nil)
+		th.MockedStore.On("GetSurveysByID", "survey_id_1").Return(&baseSurvey, nil)
+	}
+
+	t.Run("should compare NPS, response rate, questions and teams", func(t *testing.T) {
+		th := SetupAppTest(t)
+		mockSurveyStats(th, survey, baseSurvey)
+
+		th.MockedStore.On("GetSurveySegmentCounts", "survey_id_2").Return(newTestSegmentCounts(&survey,
+			newTestResponse("response_id_1", "10", testTeamA),
+			newTestResponse("response_id_2", "10", testTeamA),
+		), nil)
+		th.MockedStore.On("GetSurveySegmentCounts", "survey_id_1").Return(newTestSegmentCounts(&baseSurvey,
+			newTestResponse("response_id_3", "2", testTeamA),
+			newTestResponse("response_id_4", "9", testTeamB),
+		), nil)
+
+		comparison, err := th.App.CompareSurveys("survey_id_2", "survey_id_1")
+		require.NoError(t, err)
+		require.False(t, comparison.ResultsWithheld)
+		require.Equal(t, model.MetricComparison{Value: 10, BaseValue: 8, Difference: 2}, comparison.ResponseCount)
+		require.Equal(t, model.MetricComparison{Value: 50, BaseValue: 40, Difference: 10}, comparison.ResponseRate)
+		require.Equal(t, 55.0, comparison.NPS.Difference)
+
+		require.Len(t, comparison.RatingGroups, 3)
+		require.Equal(t, model.RatingGroupPromoters, comparison.RatingGroups[0].Answer)
+		require.Equal(t, model.MetricComparison{Value: 50, BaseValue: 25, Difference: 25}, comparison.RatingGroups[0].Share)
+
+		// questions match by ID, then by text, and only if they're of the same type
+		require.Len(t, comparison.Questions, 3)
+		require.Equal(t, []string{"question_id_4"}, comparison.UnmatchedQuestionIDs)
+		require.Equal(t, []string{"question_id_6"}, comparison.UnmatchedBaseQuestionIDs)
+
+		ratingComparison := comparison.Questions[0]
+		require.Equal(t, "question_id_1", ratingComparison.BaseQuestionID)
+		require.Equal(t, model.QuestionMatchByID, ratingComparison.MatchedBy)
+		require.Equal(t, model.MetricComparison{Value: 7.5, BaseValue: 6, Difference: 1.5}, *ratingComparison.MeanRating)
+		require.Len(t, ratingComparison.Answers, 11)
+		require.Equal(t, model.AnswerComparison{
+			Answer:    "10",
+			Count:     5,
+			BaseCount: 2,
+			Share:     model.MetricComparison{Value: 50, BaseValue: 25, Difference: 25},
+		}, ratingComparison.Answers[10])
+
+		textComparison := comparison.Questions[1]
+		require.Equal(t, "question_id_2", textComparison.QuestionID)
+		require.Equal(t, "question_id_5", textComparison.BaseQuestionID)
+		require.Equal(t, model.QuestionMatchByText, textComparison.MatchedBy)
+		require.Equal(t, model.MetricComparison{Value: 6, BaseValue: 4, Difference: 2}, textComparison.ResponseCount)
+		require.Empty(t, textComparison.Answers)
+
+		scaleComparison := comparison.Questions[2]
+		require.Equal(t, model.QuestionMatchByID, scaleComparison.MatchedBy)
+		require.Len(t, scaleComparison.Answers, 3)
+		require.Equal(t, "1", scaleComparison.Answers[0].Answer)
+		require.Equal(t, int64(4), scaleComparison.Answers[0].BaseCount)
+		require.Equal(t, "3", scaleComparison.Answers[2].Answer)
+		require.Equal(t, model.MetricComparison{Value: 60, BaseValue: 50, Difference: 10}, scaleComparison.Answers[2].Share)
+
+		require.Len(t, comparison.Teams, 2)
+		require.Equal(t, "team_a", comparison.Teams[0].TeamID)
+		require.Equal(t, 100.0, *comparison.Teams[0].NPS)
+		require.Equal(t, -100.0, *comparison.Teams[0].BaseNPS)
+		require.Equal(t, 200.0, comparison.Teams[0].Comparison.Difference)
+		require.Equal(t, "team_b", comparison.Teams[1].TeamID)
+		require.Nil(t, comparison.Teams[1].NPS)
+		require.Equal(t, 100.0, *comparison.Teams[1].BaseNPS)
+		require.Nil(t, comparison.Teams[1].Comparison)
+	})
+
+	t.Run("should compare the score of top group scales", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		scale := &model.RatingScale{Min: 1, Max: 5, DetractorMax: 2, PassiveMax: 3, ScoreType: model.RatingScoreTypeTopGroup}
+		topGroupSurvey, topGroupBaseSurvey := survey, baseSurvey
+		topGroupSurvey.SurveyQuestions = model.SurveyQuestions{Questions: []model.Question{{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale, Scale: scale}}}
+		topGroupBaseSurvey.SurveyQuestions = model.SurveyQuestions{Questions: []model.Question{{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale, Scale: scale}}}
+		mockSurveyStats(th, topGroupSurvey, topGroupBaseSurvey)
+
+		th.MockedStore.On("GetSurveySegmentCounts", mock.Anything).Return(model.SurveySegmentCounts{}, nil)
+
+		comparison, err := th.App.CompareSurveys("survey_id_2", "survey_id_1")
+		require.NoError(t, err)
+
+		// 50% of promoters against 25%, rather than an NPS of 30 against -25
+		require.Equal(t, 25.0, comparison.NPS.Difference)
+		require.InDelta(t, 0.256, comparison.NPS.PValue, 0.001)
+		require.False(t, comparison.NPS.Significant)
+	})
+
+	t.Run("should only compare response counts if either survey's results are withheld", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		anonymousBaseSurvey := baseSurvey
+		anonymousBaseSurvey.Anonymity = model.Anonymity{Enabled: true, MinGroupSize: 10}
+		mockSurveyStats(th, survey, anonymousBaseSurvey)
+
+		th.MockedStore.On("GetSurveySegmentCounts", "survey_id_2").Return(model.SurveySegmentCounts{}, nil)
+
+		comparison, err := th.App.CompareSurveys("survey_id_2", "survey_id_1")
+		require.NoError(t, err)
+		require.True(t, comparison.ResultsWithheld)
+		require.Equal(t, model.MetricComparison{Value: 10, BaseValue: 8, Difference: 2}, comparison.ResponseCount)
+		require.Nil(t, comparison.NPS)
+		require.Empty(t, comparison.RatingGroups)
+		require.Empty(t, comparison.Questions)
+		require.Empty(t, comparison.Teams)
+	})
+
+	t.Run("should return nil if either survey doesn't exist", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		th.MockedStore.On("GetSurveyStat", "survey_id_2").Return(nil, nil)
+		th.MockedStore.On("GetSurveyStat", "survey_id_1").Return(nil, nil)
+
+		comparison, err := th.App.CompareSurveys("survey_id_2", "survey_id_1")
+		require.NoError(t, err)
+		require.Nil(t, comparison)
+	})
+}
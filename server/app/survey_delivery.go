@@ -0,0 +1,153 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+	"github.com/mattermost/mattermost-plugin-user-survey/server/utils"
+)
+
+const backfillKVListPerPage = 1000
+
+// BackfillSurveyDeliveries copies the survey deliveries recorded in the KV store,
+// from before deliveries were saved in the database, into the deliveries table.
+// It only runs once, subsequent calls are no-op.
+func (a *UserSurveyApp) BackfillSurveyDeliveries() error {
+	backfilled, err := a.areSurveyDeliveriesBackfilled()
+	if err != nil {
+		return errors.Wrap(err, "BackfillSurveyDeliveries: failed to get backfill status")
+	}
+
+	if backfilled {
+		return nil
+	}
+
+	deliveries, err := a.getKVSurveyDeliveries()
+	if err != nil {
+		return errors.Wrap(err, "BackfillSurveyDeliveries: failed to get survey deliveries from KV store")
+	}
+
+	if err := a.store.BackfillSurveyDeliveries(deliveries); err != nil {
+		return errors.Wrap(err, "BackfillSurveyDeliveries: failed to save survey deliveries")
+	}
+
+	if appErr := a.api.KVSet(utils.KeySurveyDeliveriesBackfilled, []byte("true")); appErr != nil {
+		a.api.LogError("BackfillSurveyDeliveries: failed to save backfill status in KV store", "error", appErr.Error())
+		return errors.Wrap(errors.New(appErr.Error()), "BackfillSurveyDeliveries: failed to save backfill status in KV store")
+	}
+
+	a.surveyDeliveriesBackfilled.Store(true)
+	a.api.LogInfo("BackfillSurveyDeliveries: backfilled survey deliveries from KV store", "count", len(deliveries))
+	return nil
+}
+
+// areSurveyDeliveriesBackfilled returns whether the survey deliveries in the KV store were
+// backfilled, which is cached once they are as it doesn't change afterwards.
+func (a *UserSurveyApp) areSurveyDeliveriesBackfilled() (bool, error) {
+	if a.surveyDeliveriesBackfilled.Load() {
+		return true, nil
+	}
+
+	backfilled, appErr := a.api.KVGet(utils.KeySurveyDeliveriesBackfilled)
+	if appErr != nil {
+		a.api.LogError("areSurveyDeliveriesBackfilled: failed to get backfill status from KV store", "error", appErr.Error())
+		return false, errors.Wrap(errors.New(appErr.Error()), "areSurveyDeliveriesBackfilled: failed to get backfill status from KV store")
+	}
+
+	if string(backfilled) != "true" {
+		return false, nil
+	}
+
+	a.surveyDeliveriesBackfilled.Store(true)
+	return true, nil
+}
+
+// getKVSurveyPostIDSentToUser returns the ID of the survey post sent to the user as recorded
+// in the KV store, for deliveries that weren't backfilled into the deliveries table yet.
+func (a *UserSurveyApp) getKVSurveyPostIDSentToUser(userID, surveyID string) (string, error) {
+	backfilled, err := a.areSurveyDeliveriesBackfilled()
+	if err != nil {
+		return "", errors.Wrap(err, "getKVSurveyPostIDSentToUser: failed to get backfill status")
+	}
+
+	if backfilled {
+		return "", nil
+	}
+
+	postID, appErr := a.api.KVGet(utils.KeyUserSurveySentStatus(userID, surveyID))
+	if appErr != nil {
+		a.api.LogError("getKVSurveyPostIDSentToUser: failed to get user survey sent status from KV store", "userID", userID, "surveyID", surveyID, "error", appErr.Error())
+		return "", errors.Wrap(errors.New(appErr.Error()), "getKVSurveyPostIDSentToUser: failed to get user survey sent status from KV store")
+	}
+
+	return string(postID), nil
+}
+
+// getKVSurveyDeliveries builds the survey deliveries from the
+// per user sent status and opened markers in the KV store.
+func (a *UserSurveyApp) getKVSurveyDeliveries() ([]*model.SurveyDelivery, error) {
+	var sentKeys []string
+	openedKeys := map[string]bool{}
+
+	for page := 0; ; page++ {
+		keys, appErr := a.api.KVList(page, backfillKVListPerPage)
+		if appErr != nil {
+			a.api.LogError("getKVSurveyDeliveries: failed to list KV store keys", "page", page, "error", appErr.Error())
+			return nil, errors.Wrap(errors.New(appErr.Error()), "getKVSurveyDeliveries: failed to list KV store keys")
+		}
+
+		for _, key := range keys {
+			if _, _, ok := utils.ParseUserSurveyKey(key, utils.UserSurveySentStatusKeyPrefix); ok {
+				sentKeys = append(sentKeys, key)
+			} else if userID, surveyID, ok := utils.ParseUserSurveyKey(key, utils.UserSurveyOpenedKeyPrefix); ok {
+				openedKeys[utils.KeyUserSurveySentStatus(userID, surveyID)] = true
+			}
+		}
+
+		if len(keys) < backfillKVListPerPage {
+			break
+		}
+	}
+
+	deliveries := make([]*model.SurveyDelivery, 0, len(sentKeys))
+	for _, key := range sentKeys {
+		userID, surveyID, _ := utils.ParseUserSurveyKey(key, utils.UserSurveySentStatusKeyPrefix)
+
+		postID, appErr := a.api.KVGet(key)
+		if appErr != nil {
+			a.api.LogError("getKVSurveyDeliveries: failed to get user survey sent status from KV store", "key", key, "error", appErr.Error())
+			return nil, errors.Wrap(errors.New(appErr.Error()), "getKVSurveyDeliveries: failed to get user survey sent status from KV store")
+		}
+
+		if len(postID) == 0 {
+			continue
+		}
+
+		delivery := &model.SurveyDelivery{
+			UserID:   userID,
+			SurveyID: surveyID,
+			PostID:   string(postID),
+			Status:   model.SurveyDeliveryStatusDelivered,
+		}
+
+		if openedKeys[key] {
+			delivery.Status = model.SurveyDeliveryStatusOpened
+		}
+
+		// the survey post's creation is when the survey was delivered. The delivery
+		// is still backfilled without it if the post isn't available anymore.
+		post, appErr := a.api.GetPost(delivery.PostID)
+		if appErr != nil {
+			a.api.LogWarn("getKVSurveyDeliveries: failed to get survey post, backfilling delivery without delivery time", "postID", delivery.PostID, "error", appErr.Error())
+		} else {
+			delivery.DeliveredAt = post.CreateAt
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+func TestBackfillSurveyDeliveries(t *testing.T) {
+	t.Run("should backfill deliveries from the KV store markers", func(t *testing.T) {
+		th := SetupAppTest(t)
+		th.App.surveyDeliveriesBackfilled.Store(false)
+
+		th.MockedPluginAPI.On("KVGet", "survey_deliveries_backfilled").Return(nil, nil)
+		th.MockedPluginAPI.On("KVList", 0, backfillKVListPerPage).Return([]string{
+			"user_survey_status_userid1_surveyid1",
+			"user_survey_status_userid2_surveyid1",
+			"user_survey_opened_userid1_surveyid1",
+			"user_survey_opt_out_userid1",
+		}, nil)
+		th.MockedPluginAPI.On("KVGet", "user_survey_status_userid1_surveyid1").Return([]byte("postid1"), nil)
+		th.MockedPluginAPI.On("KVGet", "user_survey_status_userid2_surveyid1").Return([]byte("postid2"), nil)
+		th.MockedPluginAPI.On("GetPost", "postid1").Return(&mmModel.Post{Id: "postid1", CreateAt: 1000}, nil)
+		th.MockedPluginAPI.On("GetPost", "postid2").Return(nil, &mmModel.AppError{Message: "post not found"})
+		th.MockedStore.On("BackfillSurveyDeliveries", []*model.SurveyDelivery{
+			{UserID: "userid1", SurveyID: "surveyid1", PostID: "postid1", DeliveredAt: 1000, Status: model.SurveyDeliveryStatusOpened},
+			{UserID: "userid2", SurveyID: "surveyid1", PostID: "postid2", Status: model.SurveyDeliveryStatusDelivered},
+		}).Return(nil)
+		th.MockedPluginAPI.On("KVSet", "survey_deliveries_backfilled", []byte("true")).Return(nil)
+
+		require.NoError(t, th.App.BackfillSurveyDeliveries())
+		th.MockedStore.AssertExpectations(t)
+		th.MockedPluginAPI.AssertExpectations(t)
+		require.True(t, th.App.surveyDeliveriesBackfilled.Load())
+	})
+
+	t.Run("should not backfill deliveries twice", func(t *testing.T) {
+		th := SetupAppTest(t)
+		th.App.surveyDeliveriesBackfilled.Store(false)
+
+		th.MockedPluginAPI.On("KVGet", "survey_deliveries_backfilled").Return([]byte("true"), nil)
+
+		require.NoError(t, th.App.BackfillSurveyDeliveries())
+		th.MockedPluginAPI.AssertNotCalled(t, "KVList", 0, backfillKVListPerPage)
+		th.MockedStore.AssertNotCalled(t, "BackfillSurveyDeliveries")
+	})
+}
+
+func TestGetSurveyPostIDSentToUser(t *testing.T) {
+	t.Run("should get post from deliveries table", func(t *testing.T) {
+		th := SetupAppTest(t)
+		th.App.surveyDeliveriesBackfilled.Store(false)
+
+		th.MockedStore.On("GetSurveyDelivery", "userid1", "surveyid1").Return(&model.SurveyDelivery{PostID: "postid1"}, nil)
+
+		postID, err := th.App.GetSurveyPostIDSentToUser("userid1", "surveyid1")
+		require.NoError(t, err)
+		require.Equal(t, "postid1", postID)
+		th.MockedPluginAPI.AssertNotCalled(t, "KVGet", mock.Anything)
+	})
+
+	t.Run("should fall back to KV store until deliveries are backfilled", func(t *testing.T) {
+		th := SetupAppTest(t)
+		th.App.surveyDeliveriesBackfilled.Store(false)
+
+		th.MockedStore.On("GetSurveyDelivery", "userid1", "surveyid1").Return(nil, nil)
+		th.MockedPluginAPI.On("KVGet", "survey_deliveries_backfilled").Return(nil, nil)
+		th.MockedPluginAPI.On("KVGet", "user_survey_status_userid1_surveyid1").Return([]byte("postid1"), nil)
+
+		postID, err := th.App.GetSurveyPostIDSentToUser("userid1", "surveyid1")
+		require.NoError(t, err)
+		require.Equal(t, "postid1", postID)
+	})
+
+	t.Run("should not look in KV store once deliveries are backfilled", func(t *testing.T) {
+		th := SetupAppTest(t)
+		th.App.surveyDeliveriesBackfilled.Store(false)
+
+		th.MockedStore.On("GetSurveyDelivery", "userid1", "surveyid1").Return(nil, nil)
+		th.MockedPluginAPI.On("KVGet", "survey_deliveries_backfilled").Return([]byte("true"), nil).Once()
+
+		for i := 0; i < 2; i++ {
+			postID, err := th.App.GetSurveyPostIDSentToUser("userid1", "surveyid1")
+			require.NoError(t, err)
+			require.Empty(t, postID)
+		}
+
+		th.MockedPluginAPI.AssertExpectations(t)
+		th.MockedPluginAPI.AssertNotCalled(t, "KVGet", "user_survey_status_userid1_surveyid1")
+	})
+}
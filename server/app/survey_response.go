@@ -6,6 +6,7 @@ package app
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+	"github.com/mattermost/mattermost-plugin-user-survey/server/store"
 )
 
 const (
@@ -25,11 +27,19 @@ const (
 	postPropKeySurveyQuestions  = "survey_questions"
 	postPropSurveyID            = "survey_id"
 	postPropSurveyExpiryDate    = "survey_expire_at"
+	postPropSurveyLocale        = "survey_locale"
+	postPropResponsesEditable   = "survey_responses_editable"
 
 	postPropValueSurveyStatusSubmitted = "submitted"
 	postPropValueSurveyStatusExpired   = "ended"
+
+	acknowledgementPostMessageTranslationID = "app.survey_response.acknowledgement_message"
 )
 
+// ErrSubmissionConflict is returned when another submission of the same
+// response is being saved, in which case the client can retry the submission.
+var ErrSubmissionConflict = errors.New("another submission of the survey response is in progress")
+
 func (a *UserSurveyApp) SaveSurveyResponse(response *model.SurveyResponse) error {
 	inProgressSurvey, err := a.GetInProgressSurvey()
 	if err != nil {
@@ -42,7 +52,11 @@ func (a *UserSurveyApp) SaveSurveyResponse(response *model.SurveyResponse) error
 		return errors.New("the survey you're responding to is no longer active")
 	}
 
-	postID, err := a.GetSurveyPostIDSentToUser(response.UserID, response.SurveyID)
+	// for anonymous surveys, the response's user ID is replaced by a pseudonym below,
+	// so the user ID is kept around for the user's delivery marker and posts.
+	userID := response.UserID
+
+	postID, err := a.GetSurveyPostIDSentToUser(userID, response.SurveyID)
 	if err != nil {
 		return errors.Wrap(err, "SaveSurveyResponse: failed to fetch KV store entry for user survey")
 	}
@@ -51,12 +65,34 @@ func (a *UserSurveyApp) SaveSurveyResponse(response *model.SurveyResponse) error
 		return errors.New("the survey was not sent to the user")
 	}
 
+	surveyPost, appErr := a.api.GetPost(postID)
+	if appErr != nil {
+		a.api.LogError("SaveSurveyResponse: failed to get survey post by ID from plugin API", "postID", postID, "error", appErr.Error())
+		return errors.Wrap(errors.New(appErr.Error()), "SaveSurveyResponse: failed to get survey post by ID from plugin API")
+	}
+
+	// the response is in the locale the survey was sent in.
+	// Posts sent before surveys were localized don't have the locale prop.
+	response.Locale, _ = surveyPost.GetProp(postPropSurveyLocale).(string)
+
 	err = a.matchSurveyAndResponse(inProgressSurvey, response)
 	if err != nil {
 		a.api.LogError("SaveSurveyResponse: failed to match survey and response", "error", err.Error())
 		return errors.Wrap(err, "SaveSurveyResponse: failed to match survey and response")
 	}
 
+	// personal details are redacted before the response is validated and stored, and before it's
+	// written in the survey post, so the unredacted answers are never persisted anywhere.
+	redactionCount, err := a.redactResponse(inProgressSurvey, response)
+	if err != nil {
+		return errors.Wrap(err, "SaveSurveyResponse: failed to redact survey response")
+	}
+
+	response.UserID, err = a.getRespondentID(userID, inProgressSurvey)
+	if err != nil {
+		return errors.Wrap(err, "SaveSurveyResponse: failed to get respondent ID")
+	}
+
 	response.SetDefaults()
 	err = response.IsValid()
 	if err != nil {
@@ -69,62 +105,329 @@ func (a *UserSurveyApp) SaveSurveyResponse(response *model.SurveyResponse) error
 		return errors.Wrap(err, "SaveSurveyResponse: failed to get existing user survey response")
 	}
 
-	if existingResponse == nil {
-		err = a.store.SaveSurveyResponse(response)
-		if err != nil {
-			return errors.Wrap(err, "SaveSurveyResponse: failed to save response to database")
+	// clients send the same idempotency key when retrying a submission. If the previous attempt
+	// was saved, only the survey post is brought up to date in case updating it is what failed.
+	if existingResponse != nil && response.IdempotencyKey != "" && existingResponse.IdempotencyKey == response.IdempotencyKey {
+		if err := a.decryptResponses([]*model.SurveyResponse{existingResponse}); err != nil {
+			return errors.Wrap(err, "SaveSurveyResponse: failed to decrypt saved response")
 		}
 
-		err = a.store.IncrementSurveyResponseCount(response.SurveyID)
-		if err != nil {
-			return errors.Wrap(err, "SaveSurveyResponse: failed to increment survey response count in database")
+		if err := a.addResponseInPost(existingResponse, postID, inProgressSurvey.Anonymity.Enabled); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("SaveSurveyResponse: failed to add saved response in post, userID: %s, surveyID: %s", userID, response.SurveyID))
 		}
+
+		return nil
+	}
+
+	wasComplete := existingResponse != nil && existingResponse.ResponseType == model.ResponseTypeComplete
+
+	// a complete response can only be replaced by another complete
+	// response, and only if the survey lets users edit their responses.
+	if wasComplete && (!inProgressSurvey.ResponsesEditable || response.ResponseType != model.ResponseTypeComplete) {
+		return nil
+	}
+
+	// metadata could identify the respondents of anonymous surveys, so it isn't captured for them
+	if inProgressSurvey.Anonymity.Enabled {
+		response.Metadata = model.ResponseMetadata{}
 	} else {
-		if existingResponse.ResponseType == model.ResponseTypePartial {
-			response.ID = existingResponse.ID
-			err = a.store.UpdateSurveyResponse(response)
-			if err != nil {
-				return errors.Wrap(err, "SaveSurveyResponse: failed to update existing partial survey response")
-			}
-		} else if existingResponse.ResponseType == model.ResponseTypeComplete {
-			return nil
+		a.populateResponseMetadata(userID, surveyPost, existingResponse, response)
+	}
+
+	// answers are scored in plaintext, before they're encrypted, so the scores can be aggregated
+	response.ScoreSentiment(inProgressSurvey.SurveyQuestions.Questions)
+
+	save := &model.SurveyResponseSave{
+		Response: response,
+		Existing: existingResponse,
+	}
+
+	if existingResponse != nil {
+		response.ID = existingResponse.ID
+	}
+
+	// editing a complete response keeps the existing response as a revision
+	if wasComplete {
+		save.Revision = model.NewSurveyResponseRevision(existingResponse)
+		response.CreateAt = existingResponse.CreateAt
+		response.UpdateAt = mmModel.GetMillis()
+		response.CompletedAt = existingResponse.CompletedAt
+	} else if response.ResponseType == model.ResponseTypeComplete {
+		response.CompletedAt = mmModel.GetMillis()
+	}
+
+	save.Counts, err = a.getSurveyCountsDelta(inProgressSurvey, existingResponse, response)
+	if err != nil {
+		return errors.Wrap(err, "SaveSurveyResponse: failed to compute survey counts change")
+	}
+
+	// the redactions of a response are counted once, so saving it again only counts the redactions
+	// beyond the ones already counted for it, such as when an edit adds more personal details
+	var countedRedactions int64
+	if existingResponse != nil {
+		countedRedactions = existingResponse.RedactionCount
+	}
+
+	response.RedactionCount = max(int64(redactionCount), countedRedactions)
+	save.Counts.Redactions = int(response.RedactionCount - countedRedactions)
+	save.AnswerCounts = model.GetQuestionAnswerCountDeltas(inProgressSurvey.SurveyQuestions.Questions, existingResponse, response)
+	save.SegmentCounts = model.GetSegmentCountDeltas(inProgressSurvey, existingResponse, response)
+
+	// only the stored response is encrypted, the survey post is updated with the answers in plaintext
+	save.Response, err = a.encryptResponse(inProgressSurvey, response)
+	if err != nil {
+		return errors.Wrap(err, "SaveSurveyResponse: failed to encrypt survey response")
+	}
+
+	if err := a.store.SaveSurveyResponse(save); err != nil {
+		if errors.Is(err, store.ErrSurveyResponseConflict) {
+			return ErrSubmissionConflict
+		}
+
+		return errors.Wrap(err, "SaveSurveyResponse: failed to save response to database")
+	}
+
+	if err := a.addResponseInPost(response, postID, inProgressSurvey.Anonymity.Enabled); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("SaveSurveyResponse: failed to add submitted response in post, userID: %s, surveyID: %s responseType: %s", userID, response.SurveyID, response.ResponseType))
+	}
+
+	if response.ResponseType == model.ResponseTypeComplete && !wasComplete {
+		if err := a.sendAcknowledgementPost(userID, inProgressSurvey); err != nil {
+			return errors.Wrap(err, "SaveSurveyResponse: failed to create survey submission ack post")
 		}
 	}
 
-	if err := a.addResponseInPost(response, postID); err != nil {
-		return errors.Wrap(err, fmt.Sprintf("SaveSurveyResponse: failed to add submitted response in post, userID: %s, surveyID: %s responseType: %s", response.UserID, response.SurveyID, response.ResponseType))
+	return nil
+}
+
+// redactResponse redacts the personal details from the response's text answers
+// as configured in the plugin settings. It returns the number of redactions made.
+func (a *UserSurveyApp) redactResponse(survey *model.Survey, response *model.SurveyResponse) (int, error) {
+	redactor, err := a.getConfig().Redaction.NewRedactor()
+	if err != nil {
+		a.api.LogError("redactResponse: failed to create redactor from configured redaction settings", "error", err.Error())
+		return 0, errors.Wrap(err, "redactResponse: failed to create redactor from configured redaction settings")
+	}
+
+	return redactor.RedactResponse(response, survey.SurveyQuestions), nil
+}
+
+// getSurveyCountsDelta computes the change saving the new response makes to the survey's counters.
+func (a *UserSurveyApp) getSurveyCountsDelta(survey *model.Survey, oldResponse, newResponse *model.SurveyResponse) (model.SurveyCountsDelta, error) {
+	var delta model.SurveyCountsDelta
+
+	if oldResponse == nil {
+		delta.Responses = 1
+	}
+
+	wasComplete := oldResponse != nil && oldResponse.ResponseType == model.ResponseTypeComplete
+	if newResponse.ResponseType == model.ResponseTypeComplete && !wasComplete {
+		delta.Completed = 1
+	}
+
+	var err error
+	delta.Promoters, delta.Passives, delta.Detractors, err = a.getNPSScoreGroupFactors(survey, oldResponse, newResponse)
+	if err != nil {
+		return model.SurveyCountsDelta{}, errors.Wrap(err, "getSurveyCountsDelta: failed to get rating group changes")
+	}
+
+	return delta, nil
+}
+
+func (a *UserSurveyApp) GetSurveyResponseRevisions(surveyID, responseID string) ([]*model.SurveyResponseRevision, error) {
+	revisions, err := a.store.GetSurveyResponseRevisions(surveyID, responseID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "GetSurveyResponseRevisions: failed to get survey response revisions, surveyID: %s, responseID: %s", surveyID, responseID)
+	}
+
+	if err := a.decryptRevisions(revisions); err != nil {
+		return nil, errors.Wrapf(err, "GetSurveyResponseRevisions: failed to decrypt survey response revisions, surveyID: %s, responseID: %s", surveyID, responseID)
+	}
+
+	return revisions, nil
+}
+
+func (a *UserSurveyApp) GetSurveyResponse(userID string, survey *model.Survey) (*model.SurveyResponse, error) {
+	respondentID, err := a.getRespondentID(userID, survey)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetSurveyResponse: failed to get respondent ID")
+	}
+
+	response, err := a.store.GetSurveyResponse(respondentID, survey.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetSurveyResponse: failed to get user survey response")
 	}
 
-	if err := a.updateNPSScoreGroupCount(inProgressSurvey, existingResponse, response); err != nil {
-		return errors.Wrap(err, "SaveSurveyResponse: failed to update group counts")
+	return response, nil
+}
+
+// WithdrawSurveyResponse erases the user's response to the survey, removing
+// it from the survey statistics and from the user's survey post.
+// It returns false if the user hasn't responded to the survey.
+func (a *UserSurveyApp) WithdrawSurveyResponse(userID, surveyID string) (bool, error) {
+	survey, err := a.store.GetSurveysByID(surveyID)
+	if err != nil {
+		return false, errors.Wrapf(err, "WithdrawSurveyResponse: failed to get survey by ID, surveyID: %s", surveyID)
+	}
+
+	if survey == nil {
+		return false, nil
+	}
+
+	response, err := a.GetSurveyResponse(userID, survey)
+	if err != nil {
+		return false, errors.Wrap(err, "WithdrawSurveyResponse: failed to get user survey response")
 	}
 
+	if response == nil {
+		return false, nil
+	}
+
+	counts, err := a.getWithdrawalCountsDelta(survey, response)
+	if err != nil {
+		return false, errors.Wrapf(err, "WithdrawSurveyResponse: failed to get survey count changes, surveyID: %s", surveyID)
+	}
+
+	withdrawn, err := a.store.WithdrawSurveyResponse(&model.SurveyResponseWithdrawal{
+		Response:      response,
+		Counts:        counts,
+		AnswerCounts:  model.GetQuestionAnswerCountDeltas(survey.SurveyQuestions.Questions, response, nil),
+		SegmentCounts: model.GetSegmentCountDeltas(survey, response, nil),
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "WithdrawSurveyResponse: failed to withdraw survey response, surveyID: %s", surveyID)
+	}
+
+	if !withdrawn {
+		// another withdrawal of the response deleted it first
+		return false, nil
+	}
+
+	postID, err := a.GetSurveyPostIDSentToUser(userID, surveyID)
+	if err != nil {
+		return false, errors.Wrap(err, "WithdrawSurveyResponse: failed to get survey post sent to user")
+	}
+
+	if postID != "" {
+		if err := a.removeResponseFromPost(postID); err != nil {
+			return false, errors.Wrap(err, "WithdrawSurveyResponse: failed to remove response from survey post")
+		}
+	}
+
+	return true, nil
+}
+
+// getWithdrawalCountsDelta returns the change withdrawing the response makes to the survey's counters.
+func (a *UserSurveyApp) getWithdrawalCountsDelta(survey *model.Survey, response *model.SurveyResponse) (model.SurveyCountsDelta, error) {
+	delta := model.SurveyCountsDelta{Responses: -1}
 	if response.ResponseType == model.ResponseTypeComplete {
-		if err := a.sendAcknowledgementPost(response.UserID, postID); err != nil {
-			return errors.Wrap(err, "SaveSurveyResponse: failed to create survey submission ack post")
+		delta.Completed = -1
+	}
+
+	systemRatingQuestion, err := survey.GetSystemRatingQuestion()
+	if err != nil {
+		return model.SurveyCountsDelta{}, errors.Wrap(err, "getWithdrawalCountsDelta: failed to find a system rating question in survey")
+	}
+
+	rating, err := strconv.Atoi(response.Response[systemRatingQuestion.ID])
+	if err != nil {
+		// the rating was never counted if it isn't a number
+		a.api.LogWarn("getWithdrawalCountsDelta: response has no valid rating, skipping rating group count update", "responseID", response.ID)
+		return delta, nil
+	}
+
+	promoterFactor, neutralFactor, detractorFactor := systemRatingQuestion.GetRatingScale().GetRatingGroupFactors(rating)
+	delta.Promoters, delta.Passives, delta.Detractors = -promoterFactor, -neutralFactor, -detractorFactor
+
+	return delta, nil
+}
+
+func (a *UserSurveyApp) removeResponseFromPost(postID string) error {
+	post, appErr := a.api.GetPost(postID)
+	if appErr != nil {
+		if appErr.StatusCode == http.StatusNotFound {
+			// the user deleted the survey post, so there is nothing to remove
+			return nil
 		}
+
+		a.api.LogError("removeResponseFromPost: failed to get post by ID from plugin API", "postID", postID, "error", appErr.Error())
+		return errors.Wrap(errors.New(appErr.Error()), "removeResponseFromPost: failed to get post by ID from plugin API")
+	}
+
+	post.DelProp(postPropKeySurveyResponse)
+	post.DelProp(postPropKeyResponseCreateAt)
+	if post.GetProp(postPropKeySurveyStatus) == postPropValueSurveyStatusSubmitted {
+		post.DelProp(postPropKeySurveyStatus)
+	}
+
+	if _, appErr := a.api.UpdatePost(post); appErr != nil {
+		a.api.LogError("removeResponseFromPost: failed to update post after removing response props", "postID", postID, "error", appErr.Error())
+		return errors.Wrap(errors.New(appErr.Error()), "removeResponseFromPost: failed to update post after removing response props")
 	}
 
 	return nil
 }
 
-func (a *UserSurveyApp) addResponseInPost(response *model.SurveyResponse, postID string) error {
+// populateResponseMetadata fills in the response's metadata. The client type is set
+// by the caller, while the timings carry over from the existing response so they
+// reflect the first time the user rated and completed the survey.
+func (a *UserSurveyApp) populateResponseMetadata(userID string, surveyPost *mmModel.Post, existingResponse, response *model.SurveyResponse) {
+	now := mmModel.GetMillis()
+	metadata := &response.Metadata
+
+	if existingResponse != nil {
+		metadata.FirstRatedAt = existingResponse.Metadata.FirstRatedAt
+		metadata.CompletedAt = existingResponse.Metadata.CompletedAt
+	}
+
+	metadata.DeliveredAt = surveyPost.CreateAt
+
+	if metadata.FirstRatedAt == 0 {
+		metadata.FirstRatedAt = now
+	}
+
+	if metadata.CompletedAt == 0 && response.ResponseType == model.ResponseTypeComplete {
+		metadata.CompletedAt = now
+	}
+
+	// the teams are only context for the report, so failing to
+	// get them shouldn't prevent the user from submitting the response.
+	teams, appErr := a.api.GetTeamsForUser(userID)
+	if appErr != nil {
+		a.api.LogWarn("populateResponseMetadata: failed to get user teams", "userID", userID, "error", appErr.Error())
+		return
+	}
+
+	metadata.Teams = make([]model.ResponseTeam, 0, len(teams))
+	for _, team := range teams {
+		metadata.Teams = append(metadata.Teams, model.ResponseTeam{ID: team.Id, DisplayName: team.DisplayName})
+	}
+}
+
+func (a *UserSurveyApp) addResponseInPost(response *model.SurveyResponse, postID string, anonymous bool) error {
 	post, appErr := a.api.GetPost(postID)
 	if appErr != nil {
 		a.api.LogError("addResponseInPost: failed to get post by ID from plugin API", "postID", postID, "error", appErr.Error())
 		return errors.Wrap(errors.New(appErr.Error()), "addResponseInPost: failed to get post by ID from plugin API")
 	}
 
-	responseJSON, err := json.Marshal(response.Response)
-	if err != nil {
-		a.api.LogError("addResponseInPost: failed to marshal survey responses", "error", err.Error())
-		return errors.Wrap(err, "addResponseInPost: failed to marshal survey responses")
-	}
+	// the survey post belongs to the user, so the answers to anonymous surveys
+	// and the time they were submitted are kept out of it and only the status is updated.
+	if !anonymous {
+		responseJSON, err := json.Marshal(response.Response)
+		if err != nil {
+			a.api.LogError("addResponseInPost: failed to marshal survey responses", "error", err.Error())
+			return errors.Wrap(err, "addResponseInPost: failed to marshal survey responses")
+		}
 
-	post.AddProp(postPropKeySurveyResponse, string(responseJSON))
+		post.AddProp(postPropKeySurveyResponse, string(responseJSON))
+	}
 
 	if response.ResponseType == model.ResponseTypeComplete {
-		post.AddProp(postPropKeyResponseCreateAt, response.CreateAt)
+		if !anonymous {
+			post.AddProp(postPropKeyResponseCreateAt, response.CreateAt)
+		}
+
 		post.AddProp(postPropKeySurveyStatus, postPropValueSurveyStatusSubmitted)
 	}
 
@@ -137,23 +440,28 @@ func (a *UserSurveyApp) addResponseInPost(response *model.SurveyResponse, postID
 	return nil
 }
 
-func (a *UserSurveyApp) sendAcknowledgementPost(userID, surveyPostID string) error {
-	if a.botID == "" {
-		if err := a.ensureSurveyBot(); err != nil {
-			return errors.Wrap(err, "sendAcknowledgementPost: failed to ensure bot")
-		}
+func (a *UserSurveyApp) sendAcknowledgementPost(userID string, survey *model.Survey) error {
+	botID, err := a.getSurveyBotID(survey)
+	if err != nil {
+		return errors.Wrap(err, "sendAcknowledgementPost: failed to get survey bot")
+	}
+
+	user, appErr := a.api.GetUser(userID)
+	if appErr != nil {
+		a.api.LogError("sendAcknowledgementPost: failed to get user from ID", "userID", userID, "error", appErr.Error())
+		return errors.Wrap(errors.New(appErr.Error()), "sendAcknowledgementPost: failed to get user from ID: "+userID)
 	}
 
-	botUserDM, appErr := a.api.GetDirectChannel(userID, a.botID)
+	botUserDM, appErr := a.api.GetDirectChannel(userID, botID)
 	if appErr != nil {
-		errMsg := fmt.Sprintf("sendAcknowledgementPost: failed to create DM between survey bot and user, botID: %s, userID: %s, error: %s", a.botID, userID, appErr.Error())
+		errMsg := fmt.Sprintf("sendAcknowledgementPost: failed to create DM between survey bot and user, botID: %s, userID: %s, error: %s", botID, userID, appErr.Error())
 		a.api.LogError(errMsg)
 		return errors.Wrap(errors.New(appErr.Error()), errMsg)
 	}
 
 	post := &mmModel.Post{
-		UserId:    a.botID,
-		Message:   ":tada: Thank you for sharing your feedback!",
+		UserId:    botID,
+		Message:   a.renderMessage(survey.Customization.AcknowledgementMessage, a.i18n.GetUserTranslations(user.Locale)(acknowledgementPostMessageTranslationID), user),
 		ChannelId: botUserDM.Id,
 	}
 
@@ -205,6 +513,19 @@ func (a *UserSurveyApp) matchSurveyAndResponse(survey *model.Survey, response *m
 		response.ResponseType = model.ResponseTypeComplete
 	}
 
+	// ratings must be whole numbers on their question's scale
+	for _, question := range survey.SurveyQuestions.Questions {
+		answer, ok := response.Response[question.ID]
+		if !ok || question.Type != model.QuestionTypeLinearScale {
+			continue
+		}
+
+		rating, err := strconv.Atoi(answer)
+		if err != nil || !question.GetRatingScale().Contains(rating) {
+			return errors.New("rating is not on the question's scale, questionID: " + question.ID)
+		}
+	}
+
 	return nil
 }
 
@@ -248,7 +569,7 @@ func (a *UserSurveyApp) setExpiredPropsInPost(post *mmModel.Post, expiredSurvey
 	return nil
 }
 
-func (a *UserSurveyApp) updateNPSScoreGroupCount(survey *model.Survey, oldResponse, newResponse *model.SurveyResponse) error {
+func (a *UserSurveyApp) getNPSScoreGroupFactors(survey *model.Survey, oldResponse, newResponse *model.SurveyResponse) (promoterFactor, neutralFactor, detractorFactor int, err error) {
 	// survey table has a column each for number of promoters, neutral and detractors.
 	// Depending on the new score, we need to increment the corresponding columns, and
 	// depending on the old score, decrement its corresponding column value.
@@ -257,85 +578,54 @@ func (a *UserSurveyApp) updateNPSScoreGroupCount(survey *model.Survey, oldRespon
 	// when user clicks the "submit" button, it is possible to have an existing response saved
 	// in the database already.
 	//
-	// This function computes three values, either 1 or -1, one for each promoter, neutral and detractors columns.
-	// We then add the computed values corresponding to each column in the database, effectively
+	// This function computes three values, either 1, -1 or 0, one for each promoter, neutral and detractors columns.
+	// The computed values are then added to each column in the database, effectively
 	// increasing the column corresponding the new score by 1 and incrementing the column
 	// corresponding to the old score by -1 (incrementing by -1 is same as decrementing by 1, incrementing by -1 here to keep SQL queries simple).
 
-	systemRatingQuestionID, err := survey.GetSystemRatingQuestionID()
+	systemRatingQuestion, err := survey.GetSystemRatingQuestion()
 	if err != nil {
-		return errors.Wrap(err, "updateNPSScoreGroupCount: failed to find a system rating question in survey")
+		return 0, 0, 0, errors.Wrap(err, "getNPSScoreGroupFactors: failed to find a system rating question in survey")
 	}
 
+	systemRatingQuestionID := systemRatingQuestion.ID
+	ratingScale := systemRatingQuestion.GetRatingScale()
+
 	var oldPromoterFactor, oldNeutralFactor, oldDetractorFactor int
 	var newPromoterFactor, newNeutralFactor, newDetractorFactor int
 
 	newRating, err := strconv.Atoi(newResponse.Response[systemRatingQuestionID])
 	if err != nil {
-		a.api.LogError("updateNPSScoreGroupCount: failed to convert new rating value from string to number")
-		return errors.Wrap(err, "updateNPSScoreGroupCount: failed to convert new rating value from string to number")
+		a.api.LogError("getNPSScoreGroupFactors: failed to convert new rating value from string to number")
+		return 0, 0, 0, errors.Wrap(err, "getNPSScoreGroupFactors: failed to convert new rating value from string to number")
 	}
 
-	newPromoterFactor, newNeutralFactor, newDetractorFactor = a.getRatingGroupFactors(newRating)
+	newPromoterFactor, newNeutralFactor, newDetractorFactor = ratingScale.GetRatingGroupFactors(newRating)
 
 	if oldResponse != nil {
 		oldRating, err := strconv.Atoi(oldResponse.Response[systemRatingQuestionID])
 		if err != nil {
-			a.api.LogError("updateNPSScoreGroupCount: failed to convert new rating value from string to number")
-			return errors.Wrap(err, "updateNPSScoreGroupCount: failed to convert new rating value from string to number")
+			a.api.LogError("getNPSScoreGroupFactors: failed to convert old rating value from string to number")
+			return 0, 0, 0, errors.Wrap(err, "getNPSScoreGroupFactors: failed to convert old rating value from string to number")
 		}
 
-		if !a.hasRatingGroupChanged(oldRating, newRating) {
+		if !ratingScale.HasRatingGroupChanged(oldRating, newRating) {
 			// nothing to do if rating groups are unchanged
-			return nil
+			return 0, 0, 0, nil
 		}
 
-		oldPromoterFactor, oldNeutralFactor, oldDetractorFactor = a.getRatingGroupFactors(oldRating)
+		oldPromoterFactor, oldNeutralFactor, oldDetractorFactor = ratingScale.GetRatingGroupFactors(oldRating)
 
 		oldPromoterFactor *= -1
 		oldNeutralFactor *= -1
 		oldDetractorFactor *= -1
 	}
 
-	promoterFactor := utils.CoalesceInt(oldPromoterFactor, newPromoterFactor)
-	neutralFactor := utils.CoalesceInt(oldNeutralFactor, newNeutralFactor)
-	detractorFactor := utils.CoalesceInt(oldDetractorFactor, newDetractorFactor)
-
-	if err := a.store.UpdateRatingGroupCount(survey.ID, promoterFactor, neutralFactor, detractorFactor); err != nil {
-		return errors.Wrap(err, "updateNPSScoreGroupCount: failed to update survey rating group count")
-	}
+	promoterFactor = utils.CoalesceInt(oldPromoterFactor, newPromoterFactor)
+	neutralFactor = utils.CoalesceInt(oldNeutralFactor, newNeutralFactor)
+	detractorFactor = utils.CoalesceInt(oldDetractorFactor, newDetractorFactor)
 
-	return nil
-}
-
-func (a *UserSurveyApp) getRatingGroupFactors(rating int) (promoterFactor, neutralFactor, detractorFactor int) {
-	switch {
-	case rating >= 1 && rating <= 6:
-		detractorFactor = 1
-	case rating <= 8:
-		neutralFactor = 1
-	case rating <= 10:
-		promoterFactor = 1
-	}
-
-	return promoterFactor, neutralFactor, detractorFactor
-}
-
-func (a *UserSurveyApp) hasRatingGroupChanged(oldRating, newRating int) bool {
-	if newRating == oldRating {
-		return false
-	}
-
-	switch {
-	case oldRating >= 1 && oldRating <= 6 && newRating >= 1 && newRating <= 6:
-		return false
-	case oldRating >= 7 && oldRating <= 8 && newRating >= 7 && newRating <= 8:
-		return false
-	case oldRating >= 9 && oldRating <= 10 && newRating >= 9 && newRating <= 10:
-		return false
-	default:
-		return true
-	}
+	return promoterFactor, neutralFactor, detractorFactor, nil
 }
 
 func (a *UserSurveyApp) HandleRefreshSurveyPost(userID, postID string) error {
@@ -382,6 +672,12 @@ func (a *UserSurveyApp) HandleRefreshSurveyPost(userID, postID string) error {
 	}
 
 	if survey != nil && survey.Status == model.SurveyStatusInProgress {
+		// the post is refreshed when the user views it, so
+		// this is when the user opens the running survey.
+		if _, err := a.store.MarkSurveyDeliveryOpened(userID, surveyID); err != nil {
+			return errors.Wrapf(err, "HandleRefreshSurveyPost: failed to mark survey as opened, userID: %s, surveyID: %s", userID, surveyID)
+		}
+
 		// nothing to update in post if survey is still running
 		return nil
 	}
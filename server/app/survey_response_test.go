@@ -4,13 +4,18 @@
 package app
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
 	mmModal "github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+	"github.com/mattermost/mattermost-plugin-user-survey/server/store"
+	"github.com/mattermost/mattermost-plugin-user-survey/server/utils"
 )
 
 func TestUserSurveyApp_SaveSurveyResponse(t *testing.T) {
@@ -32,24 +37,279 @@ func TestUserSurveyApp_SaveSurveyResponse(t *testing.T) {
 
 		th.MockedStore.On("GetSurveysByStatus", "in_progress").Return([]*model.Survey{survey}, nil)
 		th.MockedStore.On("GetSurveyResponse", "user_1", "survey_id_1").Return(nil, nil)
-		th.MockedStore.On("SaveSurveyResponse", mock.Anything).Return(nil)
-		th.MockedStore.On("IncrementSurveyResponseCount", "survey_id_1").Return(nil)
-		th.MockedStore.On("UpdateRatingGroupCount", "survey_id_1", 1, 0, 0).Return(nil)
+		th.MockedStore.On("SaveSurveyResponse", mock.MatchedBy(func(save *model.SurveyResponseSave) bool {
+			return save.Existing == nil &&
+				save.Revision == nil &&
+				save.Counts == model.SurveyCountsDelta{Responses: 1, Promoters: 1}
+		})).Return(nil)
+
+		th.MockedPluginAPI.On("GetPost", "post_id_1").Return(&mmModal.Post{CreateAt: 1000}, nil)
+		th.MockedPluginAPI.On("UpdatePost", mock.Anything).Return(&mmModal.Post{}, nil)
+		th.MockedStore.On("GetSurveyDelivery", "user_1", "survey_id_1").Return(&model.SurveyDelivery{PostID: "post_id_1"}, nil)
+		th.MockedPluginAPI.On("GetTeamsForUser", "user_1").Return([]*mmModal.Team{{Id: "team_id_1", DisplayName: "Team 1"}}, nil)
+
+		response := &model.SurveyResponse{
+			SurveyID: "survey_id_1",
+			UserID:   "user_1",
+			Response: map[string]string{
+				"question_id_1": "10",
+			},
+			Metadata: model.ResponseMetadata{ClientType: model.ClientTypeDesktop},
+		}
+
+		err := th.App.SaveSurveyResponse(response)
+		require.NoError(t, err)
+
+		require.Equal(t, model.ClientTypeDesktop, response.Metadata.ClientType)
+		require.Equal(t, int64(1000), response.Metadata.DeliveredAt)
+		require.Greater(t, response.Metadata.FirstRatedAt, int64(1000))
+		require.Zero(t, response.Metadata.CompletedAt)
+		require.Equal(t, []model.ResponseTeam{{ID: "team_id_1", DisplayName: "Team 1"}}, response.Metadata.Teams)
+	})
+
+	t.Run("should keep first rating time and set completion time when completing a partial response", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		survey := &model.Survey{
+			ID: "survey_id_1",
+			SurveyQuestions: model.SurveyQuestions{
+				Questions: []model.Question{
+					{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale},
+					{ID: "question_id_2", Type: model.QuestionType},
+				},
+			},
+		}
+
+		existingResponse := &model.SurveyResponse{
+			ID:           "response_id",
+			SurveyID:     "survey_id_1",
+			UserID:       "user_1",
+			Response:     map[string]string{"question_id_1": "10"},
+			ResponseType: model.ResponseTypePartial,
+			Metadata:     model.ResponseMetadata{DeliveredAt: 1000, FirstRatedAt: 2000},
+		}
+
+		th.MockedStore.On("GetSurveysByStatus", "in_progress").Return([]*model.Survey{survey}, nil)
+		th.MockedStore.On("GetSurveyResponse", "user_1", "survey_id_1").Return(existingResponse, nil)
+		th.MockedStore.On("SaveSurveyResponse", mock.MatchedBy(func(save *model.SurveyResponseSave) bool {
+			return save.Existing == existingResponse &&
+				save.Response.ID == "response_id" &&
+				save.Response.Metadata.FirstRatedAt == 2000 &&
+				save.Response.Metadata.CompletedAt > 2000 &&
+				save.Response.CompletedAt > 2000 &&
+				save.Counts == model.SurveyCountsDelta{Completed: 1} &&
+				// the unchanged rating is already counted, so only the new text answer is
+				reflect.DeepEqual(save.AnswerCounts, []model.QuestionAnswerCountDelta{
+					{QuestionAnswerKey: model.QuestionAnswerKey{QuestionID: "question_id_2"}, Delta: 1},
+				})
+		})).Return(nil)
+
+		th.MockedPluginAPI.On("GetPost", "post_id_1").Return(&mmModal.Post{CreateAt: 1000}, nil)
+		th.MockedPluginAPI.On("UpdatePost", mock.Anything).Return(&mmModal.Post{}, nil)
+		th.MockedStore.On("GetSurveyDelivery", "user_1", "survey_id_1").Return(&model.SurveyDelivery{PostID: "post_id_1"}, nil)
+		th.MockedPluginAPI.On("GetTeamsForUser", "user_1").Return(nil, &mmModal.AppError{Message: "error"})
+		th.MockedPluginAPI.On("GetUser", "user_1").Return(&mmModal.User{Id: "user_1"}, nil)
+		th.MockedPluginAPI.On("GetDirectChannel", "user_1", "bot_user_id").Return(&mmModal.Channel{Id: "channel_id"}, nil)
+		th.MockedPluginAPI.On("CreatePost", mock.Anything).Return(&mmModal.Post{}, nil)
+
+		response := &model.SurveyResponse{
+			SurveyID: "survey_id_1",
+			UserID:   "user_1",
+			Response: map[string]string{"question_id_1": "10", "question_id_2": "Great"},
+		}
+
+		err := th.App.SaveSurveyResponse(response)
+		require.NoError(t, err)
+		th.MockedStore.AssertExpectations(t)
+		require.Empty(t, response.Metadata.Teams)
+	})
+
+	t.Run("should redact personal details from text answers before saving them", func(t *testing.T) {
+		th := SetupAppTest(t)
+		th.App.getConfig = func() *model.Config {
+			return &model.Config{
+				Redaction: model.Redaction{
+					Enabled:        true,
+					CustomPatterns: []model.RedactionPattern{{Name: "employee ID", Pattern: `EMP-\d+`}},
+				},
+			}
+		}
+
+		survey := &model.Survey{
+			ID: "survey_id_1",
+			SurveyQuestions: model.SurveyQuestions{
+				Questions: []model.Question{
+					{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale},
+					{ID: "question_id_2", Type: model.QuestionType},
+				},
+			},
+		}
+
+		redactedAnswer := "Ask [redacted mention] ([redacted employee ID]) at [redacted email] or [redacted phone]"
+
+		th.MockedStore.On("GetSurveysByStatus", "in_progress").Return([]*model.Survey{survey}, nil)
+		th.MockedStore.On("GetSurveyResponse", "user_1", "survey_id_1").Return(nil, nil)
+		th.MockedStore.On("SaveSurveyResponse", mock.MatchedBy(func(save *model.SurveyResponseSave) bool {
+			return save.Response.Response["question_id_1"] == "10" &&
+				save.Response.Response["question_id_2"] == redactedAnswer &&
+				save.Counts == model.SurveyCountsDelta{Responses: 1, Completed: 1, Promoters: 1, Redactions: 4}
+		})).Return(nil)
+
+		th.MockedPluginAPI.On("GetPost", "post_id_1").Return(&mmModal.Post{CreateAt: 1000}, nil)
+		th.MockedPluginAPI.On("UpdatePost", mock.MatchedBy(func(post *mmModal.Post) bool {
+			postResponse, ok := post.GetProp("survey_response").(string)
+			return ok && strings.Contains(postResponse, redactedAnswer)
+		})).Return(&mmModal.Post{}, nil)
+		th.MockedStore.On("GetSurveyDelivery", "user_1", "survey_id_1").Return(&model.SurveyDelivery{PostID: "post_id_1"}, nil)
+		th.MockedPluginAPI.On("GetTeamsForUser", "user_1").Return([]*mmModal.Team{}, nil)
+		th.MockedPluginAPI.On("GetUser", "user_1").Return(&mmModal.User{Id: "user_1"}, nil)
+		th.MockedPluginAPI.On("GetDirectChannel", "user_1", "bot_user_id").Return(&mmModal.Channel{Id: "channel_id"}, nil)
+		th.MockedPluginAPI.On("CreatePost", mock.Anything).Return(&mmModal.Post{}, nil)
+
+		response := &model.SurveyResponse{
+			SurveyID: "survey_id_1",
+			UserID:   "user_1",
+			Response: map[string]string{
+				"question_id_1": "10",
+				"question_id_2": "Ask @jane.doe (EMP-1234) at jane@example.com or +1 555 123 4567",
+			},
+		}
+
+		err := th.App.SaveSurveyResponse(response)
+		require.NoError(t, err)
+		th.MockedStore.AssertExpectations(t)
+		th.MockedPluginAPI.AssertExpectations(t)
+	})
+
+	t.Run("should only count the redactions an edit adds to the response", func(t *testing.T) {
+		th := SetupAppTest(t)
+		th.App.getConfig = func() *model.Config {
+			return &model.Config{Redaction: model.Redaction{Enabled: true}}
+		}
+
+		survey := &model.Survey{
+			ID: "survey_id_1",
+			SurveyQuestions: model.SurveyQuestions{
+				Questions: []model.Question{
+					{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale},
+					{ID: "question_id_2", Type: model.QuestionType},
+				},
+			},
+			ResponsesEditable: true,
+		}
+
+		existingResponse := &model.SurveyResponse{
+			ID:             "response_id",
+			SurveyID:       "survey_id_1",
+			UserID:         "user_1",
+			Response:       map[string]string{"question_id_1": "10", "question_id_2": "Write to [redacted email]"},
+			CreateAt:       1000,
+			CompletedAt:    1500,
+			ResponseType:   model.ResponseTypeComplete,
+			RedactionCount: 1,
+		}
+
+		th.MockedStore.On("GetSurveysByStatus", "in_progress").Return([]*model.Survey{survey}, nil)
+		th.MockedStore.On("GetSurveyResponse", "user_1", "survey_id_1").Return(existingResponse, nil)
+		th.MockedStore.On("SaveSurveyResponse", mock.MatchedBy(func(save *model.SurveyResponseSave) bool {
+			return save.Response.RedactionCount == 2 &&
+				save.Counts == model.SurveyCountsDelta{Redactions: 1}
+		})).Return(nil)
 
 		th.MockedPluginAPI.On("GetPost", "post_id_1").Return(&mmModal.Post{}, nil)
 		th.MockedPluginAPI.On("UpdatePost", mock.Anything).Return(&mmModal.Post{}, nil)
-		th.MockedPluginAPI.On("KVGet", "user_survey_status_user_1_survey_id_1").Return([]byte("post_id_1"), nil)
+		th.MockedStore.On("GetSurveyDelivery", "user_1", "survey_id_1").Return(&model.SurveyDelivery{PostID: "post_id_1"}, nil)
+		th.MockedPluginAPI.On("GetTeamsForUser", "user_1").Return([]*mmModal.Team{}, nil)
 
 		response := &model.SurveyResponse{
 			SurveyID: "survey_id_1",
 			UserID:   "user_1",
 			Response: map[string]string{
 				"question_id_1": "10",
+				"question_id_2": "Write to jane@example.com or +1 555 123 4567",
+			},
+		}
+
+		err := th.App.SaveSurveyResponse(response)
+		require.NoError(t, err)
+		th.MockedStore.AssertExpectations(t)
+	})
+
+	t.Run("should score the sentiment of text answers", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		survey := &model.Survey{
+			ID: "survey_id_1",
+			SurveyQuestions: model.SurveyQuestions{
+				Questions: []model.Question{
+					{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale},
+					{ID: "question_id_2", Type: model.QuestionType},
+					{ID: "question_id_3", Type: model.QuestionType},
+				},
+			},
+		}
+
+		th.MockedStore.On("GetSurveysByStatus", "in_progress").Return([]*model.Survey{survey}, nil)
+		th.MockedStore.On("GetSurveyResponse", "user_1", "survey_id_1").Return(nil, nil)
+		th.MockedStore.On("SaveSurveyResponse", mock.MatchedBy(func(save *model.SurveyResponseSave) bool {
+			_, scoredEmptyAnswer := save.Response.Sentiment["question_id_3"]
+			return save.Response.Sentiment["question_id_2"] < 0 && !scoredEmptyAnswer && len(save.Response.Sentiment) == 1
+		})).Return(nil)
+
+		th.MockedPluginAPI.On("GetPost", "post_id_1").Return(&mmModal.Post{CreateAt: 1000}, nil)
+		th.MockedPluginAPI.On("UpdatePost", mock.Anything).Return(&mmModal.Post{}, nil)
+		th.MockedStore.On("GetSurveyDelivery", "user_1", "survey_id_1").Return(&model.SurveyDelivery{PostID: "post_id_1"}, nil)
+		th.MockedPluginAPI.On("GetTeamsForUser", "user_1").Return([]*mmModal.Team{}, nil)
+		th.MockedPluginAPI.On("GetUser", "user_1").Return(&mmModal.User{Id: "user_1"}, nil)
+		th.MockedPluginAPI.On("GetDirectChannel", "user_1", "bot_user_id").Return(&mmModal.Channel{Id: "channel_id"}, nil)
+		th.MockedPluginAPI.On("CreatePost", mock.Anything).Return(&mmModal.Post{}, nil)
+
+		response := &model.SurveyResponse{
+			SurveyID: "survey_id_1",
+			UserID:   "user_1",
+			Response: map[string]string{
+				"question_id_1": "3",
+				"question_id_2": "Search is slow and keeps crashing",
+				"question_id_3": " ",
 			},
 		}
 
 		err := th.App.SaveSurveyResponse(response)
 		require.NoError(t, err)
+		th.MockedStore.AssertExpectations(t)
+	})
+
+	t.Run("should reject ratings that aren't on the question's scale", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		survey := &model.Survey{
+			ID: "survey_id_1",
+			SurveyQuestions: model.SurveyQuestions{
+				Questions: []model.Question{
+					{
+						ID:     "question_id_1",
+						System: true,
+						Type:   model.QuestionTypeLinearScale,
+						Scale:  &model.RatingScale{Min: 1, Max: 5, DetractorMax: 2, PassiveMax: 3},
+					},
+				},
+			},
+		}
+
+		th.MockedStore.On("GetSurveysByStatus", "in_progress").Return([]*model.Survey{survey}, nil)
+		th.MockedStore.On("GetSurveyDelivery", "user_1", "survey_id_1").Return(&model.SurveyDelivery{PostID: "post_id_1"}, nil)
+		th.MockedPluginAPI.On("GetPost", "post_id_1").Return(&mmModal.Post{}, nil)
+
+		response := &model.SurveyResponse{
+			SurveyID: "survey_id_1",
+			UserID:   "user_1",
+			Response: map[string]string{"question_id_1": "9"},
+		}
+
+		err := th.App.SaveSurveyResponse(response)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "rating is not on the question's scale")
+		th.MockedStore.AssertNotCalled(t, "SaveSurveyResponse", mock.Anything)
 	})
 
 	t.Run("should not allow submission from user who was never sent this survey", func(t *testing.T) {
@@ -70,7 +330,7 @@ func TestUserSurveyApp_SaveSurveyResponse(t *testing.T) {
 
 		th.MockedStore.On("GetSurveysByStatus", "in_progress").Return([]*model.Survey{survey}, nil)
 		th.MockedStore.On("GetSurveyResponse", "user_1", "survey_id_1").Return(nil, nil)
-		th.MockedPluginAPI.On("KVGet", "user_survey_status_user_1_survey_id_1").Return(nil, nil)
+		th.MockedStore.On("GetSurveyDelivery", "user_1", "survey_id_1").Return(nil, nil)
 
 		response := &model.SurveyResponse{
 			SurveyID: "survey_id_1",
@@ -81,4 +341,328 @@ func TestUserSurveyApp_SaveSurveyResponse(t *testing.T) {
 		require.Error(t, err)
 		require.Equal(t, err.Error(), "the survey was not sent to the user")
 	})
+
+	t.Run("should store anonymous response under pseudonym and keep answers and submission time out of the post", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		survey := &model.Survey{
+			ID: "survey_id_1",
+			SurveyQuestions: model.SurveyQuestions{
+				Questions: []model.Question{
+					{
+						ID:     "question_id_1",
+						System: true,
+						Type:   model.QuestionTypeLinearScale,
+					},
+				},
+			},
+			Anonymity: model.Anonymity{Enabled: true},
+		}
+
+		secret := []byte("secret")
+		pseudonym := utils.NewPseudonymID(secret, "survey_id_1", "user_1")
+
+		th.MockedStore.On("GetSurveysByStatus", "in_progress").Return([]*model.Survey{survey}, nil)
+		th.MockedStore.On("GetSurveyResponse", pseudonym, "survey_id_1").Return(nil, nil)
+		th.MockedStore.On("SaveSurveyResponse", mock.MatchedBy(func(save *model.SurveyResponseSave) bool {
+			return save.Response.UserID == pseudonym
+		})).Return(nil)
+
+		th.MockedPluginAPI.On("KVGet", "survey_pseudonym_secret").Return(secret, nil).Once()
+		th.MockedPluginAPI.On("GetPost", "post_id_1").Return(&mmModal.Post{}, nil)
+		th.MockedPluginAPI.On("UpdatePost", mock.MatchedBy(func(post *mmModal.Post) bool {
+			return post.GetProp("survey_response") == nil &&
+				post.GetProp("survey_response_create_at") == nil &&
+				post.GetProp("survey_status") == "submitted"
+		})).Return(&mmModal.Post{}, nil)
+		th.MockedStore.On("GetSurveyDelivery", "user_1", "survey_id_1").Return(&model.SurveyDelivery{PostID: "post_id_1"}, nil)
+		th.MockedPluginAPI.On("GetUser", "user_1").Return(&mmModal.User{Id: "user_1"}, nil)
+		th.MockedPluginAPI.On("GetDirectChannel", "user_1", "bot_user_id").Return(&mmModal.Channel{Id: "channel_id"}, nil)
+		th.MockedPluginAPI.On("CreatePost", mock.Anything).Return(&mmModal.Post{}, nil)
+
+		response := &model.SurveyResponse{
+			SurveyID: "survey_id_1",
+			UserID:   "user_1",
+			Response: map[string]string{
+				"question_id_1": "10",
+			},
+			ResponseType: model.ResponseTypeComplete,
+		}
+
+		err := th.App.SaveSurveyResponse(response)
+		require.NoError(t, err)
+		th.MockedStore.AssertExpectations(t)
+		th.MockedPluginAPI.AssertExpectations(t)
+	})
+
+	t.Run("should ignore resubmission of complete response if survey isn't editable", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		survey := &model.Survey{
+			ID: "survey_id_1",
+			SurveyQuestions: model.SurveyQuestions{
+				Questions: []model.Question{
+					{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale},
+					{ID: "question_id_2", Type: model.QuestionType},
+				},
+			},
+		}
+
+		existingResponse := &model.SurveyResponse{
+			ID:           "response_id",
+			SurveyID:     "survey_id_1",
+			UserID:       "user_1",
+			Response:     map[string]string{"question_id_1": "10", "question_id_2": "Great"},
+			ResponseType: model.ResponseTypeComplete,
+		}
+
+		th.MockedStore.On("GetSurveysByStatus", "in_progress").Return([]*model.Survey{survey}, nil)
+		th.MockedStore.On("GetSurveyResponse", "user_1", "survey_id_1").Return(existingResponse, nil)
+		th.MockedPluginAPI.On("GetPost", "post_id_1").Return(&mmModal.Post{}, nil)
+		th.MockedStore.On("GetSurveyDelivery", "user_1", "survey_id_1").Return(&model.SurveyDelivery{PostID: "post_id_1"}, nil)
+		th.MockedPluginAPI.On("GetTeamsForUser", "user_1").Return([]*mmModal.Team{}, nil)
+
+		response := &model.SurveyResponse{
+			SurveyID: "survey_id_1",
+			UserID:   "user_1",
+			Response: map[string]string{"question_id_1": "3", "question_id_2": "Bad"},
+		}
+
+		err := th.App.SaveSurveyResponse(response)
+		require.NoError(t, err)
+		th.MockedStore.AssertNotCalled(t, "SaveSurveyResponse", mock.Anything)
+	})
+
+	t.Run("should edit complete response and keep revision if survey is editable", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		survey := &model.Survey{
+			ID: "survey_id_1",
+			SurveyQuestions: model.SurveyQuestions{
+				Questions: []model.Question{
+					{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale},
+					{ID: "question_id_2", Type: model.QuestionType},
+				},
+			},
+			ResponsesEditable: true,
+		}
+
+		existingResponse := &model.SurveyResponse{
+			ID:           "response_id",
+			SurveyID:     "survey_id_1",
+			UserID:       "user_1",
+			Response:     map[string]string{"question_id_1": "10", "question_id_2": "Great"},
+			CreateAt:     1000,
+			CompletedAt:  1500,
+			ResponseType: model.ResponseTypeComplete,
+		}
+
+		th.MockedStore.On("GetSurveysByStatus", "in_progress").Return([]*model.Survey{survey}, nil)
+		th.MockedStore.On("GetSurveyResponse", "user_1", "survey_id_1").Return(existingResponse, nil)
+		th.MockedStore.On("SaveSurveyResponse", mock.MatchedBy(func(save *model.SurveyResponseSave) bool {
+			response, revision := save.Response, save.Revision
+			return response.ID == "response_id" &&
+				response.CreateAt == 1000 &&
+				response.UpdateAt > 1000 &&
+				response.CompletedAt == 1500 &&
+				response.Response["question_id_1"] == "3" &&
+				revision != nil &&
+				revision.ResponseID == "response_id" &&
+				revision.CreateAt == 1000 &&
+				revision.Response["question_id_1"] == "10" &&
+				revision.Response["question_id_2"] == "Great" &&
+				// the response moves from promoters to detractors
+				save.Counts == model.SurveyCountsDelta{Promoters: -1, Detractors: 1}
+		})).Return(nil)
+
+		th.MockedPluginAPI.On("GetPost", "post_id_1").Return(&mmModal.Post{}, nil)
+		th.MockedPluginAPI.On("UpdatePost", mock.MatchedBy(func(post *mmModal.Post) bool {
+			return post.GetProp("survey_response") == `{"question_id_1":"3","question_id_2":"Bad"}`
+		})).Return(&mmModal.Post{}, nil)
+		th.MockedStore.On("GetSurveyDelivery", "user_1", "survey_id_1").Return(&model.SurveyDelivery{PostID: "post_id_1"}, nil)
+		th.MockedPluginAPI.On("GetTeamsForUser", "user_1").Return([]*mmModal.Team{}, nil)
+
+		response := &model.SurveyResponse{
+			SurveyID: "survey_id_1",
+			UserID:   "user_1",
+			Response: map[string]string{"question_id_1": "3", "question_id_2": "Bad"},
+		}
+
+		err := th.App.SaveSurveyResponse(response)
+		require.NoError(t, err)
+		th.MockedStore.AssertExpectations(t)
+		th.MockedPluginAPI.AssertExpectations(t)
+
+		// the acknowledgement is only sent for the first submission
+		th.MockedPluginAPI.AssertNotCalled(t, "CreatePost", mock.Anything)
+	})
+}
+
+func TestWithdrawSurveyResponse(t *testing.T) {
+	survey := &model.Survey{
+		ID: "survey_id_1",
+		SurveyQuestions: model.SurveyQuestions{
+			Questions: []model.Question{
+				{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale},
+				{ID: "question_id_2", Type: model.QuestionType},
+			},
+		},
+	}
+
+	t.Run("should delete response, update counts and clear post props", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		th.MockedStore.On("GetSurveysByID", "survey_id_1").Return(survey, nil)
+		response := &model.SurveyResponse{
+			ID:           "response_id",
+			SurveyID:     "survey_id_1",
+			Response:     map[string]string{"question_id_1": "7", "question_id_2": "Okay"},
+			ResponseType: model.ResponseTypeComplete,
+			Metadata:     model.ResponseMetadata{Teams: []model.ResponseTeam{testTeamA}},
+		}
+		th.MockedStore.On("GetSurveyResponse", "user_1", "survey_id_1").Return(response, nil)
+		th.MockedStore.On("WithdrawSurveyResponse", &model.SurveyResponseWithdrawal{
+			Response: response,
+			Counts:   model.SurveyCountsDelta{Responses: -1, Completed: -1, Passives: -1},
+			AnswerCounts: []model.QuestionAnswerCountDelta{
+				{QuestionAnswerKey: model.QuestionAnswerKey{QuestionID: "question_id_1", Answer: "7"}, Delta: -1},
+				{QuestionAnswerKey: model.QuestionAnswerKey{QuestionID: "question_id_2"}, Delta: -1},
+			},
+			SegmentCounts: []model.SegmentCountsDelta{
+				{SegmentKey: model.SegmentKey{Type: model.SegmentTypeTeam, ID: "team_a"}, SegmentCounts: model.SegmentCounts{Passives: -1}},
+			},
+		}).Return(true, nil)
+
+		post := &mmModal.Post{Id: "post_id_1"}
+		post.AddProp("survey_response", `{"question_id_1":"7","question_id_2":"Okay"}`)
+		post.AddProp("survey_response_create_at", int64(1000))
+		post.AddProp("survey_status", "submitted")
+		post.AddProp("survey_id", "survey_id_1")
+
+		th.MockedStore.On("GetSurveyDelivery", "user_1", "survey_id_1").Return(&model.SurveyDelivery{PostID: "post_id_1"}, nil)
+		th.MockedPluginAPI.On("GetPost", "post_id_1").Return(post, nil)
+		th.MockedPluginAPI.On("UpdatePost", mock.MatchedBy(func(post *mmModal.Post) bool {
+			return post.GetProp("survey_response") == nil &&
+				post.GetProp("survey_response_create_at") == nil &&
+				post.GetProp("survey_status") == nil &&
+				post.GetProp("survey_id") == "survey_id_1"
+		})).Return(post, nil)
+
+		withdrawn, err := th.App.WithdrawSurveyResponse("user_1", "survey_id_1")
+		require.NoError(t, err)
+		require.True(t, withdrawn)
+		th.MockedStore.AssertExpectations(t)
+		th.MockedPluginAPI.AssertExpectations(t)
+	})
+
+	t.Run("should do nothing if user hasn't responded", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		th.MockedStore.On("GetSurveysByID", "survey_id_1").Return(survey, nil)
+		th.MockedStore.On("GetSurveyResponse", "user_1", "survey_id_1").Return(nil, nil)
+
+		withdrawn, err := th.App.WithdrawSurveyResponse("user_1", "survey_id_1")
+		require.NoError(t, err)
+		require.False(t, withdrawn)
+		th.MockedStore.AssertNotCalled(t, "WithdrawSurveyResponse", mock.Anything)
+	})
+
+	t.Run("should leave post alone if response was already withdrawn", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		th.MockedStore.On("GetSurveysByID", "survey_id_1").Return(survey, nil)
+		th.MockedStore.On("GetSurveyResponse", "user_1", "survey_id_1").Return(&model.SurveyResponse{
+			ID:           "response_id",
+			SurveyID:     "survey_id_1",
+			Response:     map[string]string{"question_id_1": "7"},
+			ResponseType: model.ResponseTypePartial,
+		}, nil)
+		th.MockedStore.On("WithdrawSurveyResponse", mock.MatchedBy(func(withdrawal *model.SurveyResponseWithdrawal) bool {
+			return withdrawal.Counts == model.SurveyCountsDelta{Responses: -1, Passives: -1}
+		})).Return(false, nil)
+
+		withdrawn, err := th.App.WithdrawSurveyResponse("user_1", "survey_id_1")
+		require.NoError(t, err)
+		require.False(t, withdrawn)
+		th.MockedStore.AssertExpectations(t)
+		th.MockedPluginAPI.AssertNotCalled(t, "UpdatePost", mock.Anything)
+	})
+
+	t.Run("should do nothing if survey doesn't exist", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		th.MockedStore.On("GetSurveysByID", "survey_id_2").Return(nil, nil)
+
+		withdrawn, err := th.App.WithdrawSurveyResponse("user_1", "survey_id_2")
+		require.NoError(t, err)
+		require.False(t, withdrawn)
+	})
+}
+
+func TestSaveSurveyResponseIdempotency(t *testing.T) {
+	survey := &model.Survey{
+		ID: "survey_id_1",
+		SurveyQuestions: model.SurveyQuestions{
+			Questions: []model.Question{
+				{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale},
+				{ID: "question_id_2", Type: model.QuestionType},
+			},
+		},
+		ResponsesEditable: true,
+	}
+
+	t.Run("should not save a retried submission again", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		existingResponse := &model.SurveyResponse{
+			ID:             "response_id",
+			SurveyID:       "survey_id_1",
+			UserID:         "user_1",
+			Response:       map[string]string{"question_id_1": "10", "question_id_2": "Great"},
+			ResponseType:   model.ResponseTypeComplete,
+			IdempotencyKey: "key_1",
+		}
+
+		th.MockedStore.On("GetSurveysByStatus", "in_progress").Return([]*model.Survey{survey}, nil)
+		th.MockedStore.On("GetSurveyResponse", "user_1", "survey_id_1").Return(existingResponse, nil)
+		th.MockedPluginAPI.On("GetPost", "post_id_1").Return(&mmModal.Post{}, nil)
+		th.MockedPluginAPI.On("UpdatePost", mock.MatchedBy(func(post *mmModal.Post) bool {
+			return post.GetProp("survey_response") == `{"question_id_1":"10","question_id_2":"Great"}`
+		})).Return(&mmModal.Post{}, nil)
+		th.MockedStore.On("GetSurveyDelivery", "user_1", "survey_id_1").Return(&model.SurveyDelivery{PostID: "post_id_1"}, nil)
+
+		response := &model.SurveyResponse{
+			SurveyID:       "survey_id_1",
+			UserID:         "user_1",
+			Response:       map[string]string{"question_id_1": "10", "question_id_2": "Great"},
+			IdempotencyKey: "key_1",
+		}
+
+		err := th.App.SaveSurveyResponse(response)
+		require.NoError(t, err)
+		th.MockedStore.AssertNotCalled(t, "SaveSurveyResponse", mock.Anything)
+		th.MockedPluginAPI.AssertNotCalled(t, "CreatePost", mock.Anything)
+	})
+
+	t.Run("should report conflicting submissions", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		th.MockedStore.On("GetSurveysByStatus", "in_progress").Return([]*model.Survey{survey}, nil)
+		th.MockedStore.On("GetSurveyResponse", "user_1", "survey_id_1").Return(nil, nil)
+		th.MockedStore.On("SaveSurveyResponse", mock.Anything).Return(errors.Wrap(store.ErrSurveyResponseConflict, "failed to save"))
+		th.MockedPluginAPI.On("GetPost", "post_id_1").Return(&mmModal.Post{}, nil)
+		th.MockedStore.On("GetSurveyDelivery", "user_1", "survey_id_1").Return(&model.SurveyDelivery{PostID: "post_id_1"}, nil)
+		th.MockedPluginAPI.On("GetTeamsForUser", "user_1").Return([]*mmModal.Team{}, nil)
+
+		response := &model.SurveyResponse{
+			SurveyID:       "survey_id_1",
+			UserID:         "user_1",
+			Response:       map[string]string{"question_id_1": "10"},
+			IdempotencyKey: "key_2",
+		}
+
+		err := th.App.SaveSurveyResponse(response)
+		require.ErrorIs(t, err, ErrSubmissionConflict)
+		th.MockedPluginAPI.AssertNotCalled(t, "UpdatePost", mock.Anything)
+	})
 }
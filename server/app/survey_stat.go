@@ -3,8 +3,70 @@
 
 package app
 
-import "github.com/mattermost/mattermost-plugin-user-survey/server/model"
+import (
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
 
 func (a *UserSurveyApp) GetSurveyStatList() ([]*model.SurveyStat, error) {
-	return a.store.GetSurveyStatList()
+	surveyStats, err := a.store.GetSurveyStatList()
+	if err != nil {
+		return nil, errors.Wrap(err, "GetSurveyStatList: failed to get survey stats")
+	}
+
+	for _, surveyStat := range surveyStats {
+		surveyStat.WithholdUnreportableResults()
+	}
+
+	return surveyStats, nil
+}
+
+func (a *UserSurveyApp) GetSurveyStat(surveyID string) (*model.SurveyStat, error) {
+	surveyStat, err := a.store.GetSurveyStat(surveyID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "GetSurveyStat: failed to get survey stat, surveyID: %s", surveyID)
+	}
+
+	if surveyStat == nil {
+		return nil, nil
+	}
+
+	answerCounts, err := a.store.GetQuestionAnswerCounts(surveyID, surveyStat.SurveyQuestions.GetQuestionIDs())
+	if err != nil {
+		return nil, errors.Wrapf(err, "GetSurveyStat: failed to get question answer counts, surveyID: %s", surveyID)
+	}
+
+	surveyStat.SetQuestionAnswerCounts(answerCounts)
+
+	surveyStat.QuestionStats, err = a.getQuestionStats(&surveyStat.Survey, answerCounts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "GetSurveyStat: failed to get question stats, surveyID: %s", surveyID)
+	}
+
+	surveyStat.WithholdUnreportableResults()
+
+	return surveyStat, nil
+}
+
+// getQuestionStats aggregates the answers to each of the survey's questions. Questions other
+// than linear scale ones, such as text questions, are aggregated from the number of responses
+// that answered them, passed as answerCounts.
+func (a *UserSurveyApp) getQuestionStats(survey *model.Survey, answerCounts map[string]int64) ([]model.QuestionStat, error) {
+	questionStats := make([]model.QuestionStat, 0, len(survey.SurveyQuestions.Questions))
+	for _, question := range survey.SurveyQuestions.Questions {
+		if question.Type != model.QuestionTypeLinearScale {
+			questionStats = append(questionStats, model.NewTextQuestionStat(question, answerCounts[question.ID]))
+			continue
+		}
+
+		valueCounts, err := a.store.GetQuestionAnswerValueCounts(survey.ID, question.ID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getQuestionStats: failed to get question answer value counts, questionID: %s", question.ID)
+		}
+
+		questionStats = append(questionStats, model.NewLinearScaleQuestionStat(question, valueCounts))
+	}
+
+	return questionStats, nil
 }
@@ -0,0 +1,141 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+func TestGetSurveyStatFunnel(t *testing.T) {
+	th := SetupAppTest(t)
+
+	th.MockedStore.On("GetSurveyStat", "survey_id").Return(&model.SurveyStat{
+		Survey: model.Survey{
+			ID: "survey_id",
+			SurveyQuestions: model.SurveyQuestions{
+				Questions: []model.Question{
+					{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale},
+					{ID: "question_id_2", Type: model.QuestionType},
+				},
+			},
+		},
+		ReceiptCount:   10,
+		OpenedCount:    8,
+		ResponseCount:  6,
+		CompletedCount: 4,
+	}, nil)
+	th.MockedStore.On("GetQuestionAnswerCounts", "survey_id", []string{"question_id_1", "question_id_2"}).Return(map[string]int64{
+		"question_id_1": 6,
+		"question_id_2": 4,
+	}, nil)
+	th.MockedStore.On("GetQuestionAnswerValueCounts", "survey_id", "question_id_1").Return(map[string]int64{"9": 6}, nil)
+
+	surveyStat, err := th.App.GetSurveyStat("survey_id")
+	require.NoError(t, err)
+	require.Equal(t, model.SurveyFunnel{
+		Delivered: 10,
+		Opened:    8,
+		Rated:     6,
+		Completed: 4,
+		Questions: []model.QuestionFunnelStep{
+			{QuestionID: "question_id_1", Answered: 6, DropOff: 0},
+			{QuestionID: "question_id_2", Answered: 4, DropOff: 2},
+		},
+	}, surveyStat.GetFunnel())
+}
+
+func TestGetSurveyStatQuestionStats(t *testing.T) {
+	questions := model.SurveyQuestions{
+		Questions: []model.Question{
+			{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale},
+			{ID: "question_id_2", Type: model.QuestionTypeLinearScale, Scale: &model.RatingScale{Min: 1, Max: 3, DetractorMax: 1, PassiveMax: 2}},
+			{ID: "question_id_3", Type: model.QuestionType},
+		},
+	}
+
+	setupMocks := func(th *AppTestHelper, anonymity model.Anonymity) {
+		th.MockedStore.On("GetSurveyStat", "survey_id").Return(&model.SurveyStat{
+			Survey:        model.Survey{ID: "survey_id", SurveyQuestions: questions, Anonymity: anonymity},
+			ResponseCount: 4,
+		}, nil)
+		th.MockedStore.On("GetQuestionAnswerCounts", "survey_id", []string{"question_id_1", "question_id_2", "question_id_3"}).Return(map[string]int64{
+			"question_id_1": 4,
+			"question_id_2": 3,
+			"question_id_3": 2,
+		}, nil)
+		th.MockedStore.On("GetQuestionAnswerValueCounts", "survey_id", "question_id_1").Return(map[string]int64{"3": 1, "8": 1, "10": 2, "invalid": 1}, nil)
+		th.MockedStore.On("GetQuestionAnswerValueCounts", "survey_id", "question_id_2").Return(map[string]int64{"3": 2, "1": 1}, nil)
+	}
+
+	t.Run("should aggregate the answers of each question", func(t *testing.T) {
+		th := SetupAppTest(t)
+		setupMocks(th, model.Anonymity{})
+
+		surveyStat, err := th.App.GetSurveyStat("survey_id")
+		require.NoError(t, err)
+		require.Len(t, surveyStat.QuestionStats, 3)
+
+		ratingStat := surveyStat.QuestionStats[0]
+		require.Equal(t, []int64{0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 2}, ratingStat.RatingDistribution)
+		require.Equal(t, int64(5), ratingStat.ResponseCount)
+		require.InDelta(t, 7.75, *ratingStat.MeanRating, 0.001)
+		require.InDelta(t, 9, *ratingStat.MedianRating, 0.001)
+
+		scaleStat := surveyStat.QuestionStats[1]
+		require.Equal(t, "question_id_2", scaleStat.QuestionID)
+		require.Equal(t, int64(3), scaleStat.ResponseCount)
+		require.Equal(t, []int64{1, 0, 2}, scaleStat.RatingDistribution)
+		require.InDelta(t, 7.0/3, *scaleStat.MeanRating, 0.001)
+
+		require.Equal(t, model.QuestionStat{QuestionID: "question_id_3", Type: model.QuestionType, ResponseCount: 2}, surveyStat.QuestionStats[2])
+	})
+
+	t.Run("should aggregate ratings on the question's scale", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		csatQuestions := model.SurveyQuestions{
+			Questions: []model.Question{
+				{
+					ID:     "question_id_1",
+					System: true,
+					Type:   model.QuestionTypeLinearScale,
+					Scale:  &model.RatingScale{Min: 1, Max: 5, DetractorMax: 2, PassiveMax: 3, ScoreType: model.RatingScoreTypeTopGroup},
+				},
+			},
+		}
+
+		th.MockedStore.On("GetSurveyStat", "survey_id").Return(&model.SurveyStat{
+			Survey:         model.Survey{ID: "survey_id", SurveyQuestions: csatQuestions},
+			ResponseCount:  4,
+			PromoterCount:  3,
+			DetractorCount: 1,
+		}, nil)
+		th.MockedStore.On("GetQuestionAnswerCounts", "survey_id", []string{"question_id_1"}).Return(map[string]int64{"question_id_1": 4}, nil)
+		th.MockedStore.On("GetQuestionAnswerValueCounts", "survey_id", "question_id_1").Return(map[string]int64{"1": 1, "4": 1, "5": 2, "0": 1}, nil)
+
+		surveyStat, err := th.App.GetSurveyStat("survey_id")
+		require.NoError(t, err)
+		require.Equal(t, []int64{1, 0, 0, 1, 2}, surveyStat.QuestionStats[0].RatingDistribution)
+		require.InDelta(t, 3.75, *surveyStat.QuestionStats[0].MeanRating, 0.001)
+		require.InDelta(t, 4.5, *surveyStat.QuestionStats[0].MedianRating, 0.001)
+
+		// the CSAT score is the share of satisfied users
+		require.Equal(t, 75.0, surveyStat.GetScore())
+		require.Equal(t, model.RatingScoreTypeTopGroup, surveyStat.ToMetadata()["score_type"])
+	})
+
+	t.Run("should withhold question stats of anonymous surveys without enough responses", func(t *testing.T) {
+		th := SetupAppTest(t)
+		setupMocks(th, model.Anonymity{Enabled: true, MinGroupSize: 5})
+
+		surveyStat, err := th.App.GetSurveyStat("survey_id")
+		require.NoError(t, err)
+		require.True(t, surveyStat.ResultsWithheld)
+		require.Nil(t, surveyStat.QuestionStats)
+	})
+}
@@ -4,6 +4,9 @@
 package app
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -64,6 +67,23 @@ func TestSaveSurvey(t *testing.T) {
 		})
 		require.Error(t, err)
 	})
+
+	t.Run("should reject question IDs too long to be counted", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		newSurvey := func(question model.Question) *model.Survey {
+			return &model.Survey{
+				Duration:        100,
+				Status:          "in_progress",
+				StartTime:       time.Now().UnixMilli(),
+				SurveyQuestions: model.SurveyQuestions{Questions: []model.Question{question}},
+			}
+		}
+
+		err := th.App.SaveSurvey(newSurvey(model.Question{ID: strings.Repeat("q", model.MaxQuestionIDLength+1), System: true}))
+		require.ErrorContains(t, err, "question ID cannot be longer than")
+		th.MockedStore.AssertNotCalled(t, "SaveSurvey", mock.Anything)
+	})
 }
 
 func TestGetInProgressSurvey(t *testing.T) {
@@ -101,11 +121,50 @@ func TestGetInProgressSurvey(t *testing.T) {
 	})
 }
 
+func TestForEachSurvey(t *testing.T) {
+	t.Run("should page through all surveys", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		firstPage := make([]*model.Survey, allSurveysPerPage)
+		for i := range firstPage {
+			firstPage[i] = &model.Survey{ID: fmt.Sprintf("survey_id_%03d", i)}
+		}
+		lastID := firstPage[allSurveysPerPage-1].ID
+
+		th.MockedStore.On("GetAllSurveys", "", uint64(allSurveysPerPage)).Return(firstPage, nil)
+		th.MockedStore.On("GetAllSurveys", lastID, uint64(allSurveysPerPage)).Return([]*model.Survey{{ID: "survey_id_last"}}, nil)
+
+		var surveyIDs []string
+		err := th.App.forEachSurvey(func(survey *model.Survey) error {
+			surveyIDs = append(surveyIDs, survey.ID)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, surveyIDs, allSurveysPerPage+1)
+		require.Equal(t, "survey_id_last", surveyIDs[allSurveysPerPage])
+	})
+
+	t.Run("should stop at the first error", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		th.MockedStore.On("GetAllSurveys", "", uint64(allSurveysPerPage)).Return([]*model.Survey{{ID: "survey_id_1"}, {ID: "survey_id_2"}}, nil)
+
+		calls := 0
+		err := th.App.forEachSurvey(func(survey *model.Survey) error {
+			calls++
+			return errors.New("failed")
+		})
+		require.Error(t, err)
+		require.Equal(t, 1, calls)
+	})
+}
+
 func TestShouldSendSurvey(t *testing.T) {
 	t.Run("base case", func(t *testing.T) {
 		th := SetupAppTest(t)
 
-		th.MockedPluginAPI.On("KVGet", "user_survey_status_user_id_survey_id").Return(nil, nil)
+		th.MockedStore.On("GetSurveyDelivery", "user_id", "survey_id").Return(nil, nil)
+		th.MockedPluginAPI.On("KVGet", "user_survey_opt_out_user_id").Return(nil, nil)
 		th.MockedPluginAPI.On("KVGet", "user_team_filter_cache_user_id_survey_id").Return(nil, nil)
 		th.MockedPluginAPI.On("KVCompareAndSet", "user_lock_user_id", mock.Anything, mock.Anything).Return(true, nil)
 		th.MockedPluginAPI.On("KVDelete", "user_lock_user_id").Return(nil)
@@ -147,7 +206,8 @@ func TestShouldSendSurvey(t *testing.T) {
 	t.Run("should not send as user is in a excluded team", func(t *testing.T) {
 		th := SetupAppTest(t)
 
-		th.MockedPluginAPI.On("KVGet", "user_survey_status_user_id_survey_id").Return(nil, nil)
+		th.MockedStore.On("GetSurveyDelivery", "user_id", "survey_id").Return(nil, nil)
+		th.MockedPluginAPI.On("KVGet", "user_survey_opt_out_user_id").Return(nil, nil)
 		th.MockedPluginAPI.On("KVGet", "user_team_filter_cache_user_id_survey_id").Return(nil, nil)
 		th.MockedPluginAPI.On("KVCompareAndSet", "user_lock_user_id", mock.Anything, mock.Anything).Return(true, nil)
 		th.MockedPluginAPI.On("KVDelete", "user_lock_user_id").Return(nil)
@@ -172,7 +232,8 @@ func TestShouldSendSurvey(t *testing.T) {
 	t.Run("should send as user is include team", func(t *testing.T) {
 		th := SetupAppTest(t)
 
-		th.MockedPluginAPI.On("KVGet", "user_survey_status_user_id_survey_id").Return(nil, nil)
+		th.MockedStore.On("GetSurveyDelivery", "user_id", "survey_id").Return(nil, nil)
+		th.MockedPluginAPI.On("KVGet", "user_survey_opt_out_user_id").Return(nil, nil)
 		th.MockedPluginAPI.On("KVGet", "user_team_filter_cache_user_id_survey_id").Return(nil, nil)
 		th.MockedPluginAPI.On("KVCompareAndSet", "user_lock_user_id", mock.Anything, mock.Anything).Return(true, nil)
 		th.MockedPluginAPI.On("KVDelete", "user_lock_user_id").Return(nil)
@@ -196,7 +257,8 @@ func TestShouldSendSurvey(t *testing.T) {
 	t.Run("should send as no team filter is set", func(t *testing.T) {
 		th := SetupAppTest(t)
 
-		th.MockedPluginAPI.On("KVGet", "user_survey_status_user_id_survey_id").Return(nil, nil)
+		th.MockedStore.On("GetSurveyDelivery", "user_id", "survey_id").Return(nil, nil)
+		th.MockedPluginAPI.On("KVGet", "user_survey_opt_out_user_id").Return(nil, nil)
 		th.MockedPluginAPI.On("KVGet", "user_team_filter_cache_user_id_survey_id").Return(nil, nil)
 		th.MockedPluginAPI.On("KVCompareAndSet", "user_lock_user_id", mock.Anything, mock.Anything).Return(true, nil)
 		th.MockedPluginAPI.On("KVDelete", "user_lock_user_id").Return(nil)
@@ -216,7 +278,8 @@ func TestShouldSendSurvey(t *testing.T) {
 	t.Run("excluding selected teams but not mentioning any team should send to all", func(t *testing.T) {
 		th := SetupAppTest(t)
 
-		th.MockedPluginAPI.On("KVGet", "user_survey_status_user_id_survey_id").Return(nil, nil)
+		th.MockedStore.On("GetSurveyDelivery", "user_id", "survey_id").Return(nil, nil)
+		th.MockedPluginAPI.On("KVGet", "user_survey_opt_out_user_id").Return(nil, nil)
 		th.MockedPluginAPI.On("KVGet", "user_team_filter_cache_user_id_survey_id").Return(nil, nil)
 		th.MockedPluginAPI.On("KVCompareAndSet", "user_lock_user_id", mock.Anything, mock.Anything).Return(true, nil)
 		th.MockedPluginAPI.On("KVDelete", "user_lock_user_id").Return(nil)
@@ -241,7 +304,8 @@ func TestShouldSendSurvey(t *testing.T) {
 	t.Run("including selected teams but not mentioning any team should not send to anyone", func(t *testing.T) {
 		th := SetupAppTest(t)
 
-		th.MockedPluginAPI.On("KVGet", "user_survey_status_user_id_survey_id").Return(nil, nil)
+		th.MockedStore.On("GetSurveyDelivery", "user_id", "survey_id").Return(nil, nil)
+		th.MockedPluginAPI.On("KVGet", "user_survey_opt_out_user_id").Return(nil, nil)
 		th.MockedPluginAPI.On("KVGet", "user_team_filter_cache_user_id_survey_id").Return(nil, nil)
 		th.MockedPluginAPI.On("KVCompareAndSet", "user_lock_user_id", mock.Anything, mock.Anything).Return(true, nil)
 		th.MockedPluginAPI.On("KVDelete", "user_lock_user_id").Return(nil)
@@ -266,7 +330,8 @@ func TestShouldSendSurvey(t *testing.T) {
 	t.Run("should use cached value to check for team filter if a cached value is present", func(t *testing.T) {
 		th := SetupAppTest(t)
 
-		th.MockedPluginAPI.On("KVGet", "user_survey_status_user_id_survey_id").Return(nil, nil)
+		th.MockedStore.On("GetSurveyDelivery", "user_id", "survey_id").Return(nil, nil)
+		th.MockedPluginAPI.On("KVGet", "user_survey_opt_out_user_id").Return(nil, nil)
 		th.MockedPluginAPI.On("KVGet", "user_team_filter_cache_user_id_survey_id").Return([]byte("true"), nil)
 		th.MockedPluginAPI.On("KVCompareAndSet", "user_lock_user_id", mock.Anything, mock.Anything).Return(true, nil)
 		th.MockedPluginAPI.On("KVDelete", "user_lock_user_id").Return(nil)
@@ -285,7 +350,8 @@ func TestShouldSendSurvey(t *testing.T) {
 	t.Run("should send survey based on cache value", func(t *testing.T) {
 		th := SetupAppTest(t)
 
-		th.MockedPluginAPI.On("KVGet", "user_survey_status_user_id_survey_id").Return(nil, nil)
+		th.MockedStore.On("GetSurveyDelivery", "user_id", "survey_id").Return(nil, nil)
+		th.MockedPluginAPI.On("KVGet", "user_survey_opt_out_user_id").Return(nil, nil)
 		th.MockedPluginAPI.On("KVGet", "user_team_filter_cache_user_id_survey_id").Return([]byte("false"), nil)
 		th.MockedPluginAPI.On("KVCompareAndSet", "user_lock_user_id", mock.Anything, mock.Anything).Return(true, nil)
 		th.MockedPluginAPI.On("KVDelete", "user_lock_user_id").Return(nil)
@@ -300,4 +366,150 @@ func TestShouldSendSurvey(t *testing.T) {
 		require.NoError(t, err)
 		require.True(t, should)
 	})
+
+	t.Run("should not send survey if user has opted out of surveys", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		th.MockedStore.On("GetSurveyDelivery", "user_id", "survey_id").Return(nil, nil)
+		th.MockedPluginAPI.On("KVGet", "user_survey_opt_out_user_id").Return([]byte("true"), nil)
+
+		survey := &model.Survey{
+			ID:             "survey_id",
+			Status:         "in_progress",
+			FilterTeamIDs:  []string{},
+			TeamFilterType: model.TeamFilterSendToAll,
+		}
+		should, err := th.App.ShouldSendSurvey("user_id", survey)
+		require.NoError(t, err)
+		require.False(t, should)
+		th.MockedPluginAPI.AssertNotCalled(t, "GetTeamsForUser", "user_id")
+	})
+
+	t.Run("should not send survey if survey was already sent to the user", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		th.MockedStore.On("GetSurveyDelivery", "user_id", "survey_id").Return(&model.SurveyDelivery{PostID: "post_id"}, nil)
+
+		survey := &model.Survey{
+			ID:             "survey_id",
+			Status:         "in_progress",
+			FilterTeamIDs:  []string{},
+			TeamFilterType: model.TeamFilterSendToAll,
+		}
+		should, err := th.App.ShouldSendSurvey("user_id", survey)
+		require.NoError(t, err)
+		require.False(t, should)
+		th.MockedPluginAPI.AssertNotCalled(t, "KVGet", "user_survey_opt_out_user_id")
+	})
+}
+
+func TestSetUserSurveyOptOut(t *testing.T) {
+	t.Run("opting out should save the preference", func(t *testing.T) {
+		th := SetupAppTest(t)
+		th.MockedPluginAPI.On("KVSet", "user_survey_opt_out_user_id", []byte("true")).Return(nil)
+
+		err := th.App.SetUserSurveyOptOut("user_id", true)
+		require.NoError(t, err)
+		th.MockedPluginAPI.AssertExpectations(t)
+	})
+
+	t.Run("opting in should delete the preference", func(t *testing.T) {
+		th := SetupAppTest(t)
+		th.MockedPluginAPI.On("KVDelete", "user_survey_opt_out_user_id").Return(nil)
+
+		err := th.App.SetUserSurveyOptOut("user_id", false)
+		require.NoError(t, err)
+		th.MockedPluginAPI.AssertExpectations(t)
+	})
+}
+
+func TestSendSurvey(t *testing.T) {
+	survey := &model.Survey{
+		ID:     "survey_id",
+		Status: "in_progress",
+		SurveyQuestions: model.SurveyQuestions{
+			SurveyMessageText: "Hello",
+			DefaultLocale:     "en",
+			Questions: []model.Question{
+				{ID: "question_1", Text: "How likely?", Type: model.QuestionTypeLinearScale, System: true},
+				{ID: "question_2", Text: "Why?", Type: model.QuestionType},
+			},
+			Translations: map[string]model.SurveyTranslation{
+				"de": {
+					SurveyMessageText: "Hallo",
+					Questions: map[string]model.QuestionTranslation{
+						"question_1": {Text: "Wie wahrscheinlich?"},
+					},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name             string
+		userLocale       string
+		expectedLocale   string
+		expectedMessage  string
+		expectedQuestion string
+		expectedPost     string
+	}{
+		{name: "should send translated survey matching user language", userLocale: "de", expectedLocale: "de", expectedMessage: "Hallo", expectedQuestion: "Wie wahrscheinlich?", expectedPost: "Helfen Sie uns, Ihre Erfahrung zu verbessern. Verwenden Sie Mattermost im Webbrowser oder in der Desktop-App, um an der Umfrage teilzunehmen."},
+		{name: "should match translation by language of regional locale", userLocale: "de-AT", expectedLocale: "de", expectedMessage: "Hallo", expectedQuestion: "Wie wahrscheinlich?", expectedPost: "Helfen Sie uns, Ihre Erfahrung zu verbessern. Verwenden Sie Mattermost im Webbrowser oder in der Desktop-App, um an der Umfrage teilzunehmen."},
+		{name: "should fall back to default content", userLocale: "ja", expectedLocale: "en", expectedMessage: "Hello", expectedQuestion: "How likely?", expectedPost: "より良い体験のためにご協力ください。アンケートに回答するには、Webブラウザまたはデスクトップアプリで Mattermost をご利用ください。"},
+		{name: "should send post message in default locale for unsupported language", userLocale: "xx", expectedLocale: "en", expectedMessage: "Hello", expectedQuestion: "How likely?", expectedPost: "Help us improve your experience. Use Mattermost in a web browser or the desktop app to take the survey."},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			th := SetupAppTest(t)
+
+			th.MockedPluginAPI.On("GetUser", "user_id").Return(&mmModel.User{Id: "user_id", Locale: tc.userLocale}, nil)
+			th.MockedPluginAPI.On("GetDirectChannel", "user_id", "bot_user_id").Return(&mmModel.Channel{Id: "channel_id"}, nil)
+			th.MockedPluginAPI.On("CreatePost", mock.MatchedBy(func(post *mmModel.Post) bool {
+				var questions model.SurveyQuestions
+				if err := json.Unmarshal([]byte(post.GetProp("survey_questions").(string)), &questions); err != nil {
+					return false
+				}
+
+				return post.Message == tc.expectedPost &&
+					post.GetProp("survey_locale") == tc.expectedLocale &&
+					questions.SurveyMessageText == tc.expectedMessage &&
+					questions.Questions[0].Text == tc.expectedQuestion &&
+					questions.Questions[1].Text == "Why?" &&
+					questions.Translations == nil &&
+					post.GetProp("survey_responses_editable") == nil
+			})).Return(&mmModel.Post{Id: "post_id", CreateAt: 1000}, nil)
+			th.MockedStore.On("SaveSurveyDelivery", &model.SurveyDelivery{
+				UserID:      "user_id",
+				SurveyID:    "survey_id",
+				PostID:      "post_id",
+				DeliveredAt: 1000,
+				Status:      model.SurveyDeliveryStatusDelivered,
+			}).Return(nil)
+			th.MockedStore.On("IncrementSurveyReceiptCount", "survey_id").Return(nil)
+
+			err := th.App.SendSurvey("user_id", survey)
+			require.NoError(t, err)
+			th.MockedPluginAPI.AssertExpectations(t)
+			th.MockedStore.AssertExpectations(t)
+		})
+	}
+	t.Run("should mark post of survey with editable responses", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		editableSurvey := *survey
+		editableSurvey.ResponsesEditable = true
+
+		th.MockedPluginAPI.On("GetUser", "user_id").Return(&mmModel.User{Id: "user_id"}, nil)
+		th.MockedPluginAPI.On("GetDirectChannel", "user_id", "bot_user_id").Return(&mmModel.Channel{Id: "channel_id"}, nil)
+		th.MockedPluginAPI.On("CreatePost", mock.MatchedBy(func(post *mmModel.Post) bool {
+			return post.GetProp("survey_responses_editable") == true
+		})).Return(&mmModel.Post{Id: "post_id", CreateAt: 1000}, nil)
+		th.MockedStore.On("SaveSurveyDelivery", mock.Anything).Return(nil)
+		th.MockedStore.On("IncrementSurveyReceiptCount", "survey_id").Return(nil)
+
+		err := th.App.SendSurvey("user_id", &editableSurvey)
+		require.NoError(t, err)
+		th.MockedPluginAPI.AssertExpectations(t)
+	})
 }
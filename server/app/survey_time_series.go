@@ -0,0 +1,50 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+var ErrAnonymousSurveyHourlyTimeSeries = errors.New("anonymous surveys only have a daily time series")
+
+// GetSurveyTimeSeries returns the survey's deliveries, ratings and completions per day or hour,
+// for following the effect of reminders and announcements while the survey is running.
+// Anonymous surveys only have a daily time series, as hourly counts could tell when
+// a user responded.
+func (a *UserSurveyApp) GetSurveyTimeSeries(surveyID, interval string) (*model.SurveyTimeSeries, error) {
+	intervalMillis, err := model.GetTimeSeriesIntervalMillis(interval)
+	if err != nil {
+		return nil, errors.Wrapf(err, "GetSurveyTimeSeries: invalid interval, surveyID: %s", surveyID)
+	}
+
+	survey, err := a.store.GetSurveysByID(surveyID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "GetSurveyTimeSeries: failed to get survey by ID, surveyID: %s", surveyID)
+	}
+
+	if survey == nil {
+		return nil, nil
+	}
+
+	if survey.Anonymity.Enabled && interval != model.TimeSeriesIntervalDay {
+		return nil, ErrAnonymousSurveyHourlyTimeSeries
+	}
+
+	counts, err := a.store.GetSurveyActivityCounts(surveyID, intervalMillis)
+	if err != nil {
+		return nil, errors.Wrapf(err, "GetSurveyTimeSeries: failed to get survey activity counts, surveyID: %s", surveyID)
+	}
+
+	timeSeries, err := model.NewSurveyTimeSeries(survey, interval, *counts, time.Now())
+	if err != nil {
+		return nil, errors.Wrapf(err, "GetSurveyTimeSeries: failed to build time series, surveyID: %s", surveyID)
+	}
+
+	return timeSeries, nil
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+func TestGetSurveyTimeSeries(t *testing.T) {
+	day := (24 * time.Hour).Milliseconds()
+	startTime := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC).UnixMilli()
+	firstDay := startTime - startTime%day
+
+	t.Run("should have a point per day of the survey including the days without activity", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		survey := &model.Survey{ID: "survey_id_1", StartTime: startTime, Duration: 3, Status: model.SurveyStatusEnded}
+
+		th.MockedStore.On("GetSurveysByID", "survey_id_1").Return(survey, nil)
+		th.MockedStore.On("GetSurveyActivityCounts", "survey_id_1", day).Return(&model.SurveyActivityCounts{
+			Deliveries:  map[int64]int64{firstDay: 100},
+			Ratings:     map[int64]int64{firstDay: 20, firstDay + 2*day: 10},
+			Completions: map[int64]int64{firstDay: 15, firstDay + 2*day: 8},
+		}, nil)
+
+		timeSeries, err := th.App.GetSurveyTimeSeries("survey_id_1", model.TimeSeriesIntervalDay)
+		require.NoError(t, err)
+		require.Equal(t, model.TimeSeriesIntervalDay, timeSeries.Interval)
+		require.Equal(t, []model.SurveyTimeSeriesPoint{
+			{Time: firstDay, Deliveries: 100, Ratings: 20, Completions: 15},
+			{Time: firstDay + day},
+			{Time: firstDay + 2*day, Ratings: 10, Completions: 8},
+			{Time: firstDay + 3*day},
+		}, timeSeries.Points)
+	})
+
+	t.Run("should end a running survey's series at the current interval", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		hour := time.Hour.Milliseconds()
+		surveyStartTime := time.Now().Add(-90 * time.Minute).UnixMilli()
+		survey := &model.Survey{ID: "survey_id_1", StartTime: surveyStartTime, Duration: 7, Status: model.SurveyStatusInProgress}
+
+		th.MockedStore.On("GetSurveysByID", "survey_id_1").Return(survey, nil)
+		th.MockedStore.On("GetSurveyActivityCounts", "survey_id_1", hour).Return(&model.SurveyActivityCounts{}, nil)
+
+		timeSeries, err := th.App.GetSurveyTimeSeries("survey_id_1", model.TimeSeriesIntervalHour)
+		require.NoError(t, err)
+		require.Equal(t, surveyStartTime-surveyStartTime%hour, timeSeries.Points[0].Time)
+
+		lastPoint := timeSeries.Points[len(timeSeries.Points)-1]
+		require.LessOrEqual(t, lastPoint.Time, time.Now().UnixMilli())
+		require.Greater(t, lastPoint.Time, time.Now().UnixMilli()-hour)
+	})
+
+	t.Run("should carry small daily counts of anonymous surveys over to the following days", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		survey := &model.Survey{
+			ID:        "survey_id_1",
+			StartTime: startTime,
+			Duration:  4,
+			Status:    model.SurveyStatusEnded,
+			Anonymity: model.Anonymity{Enabled: true, MinGroupSize: 5},
+		}
+
+		th.MockedStore.On("GetSurveysByID", "survey_id_1").Return(survey, nil)
+		th.MockedStore.On("GetSurveyActivityCounts", "survey_id_1", day).Return(&model.SurveyActivityCounts{
+			Deliveries:  map[int64]int64{firstDay: 100},
+			Ratings:     map[int64]int64{firstDay: 20, firstDay + day: 2, firstDay + 2*day: 4, firstDay + 4*day: 1},
+			Completions: map[int64]int64{firstDay: 3, firstDay + day: 1, firstDay + 2*day: 7},
+		}, nil)
+
+		timeSeries, err := th.App.GetSurveyTimeSeries("survey_id_1", model.TimeSeriesIntervalDay)
+		require.NoError(t, err)
+		require.Equal(t, []model.SurveyTimeSeriesPoint{
+			{Time: firstDay, Deliveries: 100, Ratings: 20},
+			{Time: firstDay + day},
+			{Time: firstDay + 2*day, Ratings: 6, Completions: 11},
+			{Time: firstDay + 3*day},
+			{Time: firstDay + 4*day},
+		}, timeSeries.Points)
+	})
+
+	t.Run("should reject hourly time series of anonymous surveys", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		survey := &model.Survey{ID: "survey_id_1", StartTime: startTime, Duration: 3, Anonymity: model.Anonymity{Enabled: true}}
+		th.MockedStore.On("GetSurveysByID", "survey_id_1").Return(survey, nil)
+
+		_, err := th.App.GetSurveyTimeSeries("survey_id_1", model.TimeSeriesIntervalHour)
+		require.ErrorIs(t, err, ErrAnonymousSurveyHourlyTimeSeries)
+		th.MockedStore.AssertNotCalled(t, "GetSurveyActivityCounts", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject unknown intervals", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		_, err := th.App.GetSurveyTimeSeries("survey_id_1", "week")
+		require.Error(t, err)
+		th.MockedStore.AssertNotCalled(t, "GetSurveysByID")
+	})
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+// GetSurveyTeamNPS computes the survey's NPS by the teams the respondents were members of
+// when answering. The minimum number of respondents for reporting a team is the survey's
+// minimum group size, whether the survey is anonymous or not. Anonymous surveys have no
+// team breakdown as the respondents' teams aren't captured for them.
+// The breakdown comes from the segment counts, which are rolled up as responses are saved.
+func (a *UserSurveyApp) GetSurveyTeamNPS(surveyID string) (*model.SurveyTeamNPS, error) {
+	survey, err := a.store.GetSurveysByID(surveyID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "GetSurveyTeamNPS: failed to get survey by ID, surveyID: %s", surveyID)
+	}
+
+	if survey == nil {
+		return nil, nil
+	}
+
+	minRespondents := survey.Anonymity.GetMinGroupSize()
+	if survey.Anonymity.Enabled {
+		return model.NewSurveyTeamNPS(surveyID, minRespondents, model.SurveySegmentCounts{}), nil
+	}
+
+	if _, err := survey.GetSystemRatingQuestion(); err != nil {
+		a.api.LogError("GetSurveyTeamNPS: failed to get system rating question", "surveyID", surveyID, "error", err.Error())
+		return nil, errors.Wrapf(err, "GetSurveyTeamNPS: failed to get system rating question, surveyID: %s", surveyID)
+	}
+
+	segmentCounts, err := a.store.GetSurveySegmentCounts(surveyID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "GetSurveyTeamNPS: failed to get survey segment counts, surveyID: %s", surveyID)
+	}
+
+	return model.NewSurveyTeamNPS(surveyID, minRespondents, segmentCounts), nil
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+func TestGetSurveyTeamNPS(t *testing.T) {
+	questions := model.SurveyQuestions{
+		Questions: []model.Question{
+			{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale},
+		},
+	}
+
+	t.Run("should compute NPS by team and withhold small teams", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		survey := &model.Survey{
+			ID:              "survey_id_1",
+			SurveyQuestions: questions,
+			Anonymity:       model.Anonymity{MinGroupSize: 2},
+		}
+
+		responses := []*model.SurveyResponse{
+			newTestResponse("response_id_1", "10", testTeamA, testTeamB),
+			newTestResponse("response_id_2", "9", testTeamA),
+			newTestResponse("response_id_3", "3", testTeamA),
+			newTestResponse("response_id_4", "7", testTeamA),
+			newTestResponse("response_id_5", "", testTeamB),
+			newTestResponse("response_id_6", "8"),
+		}
+
+		th.MockedStore.On("GetSurveysByID", "survey_id_1").Return(survey, nil)
+		th.MockedStore.On("GetSurveySegmentCounts", "survey_id_1").Return(newTestSegmentCounts(survey, responses...), nil)
+
+		surveyTeamNPS, err := th.App.GetSurveyTeamNPS("survey_id_1")
+		require.NoError(t, err)
+		require.Equal(t, 2, surveyTeamNPS.MinRespondents)
+		require.Equal(t, 1, surveyTeamNPS.WithheldTeams)
+		require.Len(t, surveyTeamNPS.Teams, 1)
+
+		teamNPS := surveyTeamNPS.Teams[0]
+		require.Equal(t, "team_a", teamNPS.TeamID)
+		require.Equal(t, "Team A", teamNPS.TeamName)
+		require.Equal(t, int64(4), teamNPS.Respondents)
+		require.Equal(t, int64(2), teamNPS.Promoters)
+		require.Equal(t, int64(1), teamNPS.Passives)
+		require.Equal(t, int64(1), teamNPS.Detractors)
+		require.Equal(t, 25.0, teamNPS.NPS)
+
+		// four respondents leave a wide margin of error, bounded to the NPS range
+		require.InDelta(t, 81.26, teamNPS.NPSConfidence.MarginOfError, 0.01)
+		require.InDelta(t, -56.26, teamNPS.NPSConfidence.Lower, 0.01)
+		require.Equal(t, 100.0, teamNPS.NPSConfidence.Upper)
+	})
+
+	t.Run("should have no team breakdown for anonymous surveys", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		survey := &model.Survey{
+			ID:              "survey_id_1",
+			SurveyQuestions: questions,
+			Anonymity:       model.Anonymity{Enabled: true},
+		}
+
+		th.MockedStore.On("GetSurveysByID", "survey_id_1").Return(survey, nil)
+
+		surveyTeamNPS, err := th.App.GetSurveyTeamNPS("survey_id_1")
+		require.NoError(t, err)
+		require.Equal(t, model.DefaultAnonymousMinGroupSize, surveyTeamNPS.MinRespondents)
+		require.Empty(t, surveyTeamNPS.Teams)
+		th.MockedStore.AssertNotCalled(t, "GetSurveySegmentCounts")
+	})
+
+	t.Run("should return nil for a missing survey", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		th.MockedStore.On("GetSurveysByID", "survey_id_1").Return(nil, nil)
+
+		surveyTeamNPS, err := th.App.GetSurveyTeamNPS("survey_id_1")
+		require.NoError(t, err)
+		require.Nil(t, surveyTeamNPS)
+	})
+}
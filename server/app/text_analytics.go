@@ -0,0 +1,85 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+const textAnalyticsPerPage = 500
+
+// JobAnalyzeSurveyText is a scheduled job that extracts the keywords and themes
+// of the text answers of the surveys that ended since it last ran.
+func (a *UserSurveyApp) JobAnalyzeSurveyText() error {
+	a.api.LogDebug("JobAnalyzeSurveyText: running")
+
+	surveys, err := a.store.GetEndedSurveysWithoutTextAnalytics()
+	if err != nil {
+		a.api.LogError("JobAnalyzeSurveyText: failed to get surveys to analyze", "error", err.Error())
+		return err
+	}
+
+	for _, survey := range surveys {
+		if _, err := a.AnalyzeSurveyText(survey); err != nil {
+			a.api.LogError("JobAnalyzeSurveyText: failed to analyze survey text answers", "surveyID", survey.ID, "error", err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AnalyzeSurveyText extracts the keywords and phrases of the survey's text answers,
+// overall and by rating group, and saves them for the API and the survey report.
+// The answers are analyzed on the server, so they're never sent to an external service.
+func (a *UserSurveyApp) AnalyzeSurveyText(survey *model.Survey) (*model.SurveyTextAnalytics, error) {
+	analyzer := model.NewTextAnalyzer(survey)
+	lastResponseID := ""
+
+	for {
+		responses, err := a.store.GetAllResponses(survey.ID, lastResponseID, textAnalyticsPerPage)
+		if err != nil {
+			return nil, errors.Wrapf(err, "AnalyzeSurveyText: failed to get survey responses, surveyID: %s", survey.ID)
+		}
+
+		if len(responses) == 0 {
+			break
+		}
+
+		if err := a.decryptResponses(responses); err != nil {
+			return nil, errors.Wrapf(err, "AnalyzeSurveyText: failed to decrypt survey responses, surveyID: %s", survey.ID)
+		}
+
+		for _, response := range responses {
+			analyzer.AddResponse(response)
+		}
+
+		if len(responses) < textAnalyticsPerPage {
+			break
+		}
+
+		lastResponseID = responses[len(responses)-1].ID
+	}
+
+	analytics := analyzer.ToSurveyTextAnalytics(mmModel.GetMillis())
+	if err := a.store.SaveSurveyTextAnalytics(analytics); err != nil {
+		return nil, errors.Wrapf(err, "AnalyzeSurveyText: failed to save text analytics, surveyID: %s", survey.ID)
+	}
+
+	return analytics, nil
+}
+
+// GetSurveyTextAnalytics returns the keywords and phrases of the survey's text answers,
+// or nil if the survey hasn't ended and been analyzed yet.
+func (a *UserSurveyApp) GetSurveyTextAnalytics(surveyID string) (*model.SurveyTextAnalytics, error) {
+	analytics, err := a.store.GetSurveyTextAnalytics(surveyID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "GetSurveyTextAnalytics: failed to get text analytics, surveyID: %s", surveyID)
+	}
+
+	return analytics, nil
+}
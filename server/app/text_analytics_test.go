@@ -0,0 +1,178 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+func TestExtractTextTerms(t *testing.T) {
+	t.Run("should leave out stop words, numbers and punctuation", func(t *testing.T) {
+		keywords, phrases := model.ExtractTextTerms("The search is SLOW. I'd rate it 3, and the mobile app's sign-in keeps failing!")
+		require.Equal(t, []string{"search", "slow", "rate", "mobile", "app's", "sign-in", "keeps", "failing"}, keywords)
+		require.Equal(t, []string{"mobile app's", "app's sign-in", "sign-in keeps", "keeps failing", "mobile app's sign-in", "app's sign-in keeps", "sign-in keeps failing"}, phrases)
+	})
+
+	t.Run("should leave out redaction placeholders", func(t *testing.T) {
+		keywords, phrases := model.ExtractTextTerms("Ask [redacted email] about search results")
+		require.Equal(t, []string{"ask", "search", "results"}, keywords)
+		require.Equal(t, []string{"search results"}, phrases)
+	})
+}
+
+func TestAnalyzeSurveyText(t *testing.T) {
+	questions := model.SurveyQuestions{
+		Questions: []model.Question{
+			{ID: "question_id_1", System: true, Type: model.QuestionTypeLinearScale},
+			{ID: "question_id_2", Text: "What could be better?", Type: model.QuestionType},
+		},
+	}
+
+	newResponse := func(id, rating, answer string) *model.SurveyResponse {
+		return &model.SurveyResponse{
+			ID:       id,
+			Response: map[string]string{"question_id_1": rating, "question_id_2": answer},
+		}
+	}
+
+	responses := []*model.SurveyResponse{
+		newResponse("response_id_1", "2", "Search results are slow, search is slow"),
+		newResponse("response_id_2", "3", "Slow search results"),
+		newResponse("response_id_3", "10", "Love the new mobile app"),
+		newResponse("response_id_4", "9", "The mobile app is great"),
+		newResponse("response_id_5", "7", ""),
+		newResponse("response_id_6", "", "Nothing about search"),
+	}
+
+	t.Run("should extract keywords and phrases overall and by rating group", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		survey := &model.Survey{ID: "survey_id_1", SurveyQuestions: questions}
+
+		th.MockedStore.On("GetAllResponses", "survey_id_1", "", uint64(textAnalyticsPerPage)).Return(responses, nil)
+		th.MockedStore.On("SaveSurveyTextAnalytics", mock.Anything).Return(nil)
+
+		analytics, err := th.App.AnalyzeSurveyText(survey)
+		require.NoError(t, err)
+		require.Equal(t, "survey_id_1", analytics.SurveyID)
+		require.NotZero(t, analytics.CreateAt)
+		require.Len(t, analytics.Questions, 1)
+		th.MockedStore.AssertCalled(t, "SaveSurveyTextAnalytics", analytics)
+
+		questionAnalytics := analytics.Questions[0]
+		require.Equal(t, "question_id_2", questionAnalytics.QuestionID)
+		require.Equal(t, "What could be better?", questionAnalytics.QuestionText)
+		require.Equal(t, int64(5), questionAnalytics.AnswerCount)
+
+		// terms from a single answer, such as "love", are left out
+		require.Equal(t, []model.TermCount{
+			{Term: "search", Answers: 3, Occurrences: 4},
+			{Term: "slow", Answers: 2, Occurrences: 3},
+			{Term: "app", Answers: 2, Occurrences: 2},
+			{Term: "mobile", Answers: 2, Occurrences: 2},
+			{Term: "results", Answers: 2, Occurrences: 2},
+		}, questionAnalytics.Keywords)
+		require.Equal(t, []model.TermCount{
+			{Term: "mobile app", Answers: 2, Occurrences: 2},
+			{Term: "search results", Answers: 2, Occurrences: 2},
+		}, questionAnalytics.Phrases)
+
+		require.Len(t, questionAnalytics.RatingGroups, 3)
+
+		promoters := questionAnalytics.RatingGroups[0]
+		require.Equal(t, model.RatingGroupPromoters, promoters.Group)
+		require.Equal(t, int64(2), promoters.AnswerCount)
+		require.Equal(t, []model.TermCount{
+			{Term: "app", Answers: 2, Occurrences: 2},
+			{Term: "mobile", Answers: 2, Occurrences: 2},
+		}, promoters.Keywords)
+
+		passives := questionAnalytics.RatingGroups[1]
+		require.Equal(t, model.RatingGroupPassives, passives.Group)
+		require.Zero(t, passives.AnswerCount)
+		require.Empty(t, passives.Keywords)
+
+		detractors := questionAnalytics.RatingGroups[2]
+		require.Equal(t, model.RatingGroupDetractors, detractors.Group)
+		require.Equal(t, int64(2), detractors.AnswerCount)
+		require.Equal(t, "search", detractors.Keywords[0].Term)
+	})
+
+	t.Run("should skip answers that fail to decrypt", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		survey := &model.Survey{ID: "survey_id_1", SurveyQuestions: questions}
+
+		undecryptable := append([]*model.SurveyResponse{
+			newResponse("response_id_0", "1", "enc:v1:removed_key:c2xvdyBzZWFyY2g="),
+		}, responses...)
+
+		th.MockedStore.On("GetAllResponses", "survey_id_1", "", uint64(textAnalyticsPerPage)).Return(undecryptable, nil)
+		th.MockedStore.On("SaveSurveyTextAnalytics", mock.Anything).Return(nil)
+
+		analytics, err := th.App.AnalyzeSurveyText(survey)
+		require.NoError(t, err)
+		require.Len(t, analytics.Questions, 1)
+		require.Equal(t, int64(5), analytics.Questions[0].AnswerCount)
+	})
+
+	t.Run("should withhold the results of too few answers in anonymous surveys", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		survey := &model.Survey{
+			ID:              "survey_id_1",
+			SurveyQuestions: questions,
+			Anonymity:       model.Anonymity{Enabled: true, MinGroupSize: 3},
+		}
+
+		th.MockedStore.On("GetAllResponses", "survey_id_1", "", uint64(textAnalyticsPerPage)).Return(responses, nil)
+		th.MockedStore.On("SaveSurveyTextAnalytics", mock.Anything).Return(nil)
+
+		analytics, err := th.App.AnalyzeSurveyText(survey)
+		require.NoError(t, err)
+
+		questionAnalytics := analytics.Questions[0]
+		require.False(t, questionAnalytics.Withheld)
+		require.NotEmpty(t, questionAnalytics.Keywords)
+
+		for _, group := range questionAnalytics.RatingGroups {
+			require.True(t, group.Withheld)
+			require.Empty(t, group.Keywords)
+		}
+
+		survey.Anonymity.MinGroupSize = 10
+
+		analytics, err = th.App.AnalyzeSurveyText(survey)
+		require.NoError(t, err)
+		require.True(t, analytics.Questions[0].Withheld)
+		require.Empty(t, analytics.Questions[0].Keywords)
+		require.Empty(t, analytics.Questions[0].Phrases)
+		require.Empty(t, analytics.Questions[0].RatingGroups)
+	})
+}
+
+func TestJobAnalyzeSurveyText(t *testing.T) {
+	t.Run("should analyze the ended surveys without text analytics", func(t *testing.T) {
+		th := SetupAppTest(t)
+
+		surveys := []*model.Survey{{ID: "survey_id_1"}, {ID: "survey_id_2"}}
+
+		th.MockedStore.On("GetEndedSurveysWithoutTextAnalytics").Return(surveys, nil)
+		th.MockedStore.On("GetAllResponses", mock.Anything, "", uint64(textAnalyticsPerPage)).Return([]*model.SurveyResponse{}, nil)
+		th.MockedStore.On("SaveSurveyTextAnalytics", mock.Anything).Return(nil)
+
+		require.NoError(t, th.App.JobAnalyzeSurveyText())
+		th.MockedStore.AssertCalled(t, "SaveSurveyTextAnalytics", mock.MatchedBy(func(analytics *model.SurveyTextAnalytics) bool {
+			return analytics.SurveyID == "survey_id_1"
+		}))
+		th.MockedStore.AssertCalled(t, "SaveSurveyTextAnalytics", mock.MatchedBy(func(analytics *model.SurveyTextAnalytics) bool {
+			return analytics.SurveyID == "survey_id_2"
+		}))
+	})
+}
@@ -0,0 +1,261 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/i18n"
+	surveyModel "github.com/mattermost/mattermost-plugin-user-survey/server/model"
+	"github.com/mattermost/mattermost-plugin-user-survey/server/utils"
+)
+
+const (
+	surveyCommand = "survey"
+
+	surveySubCommandTake     = "take"
+	surveySubCommandStatus   = "status"
+	surveySubCommandOptOut   = "opt-out"
+	surveySubCommandOptIn    = "opt-in"
+	surveySubCommandWithdraw = "withdraw"
+
+	surveyCommandHelpTranslationID = "command.survey.help"
+	commandErrorTranslationID      = "command.error"
+)
+
+func (p *Plugin) executeSurveyCommand(_ *plugin.Context, args *model.CommandArgs, params []string) (*model.CommandResponse, *model.AppError) {
+	user, appErr := p.API.GetUser(args.UserId)
+	if appErr != nil {
+		p.API.LogError("executeSurveyCommand: failed to get user by id", "userID", args.UserId, "error", appErr.Error())
+		return &model.CommandResponse{Text: p.app.GetUserTranslations("")(commandErrorTranslationID)}, nil
+	}
+
+	T := p.app.GetUserTranslations(user.Locale)
+
+	if len(params) == 0 {
+		return &model.CommandResponse{Text: T(surveyCommandHelpTranslationID)}, nil
+	}
+
+	if user.IsGuest() {
+		return &model.CommandResponse{Text: T("command.survey.guest_user")}, nil
+	}
+
+	var message string
+	var err error
+
+	switch params[0] {
+	case surveySubCommandTake:
+		message, err = p.executeSurveyTakeCommand(T, args.UserId)
+	case surveySubCommandStatus:
+		message, err = p.executeSurveyStatusCommand(T, args.UserId)
+	case surveySubCommandOptOut:
+		message, err = p.executeSurveyOptOutCommand(T, args.UserId, true)
+	case surveySubCommandOptIn:
+		message, err = p.executeSurveyOptOutCommand(T, args.UserId, false)
+	case surveySubCommandWithdraw:
+		message, err = p.executeSurveyWithdrawCommand(T, args.UserId, params[1:])
+	default:
+		message = T(surveyCommandHelpTranslationID)
+	}
+
+	if err != nil {
+		p.API.LogError("executeSurveyCommand: failed to execute command", "command", args.Command, "userID", args.UserId, "error", err.Error())
+		return &model.CommandResponse{Text: T(commandErrorTranslationID)}, nil
+	}
+
+	return &model.CommandResponse{Text: message}, nil
+}
+
+func (p *Plugin) executeSurveyTakeCommand(T i18n.TranslateFunc, userID string) (string, error) {
+	survey, err := p.app.GetInProgressSurvey()
+	if err != nil {
+		return "", errors.Wrap(err, "executeSurveyTakeCommand: failed to get in progress survey")
+	}
+
+	if survey == nil {
+		return T("command.survey.no_running_survey"), nil
+	}
+
+	// acquire the same lock used when sending surveys on user connect
+	// to prevent sending a duplicate survey to the user.
+	key := utils.KeyUserSendSurveyLock(userID)
+	utcNow := time.Now().UTC()
+	locked, err := p.app.AcquireUserSurveyLock(key, utcNow)
+	if err != nil {
+		return "", errors.Wrap(err, "executeSurveyTakeCommand: failed to acquire user survey lock")
+	}
+
+	if !locked {
+		return T("command.survey.take.already_sending"), nil
+	}
+
+	defer func() {
+		_, _ = p.app.ReleaseUserSurveyLock(key, utcNow)
+	}()
+
+	postID, err := p.app.GetSurveyPostIDSentToUser(userID, survey.ID)
+	if err != nil {
+		return "", errors.Wrap(err, "executeSurveyTakeCommand: failed to get survey post sent to user")
+	}
+
+	if postID == "" {
+		var eligible bool
+		eligible, err = p.app.IsUserEligibleForSurvey(userID, survey)
+		if err != nil {
+			return "", errors.Wrap(err, "executeSurveyTakeCommand: failed to check if user is eligible for survey")
+		}
+
+		if !eligible {
+			return T("command.survey.take.not_eligible"), nil
+		}
+
+		if err := p.app.SendSurvey(userID, survey); err != nil {
+			return "", errors.Wrap(err, "executeSurveyTakeCommand: failed to send survey")
+		}
+
+		return T("command.survey.take.sent"), nil
+	}
+
+	response, err := p.app.GetSurveyResponse(userID, survey)
+	if err != nil {
+		return "", errors.Wrap(err, "executeSurveyTakeCommand: failed to get user survey response")
+	}
+
+	if response != nil && response.ResponseType == surveyModel.ResponseTypeComplete && !survey.ResponsesEditable {
+		return T("command.survey.take.already_responded"), nil
+	}
+
+	// the user may have deleted the original survey post,
+	// in which case we post the survey again.
+	if _, appErr := p.API.GetPost(postID); appErr != nil {
+		postID, err = p.app.ResendSurvey(userID, survey)
+		if err != nil {
+			return "", errors.Wrap(err, "executeSurveyTakeCommand: failed to resend survey")
+		}
+	}
+
+	return T("command.survey.take.link", map[string]interface{}{"Permalink": p.getPermalink(postID)}), nil
+}
+
+func (p *Plugin) executeSurveyStatusCommand(T i18n.TranslateFunc, userID string) (string, error) {
+	optedOut, err := p.app.HasUserOptedOutOfSurveys(userID)
+	if err != nil {
+		return "", errors.Wrap(err, "executeSurveyStatusCommand: failed to check if user has opted out of surveys")
+	}
+
+	var optOutStatus string
+	if optedOut {
+		optOutStatus = "\n" + T("command.survey.status.opted_out")
+	}
+
+	survey, err := p.app.GetInProgressSurvey()
+	if err != nil {
+		return "", errors.Wrap(err, "executeSurveyStatusCommand: failed to get in progress survey")
+	}
+
+	if survey == nil {
+		return T("command.survey.no_running_survey") + optOutStatus, nil
+	}
+
+	postID, err := p.app.GetSurveyPostIDSentToUser(userID, survey.ID)
+	if err != nil {
+		return "", errors.Wrap(err, "executeSurveyStatusCommand: failed to get survey post sent to user")
+	}
+
+	if postID == "" {
+		return T("command.survey.status.not_received") + optOutStatus, nil
+	}
+
+	response, err := p.app.GetSurveyResponse(userID, survey)
+	if err != nil {
+		return "", errors.Wrap(err, "executeSurveyStatusCommand: failed to get user survey response")
+	}
+
+	switch {
+	case response == nil:
+		return T("command.survey.status.not_responded") + optOutStatus, nil
+	case response.ResponseType == surveyModel.ResponseTypePartial:
+		return T("command.survey.status.partial") + optOutStatus, nil
+	default:
+		message := T("command.survey.status.responded", map[string]interface{}{"Date": utils.FormatUnixTimeMillis(response.CreateAt)})
+		if survey.ResponsesEditable {
+			message += " " + T("command.survey.status.editable")
+		}
+
+		return message + optOutStatus, nil
+	}
+}
+
+func (p *Plugin) executeSurveyOptOutCommand(T i18n.TranslateFunc, userID string, optOut bool) (string, error) {
+	if err := p.app.SetUserSurveyOptOut(userID, optOut); err != nil {
+		return "", errors.Wrap(err, "executeSurveyOptOutCommand: failed to update user survey opt out status")
+	}
+
+	if optOut {
+		return T("command.survey.opt_out.success"), nil
+	}
+
+	return T("command.survey.opt_in.success"), nil
+}
+
+func (p *Plugin) executeSurveyWithdrawCommand(T i18n.TranslateFunc, userID string, params []string) (string, error) {
+	var surveyID string
+	if len(params) > 0 {
+		surveyID = params[0]
+	} else {
+		survey, err := p.app.GetInProgressSurvey()
+		if err != nil {
+			return "", errors.Wrap(err, "executeSurveyWithdrawCommand: failed to get in progress survey")
+		}
+
+		if survey == nil {
+			return T("command.survey.withdraw.no_running_survey"), nil
+		}
+
+		surveyID = survey.ID
+	}
+
+	// acquire the same lock used when saving responses to prevent
+	// withdrawing the response while a submission of it is being saved.
+	key := utils.KeyUserSubmitResponseLock(userID)
+	utcNow := time.Now().UTC()
+	locked, err := p.app.AcquireUserSurveyLock(key, utcNow)
+	if err != nil {
+		return "", errors.Wrap(err, "executeSurveyWithdrawCommand: failed to acquire user survey lock")
+	}
+
+	if !locked {
+		return T("command.survey.withdraw.submission_in_progress"), nil
+	}
+
+	defer func() {
+		_, _ = p.app.ReleaseUserSurveyLock(key, utcNow)
+	}()
+
+	withdrawn, err := p.app.WithdrawSurveyResponse(userID, surveyID)
+	if err != nil {
+		return "", errors.Wrap(err, "executeSurveyWithdrawCommand: failed to withdraw survey response")
+	}
+
+	if !withdrawn {
+		return T("command.survey.withdraw.no_response"), nil
+	}
+
+	return T("command.survey.withdraw.success"), nil
+}
+
+func (p *Plugin) getPermalink(postID string) string {
+	siteURL := ""
+	if config := p.API.GetConfig(); config != nil && config.ServiceSettings.SiteURL != nil {
+		siteURL = strings.TrimSuffix(*config.ServiceSettings.SiteURL, "/")
+	}
+
+	return fmt.Sprintf("%s/_redirect/pl/%s", siteURL, postID)
+}
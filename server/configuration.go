@@ -4,8 +4,10 @@
 package main
 
 import (
+	"encoding/json"
 	"reflect"
 
+	mmModel "github.com/mattermost/mattermost/server/public/model"
 	"github.com/pkg/errors"
 
 	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
@@ -86,7 +88,57 @@ func (p *Plugin) OnConfigurationChange() error {
 		return errors.Wrap(err, "failed to load plugin configuration")
 	}
 
+	if cfg.SystemConsoleSetting != nil {
+		if err := cfg.SystemConsoleSetting.SurveyQuestions.IsValid(); err != nil {
+			return errors.Wrap(err, "invalid survey questions in plugin configuration")
+		}
+
+		if err := cfg.SystemConsoleSetting.Redaction.IsValid(); err != nil {
+			return errors.Wrap(err, "invalid redaction settings in plugin configuration")
+		}
+
+		if err := cfg.SystemConsoleSetting.Encryption.IsValid(); err != nil {
+			return errors.Wrap(err, "invalid encryption settings in plugin configuration")
+		}
+	}
+
 	p.setConfiguration(cfg.SystemConsoleSetting)
 
 	return nil
 }
+
+// ConfigurationWillBeSaved rejects plugin configuration changes removing an encryption key
+// that stored answers are still encrypted with. Keys can be removed once the re-encryption
+// job has re-encrypted the answers with the active key. It needs Mattermost 8.0 or later,
+// older servers save the configuration without the check.
+func (p *Plugin) ConfigurationWillBeSaved(newCfg *mmModel.Config) (*mmModel.Config, error) {
+	if p.app == nil {
+		return nil, nil
+	}
+
+	pluginSettings, ok := newCfg.PluginSettings.Plugins[model.PluginID]
+	if !ok {
+		return nil, nil
+	}
+
+	systemConsoleSetting, ok := pluginSettings["systemconsolesetting"]
+	if !ok {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(systemConsoleSetting)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal plugin configuration")
+	}
+
+	var newPluginCfg model.Config
+	if err := json.Unmarshal(data, &newPluginCfg); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal plugin configuration")
+	}
+
+	if err := p.app.CheckEncryptionKeysRemovable(newPluginCfg.Encryption); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin"
 	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/i18n"
 )
 
 const (
@@ -28,6 +30,41 @@ func (p *Plugin) registerDebugCommands() error {
 
 	return nil
 }
+
+// registerCommands registers the survey commands, which are available in all builds,
+// unlike the debug commands registered above. The autocomplete texts are registered
+// once for all users, so they're translated to the server's default locale.
+func (p *Plugin) registerCommands() error {
+	T := p.app.GetUserTranslations(p.getDefaultServerLocale())
+
+	autocompleteData := model.NewAutocompleteData(surveyCommand, "[command]", T("command.survey.autocomplete.description"))
+	autocompleteData.AddCommand(model.NewAutocompleteData(surveySubCommandTake, "", T("command.survey.autocomplete.take")))
+	autocompleteData.AddCommand(model.NewAutocompleteData(surveySubCommandStatus, "", T("command.survey.autocomplete.status")))
+	autocompleteData.AddCommand(model.NewAutocompleteData(surveySubCommandOptOut, "", T("command.survey.autocomplete.opt_out")))
+	autocompleteData.AddCommand(model.NewAutocompleteData(surveySubCommandOptIn, "", T("command.survey.autocomplete.opt_in")))
+
+	withdrawData := model.NewAutocompleteData(surveySubCommandWithdraw, "[survey ID]", T("command.survey.autocomplete.withdraw"))
+	withdrawData.AddTextArgument(T("command.survey.autocomplete.withdraw.survey_id"), "[survey ID]", "")
+	autocompleteData.AddCommand(withdrawData)
+
+	err := p.API.RegisterCommand(&model.Command{
+		Trigger:          surveyCommand,
+		DisplayName:      "User Survey",
+		Description:      T("command.survey.autocomplete.description"),
+		AutoComplete:     true,
+		AutoCompleteDesc: T("command.survey.autocomplete.available"),
+		AutoCompleteHint: "[command]",
+		AutocompleteData: autocompleteData,
+	})
+
+	if err != nil {
+		p.API.LogError("registerCommands: failed to register survey command", "error", err.Error())
+		return errors.Wrap(err, "registerCommands: failed to register survey command")
+	}
+
+	return p.registerAdminCommands()
+}
+
 func (p *Plugin) ExecuteCommand(ctx *plugin.Context, args *model.CommandArgs) (*model.CommandResponse, *model.AppError) {
 	split := strings.Fields(args.Command)
 	if len(split) == 0 {
@@ -36,13 +73,28 @@ func (p *Plugin) ExecuteCommand(ctx *plugin.Context, args *model.CommandArgs) (*
 
 	command := split[0]
 
-	if command == "/"+resetDataCommand {
+	switch command {
+	case "/" + surveyCommand:
+		return p.executeSurveyCommand(ctx, args, split[1:])
+	case "/" + surveyAdminCommand:
+		return p.executeSurveyAdminCommand(ctx, args, split[1:])
+	case "/" + resetDataCommand:
 		return p.executeResetDataCommand(ctx, args)
 	}
 
 	return nil, nil
 }
 
+// getDefaultServerLocale returns the locale of the server, for the texts that aren't shown to a particular user.
+func (p *Plugin) getDefaultServerLocale() string {
+	config := p.API.GetConfig()
+	if config == nil || config.LocalizationSettings.DefaultServerLocale == nil {
+		return i18n.DefaultLocale
+	}
+
+	return *config.LocalizationSettings.DefaultServerLocale
+}
+
 func (p *Plugin) executeResetDataCommand(_ *plugin.Context, args *model.CommandArgs) (*model.CommandResponse, *model.AppError) {
 	user, appErr := p.API.GetUser(args.UserId)
 	if appErr != nil {
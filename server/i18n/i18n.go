@@ -0,0 +1,66 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package i18n
+
+import (
+	"embed"
+	"strings"
+
+	"github.com/mattermost/go-i18n/i18n/bundle"
+	"github.com/pkg/errors"
+)
+
+const DefaultLocale = "en"
+
+//go:embed *.json
+var translationFiles embed.FS
+
+// TranslateFunc returns the translated message for the translation ID,
+// rendering the optional template arguments into it.
+type TranslateFunc func(translationID string, args ...interface{}) string
+
+// Bundle holds the server side translations embedded in the plugin.
+type Bundle struct {
+	bundle *bundle.Bundle
+}
+
+func NewBundle() (*Bundle, error) {
+	b := bundle.New()
+
+	entries, err := translationFiles.ReadDir(".")
+	if err != nil {
+		return nil, errors.Wrap(err, "NewBundle: failed to read translation files")
+	}
+
+	for _, entry := range entries {
+		data, err := translationFiles.ReadFile(entry.Name())
+		if err != nil {
+			return nil, errors.Wrap(err, "NewBundle: failed to read translation file, file: "+entry.Name())
+		}
+
+		if err := b.ParseTranslationFileBytes(entry.Name(), data); err != nil {
+			return nil, errors.Wrap(err, "NewBundle: failed to parse translation file, file: "+entry.Name())
+		}
+	}
+
+	return &Bundle{bundle: b}, nil
+}
+
+// GetUserTranslations returns the translate function for the user's locale.
+// Regional locales without their own translations use the translations of their language,
+// and messages missing from the user's language fall back to the default locale.
+func (b *Bundle) GetUserTranslations(locale string) TranslateFunc {
+	// errors here only mean no supported language was found,
+	// in which case the translate functions return the translation ID.
+	defaultTfunc, _ := b.bundle.Tfunc(DefaultLocale)
+	tfunc, _ := b.bundle.Tfunc(locale, strings.SplitN(locale, "-", 2)[0], DefaultLocale)
+
+	return func(translationID string, args ...interface{}) string {
+		if translated := tfunc(translationID, args...); translated != translationID {
+			return translated
+		}
+
+		return defaultTfunc(translationID, args...)
+	}
+}
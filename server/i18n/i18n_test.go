@@ -0,0 +1,50 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUserTranslations(t *testing.T) {
+	bundle, err := NewBundle()
+	require.NoError(t, err)
+
+	const translationID = "app.survey_response.acknowledgement_message"
+
+	testCases := []struct {
+		name     string
+		locale   string
+		expected string
+	}{
+		{name: "should translate to user locale", locale: "de", expected: ":tada: Vielen Dank für Ihr Feedback!"},
+		{name: "should use language of regional locale", locale: "fr-CA", expected: ":tada: Merci d'avoir partagé votre avis !"},
+		{name: "should fall back to default locale for unsupported locale", locale: "xx", expected: ":tada: Thank you for sharing your feedback!"},
+		{name: "should fall back to default locale for empty locale", locale: "", expected: ":tada: Thank you for sharing your feedback!"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, bundle.GetUserTranslations(tc.locale)(translationID))
+		})
+	}
+
+	t.Run("should render template arguments", func(t *testing.T) {
+		message := bundle.GetUserTranslations("de")("app.report.post_message", map[string]interface{}{"SurveyID": "survey_1"})
+		require.Equal(t, "Hier ist der Bericht für die Umfrage `survey_1`.", message)
+	})
+
+	t.Run("should return translation ID for unknown message", func(t *testing.T) {
+		require.Equal(t, "unknown.id", bundle.GetUserTranslations("de")("unknown.id"))
+	})
+
+	t.Run("should have all default locale messages translated", func(t *testing.T) {
+		defaultIDs := bundle.bundle.LanguageTranslationIDs(DefaultLocale)
+		for _, tag := range bundle.bundle.LanguageTags() {
+			require.ElementsMatch(t, defaultIDs, bundle.bundle.LanguageTranslationIDs(tag), "locale: %s", tag)
+		}
+	})
+}
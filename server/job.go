@@ -11,11 +11,25 @@ import (
 )
 
 const (
-	jobKeyStartSurveyJob = "job_start_survey"
+	jobKeyStartSurveyJob     = "job_start_survey"
+	jobKeyReconcileCountsJob = "job_reconcile_survey_counts"
+	jobKeyReencryptJob       = "job_reencrypt_survey_responses"
+	jobKeyTextAnalyticsJob   = "job_analyze_survey_text"
+
+	mutexKeyBackfill = "mutex_backfill"
 
 	debugStartSurveyJobInterval = 15 * time.Second
 	startSurveyJobInterval      = 15 * time.Minute
 
+	debugReconcileCountsJobInterval = time.Minute
+	reconcileCountsJobInterval      = 24 * time.Hour
+
+	debugReencryptJobInterval = time.Minute
+	reencryptJobInterval      = time.Hour
+
+	debugTextAnalyticsJobInterval = time.Minute
+	textAnalyticsJobInterval      = time.Hour
+
 	LockExpiration = time.Hour
 )
 
@@ -41,3 +55,101 @@ func (p *Plugin) startManageSurveyJob() error {
 	p.jobs = append(p.jobs, job)
 	return nil
 }
+
+func (p *Plugin) startReconcileCountsJob() error {
+	interval := reconcileCountsJobInterval
+	if DebugBuild == "true" {
+		interval = debugReconcileCountsJobInterval
+	}
+
+	job, err := cluster.Schedule(
+		p.API,
+		jobKeyReconcileCountsJob,
+		cluster.MakeWaitForInterval(interval),
+		func() {
+			_ = p.app.JobReconcileSurveyCounts()
+		},
+	)
+
+	if err != nil {
+		return errors.Wrap(err, "failed to schedule survey counts reconciliation job")
+	}
+
+	p.jobs = append(p.jobs, job)
+	return nil
+}
+
+func (p *Plugin) startReencryptJob() error {
+	interval := reencryptJobInterval
+	if DebugBuild == "true" {
+		interval = debugReencryptJobInterval
+	}
+
+	job, err := cluster.Schedule(
+		p.API,
+		jobKeyReencryptJob,
+		cluster.MakeWaitForInterval(interval),
+		func() {
+			_ = p.app.JobReencryptSurveyResponses()
+		},
+	)
+
+	if err != nil {
+		return errors.Wrap(err, "failed to schedule survey response re-encryption job")
+	}
+
+	p.jobs = append(p.jobs, job)
+	return nil
+}
+
+func (p *Plugin) startTextAnalyticsJob() error {
+	interval := textAnalyticsJobInterval
+	if DebugBuild == "true" {
+		interval = debugTextAnalyticsJobInterval
+	}
+
+	job, err := cluster.Schedule(
+		p.API,
+		jobKeyTextAnalyticsJob,
+		cluster.MakeWaitForInterval(interval),
+		func() {
+			_ = p.app.JobAnalyzeSurveyText()
+		},
+	)
+
+	if err != nil {
+		return errors.Wrap(err, "failed to schedule survey text analytics job")
+	}
+
+	p.jobs = append(p.jobs, job)
+	return nil
+}
+
+// startBackfillJob runs the one-off backfills in the background, so they don't hold up
+// activating the plugin. They run under a cluster mutex so only one node of a cluster
+// runs them, and each records when it's done, so later runs have nothing to do.
+func (p *Plugin) startBackfillJob() error {
+	mutex, err := cluster.NewMutex(p.API, mutexKeyBackfill)
+	if err != nil {
+		return errors.Wrap(err, "failed to create backfill mutex")
+	}
+
+	go func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if err := p.app.BackfillSurveyDeliveries(); err != nil {
+			p.API.LogError("startBackfillJob: failed to backfill survey deliveries", "error", err.Error())
+		}
+
+		if err := p.app.BackfillQuestionAnswerCounts(); err != nil {
+			p.API.LogError("startBackfillJob: failed to backfill question answer counts", "error", err.Error())
+		}
+
+		if err := p.app.BackfillSegmentCounts(); err != nil {
+			p.API.LogError("startBackfillJob: failed to backfill segment counts", "error", err.Error())
+		}
+	}()
+
+	return nil
+}
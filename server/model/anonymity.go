@@ -0,0 +1,46 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultAnonymousMinGroupSize = 5
+)
+
+// Anonymity configures anonymous surveys. Responses to an anonymous survey are stored
+// under a one-way pseudonym instead of the user ID, and results are only reported
+// for groups of at least MinGroupSize responses.
+type Anonymity struct {
+	Enabled bool `json:"enabled"`
+
+	// MinGroupSize is the smallest number of responses a group needs before
+	// its results are reported. Zero uses DefaultAnonymousMinGroupSize.
+	MinGroupSize int `json:"minGroupSize"`
+}
+
+func (a Anonymity) GetMinGroupSize() int {
+	if a.MinGroupSize <= 0 {
+		return DefaultAnonymousMinGroupSize
+	}
+
+	return a.MinGroupSize
+}
+
+// IsGroupReportable tells whether the results of a group with the given number
+// of responses can be reported without risking identifying the respondents.
+// Groups of any size are reportable for surveys that aren't anonymous.
+func (a Anonymity) IsGroupReportable(responseCount int64) bool {
+	return !a.Enabled || responseCount >= int64(a.GetMinGroupSize())
+}
+
+func (a Anonymity) IsValid() error {
+	if a.MinGroupSize < 0 {
+		return errors.New("minimum group size cannot be negative")
+	}
+
+	return nil
+}
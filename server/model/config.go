@@ -5,17 +5,31 @@ package model
 
 import (
 	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	mmModel "github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
 )
 
+// PluginID is the ID of the plugin, which owns the bots it creates.
+const PluginID = "com.mattermost.user-survey"
+
 type Config struct {
 	EnableSurvey    bool            `json:"EnableSurvey"`
 	SurveyDateTime  SurveyDateTime  `json:"SurveyDateTime"`
 	SurveyExpiry    SurveyExpiry    `json:"SurveyExpiry"`
 	SurveyQuestions SurveyQuestions `json:"SurveyQuestions"`
 	TeamFilter      TeamFilter      `json:"TeamFilter"`
+	Customization   Customization   `json:"Customization"`
+	Anonymity       Anonymity       `json:"Anonymity"`
+	Redaction       Redaction       `json:"Redaction"`
+	Encryption      Encryption      `json:"Encryption"`
+
+	ResponsesEditable bool `json:"ResponsesEditable"`
 }
 
 type SurveyDateTime struct {
@@ -29,22 +43,129 @@ type SurveyExpiry struct {
 type SurveyQuestions struct {
 	Questions         []Question `json:"questions"`
 	SurveyMessageText string     `json:"surveyMessageText"`
+
+	// DefaultLocale is the locale of the default survey content,
+	// used for users whose locale has no translation.
+	DefaultLocale string `json:"defaultLocale,omitempty"`
+
+	// Translations holds the localized survey content keyed by locale, such as "de" or "pt-BR".
+	Translations map[string]SurveyTranslation `json:"translations,omitempty"`
+}
+
+type SurveyTranslation struct {
+	SurveyMessageText string `json:"surveyMessageText"`
+
+	// Questions holds the translated questions keyed by question ID.
+	Questions map[string]QuestionTranslation `json:"questions"`
+}
+
+type QuestionTranslation struct {
+	Text string `json:"text"`
+}
+
+// MatchLocale returns the translation locale that best matches the user's locale,
+// first by the exact locale, then by its language. An empty string is returned
+// if there is no matching translation.
+func (sq *SurveyQuestions) MatchLocale(userLocale string) string {
+	if userLocale == "" || len(sq.Translations) == 0 {
+		return ""
+	}
+
+	if _, ok := sq.Translations[userLocale]; ok {
+		return userLocale
+	}
+
+	language := strings.SplitN(strings.ReplaceAll(userLocale, "_", "-"), "-", 2)[0]
+	if _, ok := sq.Translations[language]; ok {
+		return language
+	}
+
+	return ""
+}
+
+// Localize returns a copy of the survey questions translated for the user's locale,
+// along with the locale of the returned content. Fields without a translation
+// keep their default text. The returned copy doesn't contain the translations.
+func (sq *SurveyQuestions) Localize(userLocale string) (SurveyQuestions, string) {
+	localized := SurveyQuestions{
+		Questions:         slices.Clone(sq.Questions),
+		SurveyMessageText: sq.SurveyMessageText,
+		DefaultLocale:     sq.DefaultLocale,
+	}
+
+	locale := sq.MatchLocale(userLocale)
+	if locale == "" {
+		return localized, sq.DefaultLocale
+	}
+
+	translation := sq.Translations[locale]
+	if translation.SurveyMessageText != "" {
+		localized.SurveyMessageText = translation.SurveyMessageText
+	}
+
+	for i, question := range localized.Questions {
+		questionTranslation, ok := translation.Questions[question.ID]
+		if !ok {
+			continue
+		}
+
+		if questionTranslation.Text != "" {
+			localized.Questions[i].Text = questionTranslation.Text
+		}
+	}
+
+	return localized, locale
 }
 
 func (sq *SurveyQuestions) GetMetadata() []interface{} {
 	metadata := []interface{}{}
 
 	for _, question := range sq.Questions {
-		metadata = append(metadata, map[string]string{
+		questionMetadata := map[string]interface{}{
 			"id":   question.ID,
 			"text": question.Text,
 			"type": question.Type,
-		})
+		}
+
+		if question.Type == QuestionTypeLinearScale {
+			questionMetadata["scale"] = question.GetRatingScale()
+		}
+
+		metadata = append(metadata, questionMetadata)
 	}
 
 	return metadata
 }
 
+// IsValid checks the rating scales of the linear scale questions, and that
+// the question IDs fit the column of the question answer counts.
+func (sq *SurveyQuestions) IsValid() error {
+	for _, question := range sq.Questions {
+		if utf8.RuneCountInString(question.ID) > MaxQuestionIDLength {
+			return fmt.Errorf("question ID cannot be longer than %d characters, questionID: %s", MaxQuestionIDLength, question.ID)
+		}
+
+		if question.Type != QuestionTypeLinearScale || question.Scale == nil {
+			continue
+		}
+
+		if err := question.Scale.IsValid(); err != nil {
+			return errors.Wrap(err, "invalid rating scale for question "+question.ID)
+		}
+	}
+
+	return nil
+}
+
+func (sq *SurveyQuestions) GetQuestionIDs() []string {
+	questionIDs := make([]string, 0, len(sq.Questions))
+	for _, question := range sq.Questions {
+		questionIDs = append(questionIDs, question.ID)
+	}
+
+	return questionIDs
+}
+
 type TeamFilter struct {
 	FilteredTeamIDs []string `json:"filteredTeamIDs"`
 	FilterType      string   `json:"filterType"`
@@ -0,0 +1,85 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/base64"
+	"text/template"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+)
+
+// Customization lets a survey override the bot the survey is sent from
+// and the messages the bot posts. Empty values fall back to the defaults.
+type Customization struct {
+	Bot SurveyBot `json:"bot"`
+
+	// PostMessage is the message text of the survey post. It is displayed
+	// on clients that cannot render the survey, such as the mobile app.
+	PostMessage string `json:"postMessage"`
+
+	// AcknowledgementMessage is posted once the user submits the survey.
+	AcknowledgementMessage string `json:"acknowledgementMessage"`
+}
+
+type SurveyBot struct {
+	Username    string `json:"username"`
+	DisplayName string `json:"displayName"`
+
+	// ProfileImage is the base64 encoded profile image of the bot.
+	ProfileImage string `json:"profileImage"`
+}
+
+// MessageTemplateData is the data available to the custom
+// survey messages, for example, {{.FirstName}}.
+type MessageTemplateData struct {
+	Username  string
+	FirstName string
+	LastName  string
+	Nickname  string
+}
+
+func NewMessageTemplateData(user *mmModel.User) MessageTemplateData {
+	return MessageTemplateData{
+		Username:  user.Username,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Nickname:  user.Nickname,
+	}
+}
+
+func (c Customization) HasCustomBot() bool {
+	return c.Bot.Username != ""
+}
+
+func (c Customization) IsValid() error {
+	if c.HasCustomBot() && !mmModel.IsValidUsername(c.Bot.Username) {
+		return errors.New("invalid bot username: " + c.Bot.Username)
+	}
+
+	if !c.HasCustomBot() && (c.Bot.DisplayName != "" || c.Bot.ProfileImage != "") {
+		return errors.New("bot username is required when customizing the bot")
+	}
+
+	if c.Bot.ProfileImage != "" {
+		if _, err := c.Bot.ProfileImageBytes(); err != nil {
+			return errors.Wrap(err, "invalid bot profile image")
+		}
+	}
+
+	if _, err := template.New("postMessage").Parse(c.PostMessage); err != nil {
+		return errors.Wrap(err, "invalid post message template")
+	}
+
+	if _, err := template.New("acknowledgementMessage").Parse(c.AcknowledgementMessage); err != nil {
+		return errors.Wrap(err, "invalid acknowledgement message template")
+	}
+
+	return nil
+}
+
+func (b SurveyBot) ProfileImageBytes() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(b.ProfileImage)
+}
@@ -0,0 +1,276 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	stdErrors "errors"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	encryptedAnswerPrefix = "enc:v1:"
+
+	// reservedAnswerPrefix starts every stored answer that isn't plain text. Plaintext answers
+	// starting with it are stored with escapedAnswerPrefix added, so they can't pass for
+	// encrypted answers.
+	reservedAnswerPrefix = "enc:"
+	escapedAnswerPrefix  = "enc:plain:"
+
+	EncryptionKeySize = 32
+)
+
+// Encryption configures encrypting the text answers of survey responses at rest.
+// Keys are rotated by adding a new key and making it active. Answers encrypted with
+// the previous keys are re-encrypted with the active key by a scheduled job,
+// after which the previous keys can be removed.
+type Encryption struct {
+	Enabled bool `json:"enabled"`
+
+	// ActiveKeyID is the ID of the key new answers are encrypted with.
+	ActiveKeyID string `json:"activeKeyId"`
+
+	// Keys holds the active key and the previous keys still needed for decrypting answers.
+	Keys []EncryptionKey `json:"keys,omitempty"`
+}
+
+type EncryptionKey struct {
+	ID string `json:"id"`
+
+	// Key is the base64 encoded 256-bit AES key.
+	Key string `json:"key"`
+}
+
+// AnswerCipher encrypts and decrypts the text answers of survey responses.
+// Encrypted answers are stored as "enc:v1:<key ID>:<base64 nonce and ciphertext>",
+// so the key an answer was encrypted with is known when decrypting it. Plaintext answers
+// starting with "enc:" are stored as "enc:plain:<answer>", so only encrypted answers
+// are stored starting with "enc:v1:".
+type AnswerCipher struct {
+	enabled     bool
+	activeKeyID string
+	keys        map[string]cipher.AEAD
+}
+
+func (e Encryption) IsValid() error {
+	keyIDs := map[string]bool{}
+	for _, key := range e.Keys {
+		if key.ID == "" || strings.Contains(key.ID, ":") {
+			return errors.New("encryption key ID cannot be empty or contain colons")
+		}
+
+		if keyIDs[key.ID] {
+			return errors.New("duplicate encryption key ID: " + key.ID)
+		}
+		keyIDs[key.ID] = true
+
+		decodedKey, err := base64.StdEncoding.DecodeString(key.Key)
+		if err != nil {
+			return errors.Wrap(err, "encryption key is not base64 encoded, key ID: "+key.ID)
+		}
+
+		if len(decodedKey) != EncryptionKeySize {
+			return errors.Errorf("encryption key must be %d bytes long, key ID: %s", EncryptionKeySize, key.ID)
+		}
+	}
+
+	if e.Enabled && !keyIDs[e.ActiveKeyID] {
+		return errors.New("active encryption key not found: " + e.ActiveKeyID)
+	}
+
+	return nil
+}
+
+// NewAnswerCipher creates a cipher with the configured keys. The configured keys are
+// used for decrypting even if encryption is disabled, so answers encrypted before
+// encryption was disabled can still be read.
+func (e Encryption) NewAnswerCipher() (*AnswerCipher, error) {
+	if err := e.IsValid(); err != nil {
+		return nil, err
+	}
+
+	answerCipher := &AnswerCipher{
+		enabled:     e.Enabled,
+		activeKeyID: e.ActiveKeyID,
+		keys:        map[string]cipher.AEAD{},
+	}
+
+	for _, key := range e.Keys {
+		decodedKey, _ := base64.StdEncoding.DecodeString(key.Key)
+
+		block, err := aes.NewCipher(decodedKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create AES cipher, key ID: "+key.ID)
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create GCM cipher, key ID: "+key.ID)
+		}
+
+		answerCipher.keys[key.ID] = aead
+	}
+
+	return answerCipher, nil
+}
+
+func (c *AnswerCipher) IsEnabled() bool {
+	return c.enabled
+}
+
+func (c *AnswerCipher) encrypt(answer string) (string, error) {
+	aead := c.keys[c.activeKeyID]
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", errors.Wrap(err, "failed to generate nonce")
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(answer), nil)
+	return EncryptedAnswerKeyPrefix(c.activeKeyID) + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func escapeAnswer(answer string) string {
+	if strings.HasPrefix(answer, reservedAnswerPrefix) {
+		return escapedAnswerPrefix + answer
+	}
+
+	return answer
+}
+
+func (c *AnswerCipher) decrypt(answer string) (string, error) {
+	if plaintext, ok := strings.CutPrefix(answer, escapedAnswerPrefix); ok {
+		return plaintext, nil
+	}
+
+	encrypted, ok := strings.CutPrefix(answer, encryptedAnswerPrefix)
+	if !ok {
+		// the answer was saved before encryption was enabled
+		return answer, nil
+	}
+
+	keyID, encoded, ok := strings.Cut(encrypted, ":")
+	if !ok {
+		return "", errors.New("malformed encrypted answer")
+	}
+
+	aead, ok := c.keys[keyID]
+	if !ok {
+		return "", errors.New("encryption key not configured, key ID: " + keyID)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decode encrypted answer")
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("malformed encrypted answer")
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decrypt answer, key ID: "+keyID)
+	}
+
+	return string(plaintext), nil
+}
+
+// isCurrent tells whether the answer is stored the way it would be encrypted now,
+// that is encrypted with the active key, or in plaintext if encryption is disabled.
+func (c *AnswerCipher) isCurrent(answer string) bool {
+	if answer == "" || !c.enabled {
+		return true
+	}
+
+	return strings.HasPrefix(answer, EncryptedAnswerKeyPrefix(c.activeKeyID))
+}
+
+// EncryptedAnswerKeyPrefix is the prefix of the answers encrypted with the key.
+func EncryptedAnswerKeyPrefix(keyID string) string {
+	return encryptedAnswerPrefix + keyID + ":"
+}
+
+// EncryptAnswers returns a copy of the answers for storing, with the answers to the text questions
+// encrypted. If encryption is disabled, the text answers are stored in plaintext, escaped if needed.
+// The answers must be in plaintext, as answers that look encrypted are encrypted again.
+func (c *AnswerCipher) EncryptAnswers(answers map[string]string, questions SurveyQuestions) (map[string]string, error) {
+	encrypted := make(map[string]string, len(answers))
+	for questionID, answer := range answers {
+		encrypted[questionID] = answer
+	}
+
+	for _, question := range questions.Questions {
+		answer := encrypted[question.ID]
+		if question.Type != QuestionType || answer == "" {
+			continue
+		}
+
+		if !c.enabled {
+			encrypted[question.ID] = escapeAnswer(answer)
+			continue
+		}
+
+		encryptedAnswer, err := c.encrypt(answer)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to encrypt answer, questionID: "+question.ID)
+		}
+
+		encrypted[question.ID] = encryptedAnswer
+	}
+
+	return encrypted, nil
+}
+
+// DecryptAnswers returns a copy of the answers with all encrypted answers decrypted.
+// Answers that can't be decrypted are left out of the copy, which is returned along
+// with an error describing them.
+func (c *AnswerCipher) DecryptAnswers(answers map[string]string) (map[string]string, error) {
+	decrypted := make(map[string]string, len(answers))
+	var decryptErrs []error
+	for questionID, answer := range answers {
+		decryptedAnswer, err := c.decrypt(answer)
+		if err != nil {
+			decryptErrs = append(decryptErrs, errors.Wrap(err, "failed to decrypt answer, questionID: "+questionID))
+			continue
+		}
+
+		decrypted[questionID] = decryptedAnswer
+	}
+
+	return decrypted, stdErrors.Join(decryptErrs...)
+}
+
+// ReencryptAnswers brings the answers up to date with the current encryption settings,
+// encrypting plaintext text answers and re-encrypting the answers encrypted with a
+// previous key with the active key. It returns nil if the answers are already up to date.
+func (c *AnswerCipher) ReencryptAnswers(answers map[string]string, questions SurveyQuestions) (map[string]string, error) {
+	if !c.enabled {
+		return nil, nil
+	}
+
+	upToDate := true
+	for _, question := range questions.Questions {
+		if question.Type == QuestionType && !c.isCurrent(answers[question.ID]) {
+			upToDate = false
+			break
+		}
+	}
+
+	if upToDate {
+		return nil, nil
+	}
+
+	decrypted, err := c.DecryptAnswers(answers)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.EncryptAnswers(decrypted, questions)
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"github.com/mattermost/mattermost-plugin-user-survey/server/utils"
+)
+
+// NPSSignificanceLevel is the p-value below which a difference in NPS is considered significant.
+const NPSSignificanceLevel = 0.05
+
+// NPSGroupCounts are the number of responses in each rating group, from which the NPS is computed.
+type NPSGroupCounts struct {
+	Promoters  int64
+	Passives   int64
+	Detractors int64
+}
+
+// NPSConfidenceInterval is the range the NPS lies in with 95% confidence, given the number of responses.
+type NPSConfidenceInterval struct {
+	MarginOfError float64 `json:"marginOfError"`
+	Lower         float64 `json:"lower"`
+	Upper         float64 `json:"upper"`
+}
+
+// NPSComparison is the difference between two NPS, or two scores of another score type, along with whether
+// it's statistically significant or likely due to sampling noise.
+type NPSComparison struct {
+	Difference  float64 `json:"difference"`
+	PValue      float64 `json:"pValue"`
+	Significant bool    `json:"significant"`
+}
+
+func (c NPSGroupCounts) Total() int64 {
+	return c.Promoters + c.Passives + c.Detractors
+}
+
+func (c NPSGroupCounts) NPS() float64 {
+	return utils.CalculateNPS(c.Promoters, c.Detractors, c.Passives)
+}
+
+func (c NPSGroupCounts) ConfidenceInterval() NPSConfidenceInterval {
+	lower, upper := utils.CalculateNPSConfidenceInterval(c.Promoters, c.Detractors, c.Passives)
+
+	return NPSConfidenceInterval{
+		MarginOfError: utils.CalculateNPSMarginOfError(c.Promoters, c.Detractors, c.Passives),
+		Lower:         lower,
+		Upper:         upper,
+	}
+}
+
+// CompareTo tests the difference of the score from the score of another, independent group of
+// responses, such as the previous survey or another team, scored according to the scale's score
+// type. The difference is never significant if either group has no responses.
+func (c NPSGroupCounts) CompareTo(other NPSGroupCounts, scale RatingScale) NPSComparison {
+	score, otherScore := scale.CalculateScore(c), scale.CalculateScore(other)
+	comparison := NPSComparison{
+		Difference: score - otherScore,
+		PValue:     1,
+	}
+
+	if c.Total() == 0 || other.Total() == 0 {
+		return comparison
+	}
+
+	comparison.PValue = utils.CalculateNPSDifferencePValue(
+		score, scale.CalculateStandardError(c),
+		otherScore, scale.CalculateStandardError(other),
+	)
+	comparison.Significant = comparison.PValue < NPSSignificanceLevel
+
+	return comparison
+}
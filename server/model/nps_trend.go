@@ -0,0 +1,92 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"github.com/mattermost/mattermost-plugin-user-survey/server/utils"
+)
+
+const (
+	DefaultNPSTrendPerPage = 50
+	MaxNPSTrendPerPage     = 200
+)
+
+// NPSTrendPoint is the NPS of an ended survey, as a point of the NPS trend across surveys.
+type NPSTrendPoint struct {
+	SurveyID  string `json:"surveyID"`
+	StartTime int64  `json:"startTime"`
+	EndTime   int64  `json:"endTime"`
+
+	ReceiptCount  int64 `json:"receiptCount"`
+	ResponseCount int64 `json:"responseCount"`
+
+	// ResponseRate is the percentage of the users the survey was delivered to that responded.
+	ResponseRate float64 `json:"responseRate"`
+
+	PromoterCount  int64 `json:"promoterCount"`
+	PassiveCount   int64 `json:"passiveCount"`
+	DetractorCount int64 `json:"detractorCount"`
+
+	// NPS and its confidence interval are nil if the survey's results
+	// are withheld to keep its respondents anonymous.
+	NPS             *float64               `json:"nps"`
+	NPSConfidence   *NPSConfidenceInterval `json:"npsConfidence,omitempty"`
+	ResultsWithheld bool                   `json:"resultsWithheld"`
+
+	// ChangeFromPrevious compares the score with the previous survey of the trend,
+	// telling whether the change is significant. It's nil for the first survey
+	// and if either survey's results are withheld.
+	ChangeFromPrevious *NPSComparison `json:"changeFromPrevious,omitempty"`
+}
+
+// NPSTrend is a page of the NPS trend across ended surveys, oldest survey first.
+type NPSTrend struct {
+	Page    int             `json:"page"`
+	PerPage int             `json:"perPage"`
+	HasMore bool            `json:"hasMore"`
+	Points  []NPSTrendPoint `json:"points"`
+}
+
+// NPSTrendFilter selects the surveys of the NPS trend.
+type NPSTrendFilter struct {
+	// QuestionID restricts the trend to the surveys with the question. Questions keep their IDs
+	// across the surveys started from the plugin configuration, so it selects comparable surveys.
+	QuestionID string
+
+	Page    int
+	PerPage int
+}
+
+// ToNPSTrendPoint returns the stat as a point of the NPS trend, compared to the previous
+// survey's stat if there's one. The stats' unreportable results must already be withheld.
+func (stat *SurveyStat) ToNPSTrendPoint(previous *SurveyStat) NPSTrendPoint {
+	point := NPSTrendPoint{
+		SurveyID:        stat.ID,
+		StartTime:       stat.StartTime,
+		EndTime:         stat.GetEndTime().UnixMilli(),
+		ReceiptCount:    stat.ReceiptCount,
+		ResponseCount:   stat.ResponseCount,
+		PromoterCount:   stat.PromoterCount,
+		PassiveCount:    stat.PassiveCount,
+		DetractorCount:  stat.DetractorCount,
+		ResponseRate:    stat.GetResponseRate(),
+		ResultsWithheld: stat.ResultsWithheld,
+	}
+
+	if stat.ResultsWithheld {
+		return point
+	}
+
+	nps := utils.CalculateNPS(stat.PromoterCount, stat.DetractorCount, stat.PassiveCount)
+	confidence := stat.GetNPSGroupCounts().ConfidenceInterval()
+	point.NPS = &nps
+	point.NPSConfidence = &confidence
+
+	if previous != nil && !previous.ResultsWithheld {
+		change := stat.GetNPSGroupCounts().CompareTo(previous.GetNPSGroupCounts(), stat.GetRatingScale())
+		point.ChangeFromPrevious = &change
+	}
+
+	return point
+}
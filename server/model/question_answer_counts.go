@@ -0,0 +1,111 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"sort"
+)
+
+const (
+	// MaxQuestionIDLength is the length, in characters, of the question
+	// column the question answer counts are stored in.
+	MaxQuestionIDLength = 100
+)
+
+// QuestionAnswerKey identifies the answers to a question counted together in the question answer counts.
+type QuestionAnswerKey struct {
+	QuestionID string
+	Answer     string
+}
+
+// QuestionAnswerCountDelta is the change saving or withdrawing a response makes
+// to the number of the survey's responses with an answer to a question.
+type QuestionAnswerCountDelta struct {
+	QuestionAnswerKey
+	Delta int64
+}
+
+// QuestionAnswerCounts are the number of a survey's responses with each answer to each question,
+// kept up to date as responses are saved so the question stats don't need to scan the responses.
+type QuestionAnswerCounts map[QuestionAnswerKey]int64
+
+// GetAnswerCountKey returns the key the answer to the question is counted under, and false if
+// the answer is empty and isn't counted. Only ratings are counted by their value. Other answers,
+// such as text answers which are free text and encrypted when stored, are all counted under
+// an empty answer, counting only the responses that answered.
+func (q Question) GetAnswerCountKey(answer string) (QuestionAnswerKey, bool) {
+	if answer == "" {
+		return QuestionAnswerKey{}, false
+	}
+
+	if q.Type != QuestionTypeLinearScale {
+		answer = ""
+	}
+
+	return QuestionAnswerKey{QuestionID: q.ID, Answer: answer}, true
+}
+
+// AddResponse counts the answers of the response to the questions,
+// or discounts them if factor is -1, such as for a replaced response.
+func (c QuestionAnswerCounts) AddResponse(questions []Question, response *SurveyResponse, factor int64) {
+	if response == nil {
+		return
+	}
+
+	for _, question := range questions {
+		if key, ok := question.GetAnswerCountKey(response.Response[question.ID]); ok {
+			c[key] += factor
+		}
+	}
+}
+
+// ToDeltas returns the non-zero counts as deltas, sorted by question and answer
+// so concurrent transactions applying them lock the rows in the same order.
+func (c QuestionAnswerCounts) ToDeltas() []QuestionAnswerCountDelta {
+	deltas := []QuestionAnswerCountDelta{}
+	for key, count := range c {
+		if count != 0 {
+			deltas = append(deltas, QuestionAnswerCountDelta{QuestionAnswerKey: key, Delta: count})
+		}
+	}
+
+	sort.Slice(deltas, func(i, j int) bool {
+		if deltas[i].QuestionID != deltas[j].QuestionID {
+			return deltas[i].QuestionID < deltas[j].QuestionID
+		}
+
+		return deltas[i].Answer < deltas[j].Answer
+	})
+
+	return deltas
+}
+
+// Equal tells whether both have the same counts, an answer that isn't counted being the same as a count of zero.
+func (c QuestionAnswerCounts) Equal(other QuestionAnswerCounts) bool {
+	for key, count := range c {
+		if other[key] != count {
+			return false
+		}
+	}
+
+	for key, count := range other {
+		if c[key] != count {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetQuestionAnswerCountDeltas computes the change replacing the old response with the new one makes
+// to the question answer counts. The old response is nil for new responses, and the new response nil
+// for withdrawn responses. Text answers only need to be compared by whether they're empty,
+// so either response may have its text answers encrypted.
+func GetQuestionAnswerCountDeltas(questions []Question, oldResponse, newResponse *SurveyResponse) []QuestionAnswerCountDelta {
+	counts := QuestionAnswerCounts{}
+	counts.AddResponse(questions, oldResponse, -1)
+	counts.AddResponse(questions, newResponse, 1)
+
+	return counts.ToDeltas()
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strconv"
+)
+
+// QuestionStat is the aggregate of the answers to a survey question.
+type QuestionStat struct {
+	QuestionID string `json:"questionID"`
+	Type       string `json:"type"`
+
+	// ResponseCount is the number of responses that answered the question.
+	ResponseCount int64 `json:"responseCount"`
+
+	// RatingDistribution holds the number of responses with each rating of the question's scale,
+	// from the lowest rating. It's only set for linear scale questions, along with the mean and median rating.
+	RatingDistribution []int64  `json:"ratingDistribution,omitempty"`
+	MeanRating         *float64 `json:"meanRating,omitempty"`
+	MedianRating       *float64 `json:"medianRating,omitempty"`
+}
+
+// SurveyQuestionStats are the question stats of a survey. The stats are empty
+// if the survey's results are withheld to keep its respondents anonymous.
+type SurveyQuestionStats struct {
+	SurveyID        string         `json:"surveyID"`
+	ResultsWithheld bool           `json:"resultsWithheld"`
+	Questions       []QuestionStat `json:"questions"`
+}
+
+// NewTextQuestionStat creates the stat of a text question. Only the number
+// of answers is aggregated, as the answers themselves are free text.
+func NewTextQuestionStat(question Question, responseCount int64) QuestionStat {
+	return QuestionStat{
+		QuestionID:    question.ID,
+		Type:          question.Type,
+		ResponseCount: responseCount,
+	}
+}
+
+// NewLinearScaleQuestionStat creates the stat of a linear scale question
+// from the number of responses with each rating.
+func NewLinearScaleQuestionStat(question Question, answerCounts map[string]int64) QuestionStat {
+	stat := QuestionStat{
+		QuestionID: question.ID,
+		Type:       question.Type,
+	}
+
+	for _, count := range answerCounts {
+		stat.ResponseCount += count
+	}
+
+	scale := question.GetRatingScale()
+	stat.RatingDistribution = make([]int64, scale.Max-scale.Min+1)
+	for answer, count := range answerCounts {
+		rating, err := strconv.Atoi(answer)
+		if err != nil || !scale.Contains(rating) {
+			continue
+		}
+
+		stat.RatingDistribution[rating-scale.Min] += count
+	}
+
+	stat.MeanRating, stat.MedianRating = getMeanAndMedianRating(stat.RatingDistribution, scale.Min)
+	return stat
+}
+
+func getMeanAndMedianRating(distribution []int64, minRating int) (*float64, *float64) {
+	var total, sum int64
+	for i, count := range distribution {
+		total += count
+		sum += int64(i+minRating) * count
+	}
+
+	if total == 0 {
+		return nil, nil
+	}
+
+	mean := float64(sum) / float64(total)
+
+	// the median is the middle rating, or the average of the two middle ratings for an even count
+	lowerMiddle := ratingAtPosition(distribution, minRating, (total+1)/2)
+	upperMiddle := ratingAtPosition(distribution, minRating, total/2+1)
+	median := float64(lowerMiddle+upperMiddle) / 2
+
+	return &mean, &median
+}
+
+// ratingAtPosition returns the rating at the 1-based position among the sorted ratings.
+func ratingAtPosition(distribution []int64, minRating int, position int64) int {
+	var seen int64
+	for i, count := range distribution {
+		seen += count
+		if seen >= position {
+			return i + minRating
+		}
+	}
+
+	return len(distribution) - 1 + minRating
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/utils"
+)
+
+const (
+	// RatingScoreTypeNet scores the percentage of promoters minus the percentage
+	// of detractors, which is the NPS for the default 0 to 10 scale.
+	RatingScoreTypeNet = "net"
+
+	// RatingScoreTypeTopGroup scores the percentage of promoters,
+	// such as the share of satisfied users for a 1 to 5 CSAT scale.
+	RatingScoreTypeTopGroup = "top_group"
+
+	RatingGroupPromoters  = "promoters"
+	RatingGroupPassives   = "passives"
+	RatingGroupDetractors = "detractors"
+)
+
+// RatingGroups are the rating groups, from the highest ratings to the lowest.
+var RatingGroups = []string{RatingGroupPromoters, RatingGroupPassives, RatingGroupDetractors}
+
+// DefaultRatingScale is the NPS scale, used for linear scale questions without a scale configured.
+var DefaultRatingScale = RatingScale{
+	Min:          0,
+	Max:          10,
+	DetractorMax: 6,
+	PassiveMax:   8,
+	ScoreType:    RatingScoreTypeNet,
+}
+
+// RatingScale is the range of ratings of a linear scale question and how the ratings are grouped
+// for scoring. Ratings from Min to DetractorMax are detractors, ratings up to PassiveMax are passives
+// and the remaining ratings up to Max are promoters. For example, a 1 to 5 CSAT scale could count
+// 1 and 2 as detractors, 3 as passive and 4 and 5 as promoters.
+type RatingScale struct {
+	Min          int    `json:"min"`
+	Max          int    `json:"max"`
+	DetractorMax int    `json:"detractorMax"`
+	PassiveMax   int    `json:"passiveMax"`
+	ScoreType    string `json:"scoreType,omitempty"`
+}
+
+func (r RatingScale) IsValid() error {
+	if r.Min >= r.Max {
+		return errors.New("rating scale minimum must be lower than its maximum")
+	}
+
+	if r.DetractorMax < r.Min || r.PassiveMax < r.DetractorMax || r.PassiveMax >= r.Max {
+		return errors.New("rating scale group thresholds must be within the scale, with detractors below passives and passives below promoters")
+	}
+
+	if r.ScoreType != "" && r.ScoreType != RatingScoreTypeNet && r.ScoreType != RatingScoreTypeTopGroup {
+		return errors.New("unknown rating scale score type: " + r.ScoreType)
+	}
+
+	return nil
+}
+
+func (r RatingScale) GetScoreType() string {
+	if r.ScoreType == "" {
+		return RatingScoreTypeNet
+	}
+
+	return r.ScoreType
+}
+
+func (r RatingScale) Contains(rating int) bool {
+	return rating >= r.Min && rating <= r.Max
+}
+
+// GetRatingGroupFactors returns 1 for the rating group the rating belongs to, and 0 for the others.
+// Ratings outside of the scale don't belong to any group.
+func (r RatingScale) GetRatingGroupFactors(rating int) (promoterFactor, neutralFactor, detractorFactor int) {
+	switch {
+	case !r.Contains(rating):
+	case rating <= r.DetractorMax:
+		detractorFactor = 1
+	case rating <= r.PassiveMax:
+		neutralFactor = 1
+	default:
+		promoterFactor = 1
+	}
+
+	return promoterFactor, neutralFactor, detractorFactor
+}
+
+// GetRatingGroup returns the name of the rating group the rating belongs to,
+// or an empty string for ratings outside of the scale.
+func (r RatingScale) GetRatingGroup(rating int) string {
+	promoterFactor, neutralFactor, detractorFactor := r.GetRatingGroupFactors(rating)
+
+	switch {
+	case promoterFactor == 1:
+		return RatingGroupPromoters
+	case neutralFactor == 1:
+		return RatingGroupPassives
+	case detractorFactor == 1:
+		return RatingGroupDetractors
+	default:
+		return ""
+	}
+}
+
+func (r RatingScale) HasRatingGroupChanged(oldRating, newRating int) bool {
+	oldPromoterFactor, oldNeutralFactor, oldDetractorFactor := r.GetRatingGroupFactors(oldRating)
+	newPromoterFactor, newNeutralFactor, newDetractorFactor := r.GetRatingGroupFactors(newRating)
+
+	return oldPromoterFactor != newPromoterFactor || oldNeutralFactor != newNeutralFactor || oldDetractorFactor != newDetractorFactor
+}
+
+// CalculateScore computes the score of the rating group counts according to the scale's score type.
+func (r RatingScale) CalculateScore(counts NPSGroupCounts) float64 {
+	if r.GetScoreType() == RatingScoreTypeNet {
+		return counts.NPS()
+	}
+
+	if counts.Total() == 0 {
+		return 0.0
+	}
+
+	return float64(counts.Promoters) / float64(counts.Total()) * 100
+}
+
+// CalculateStandardError computes the standard error of the score of the rating group counts, in score points.
+func (r RatingScale) CalculateStandardError(counts NPSGroupCounts) float64 {
+	if r.GetScoreType() == RatingScoreTypeNet {
+		return utils.CalculateNPSStandardError(counts.Promoters, counts.Detractors, counts.Passives)
+	}
+
+	return utils.CalculateProportionStandardError(counts.Promoters, counts.Total())
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"regexp"
+	"slices"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	RedactionDetectorEmail   = "email"
+	RedactionDetectorPhone   = "phone"
+	RedactionDetectorMention = "mention"
+)
+
+// redactionDetectors are the built-in detectors, in the order they're applied.
+// Emails are redacted before mentions so the domain of an email isn't taken for a mention.
+var redactionDetectors = []redactionRule{
+	{name: RedactionDetectorEmail, pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)},
+	{name: RedactionDetectorPhone, pattern: regexp.MustCompile(`(?:\+\d{1,3}[\s.\-]?)?(?:\(\d{2,4}\)|\d{2,4})[\s.\-]?\d{3,4}[\s.\-]?\d{3,4}`)},
+	{name: RedactionDetectorMention, pattern: regexp.MustCompile(`\B@[A-Za-z0-9][A-Za-z0-9._\-]*`)},
+}
+
+// Redaction configures redacting personal details, such as emails, phone numbers
+// and names, from the text answers of survey responses before they're stored.
+type Redaction struct {
+	Enabled bool `json:"enabled"`
+
+	// Detectors are the built-in detectors to apply, any of email, phone and mention.
+	// All built-in detectors are applied if none are specified.
+	Detectors []string `json:"detectors,omitempty"`
+
+	// CustomPatterns are additional regular expressions to redact, such as employee IDs
+	// or the names of people that are frequently mentioned in the answers.
+	CustomPatterns []RedactionPattern `json:"customPatterns,omitempty"`
+}
+
+type RedactionPattern struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+}
+
+type redactionRule struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// Redactor replaces the personal details detected in text with a placeholder naming what was redacted.
+type Redactor struct {
+	rules []redactionRule
+}
+
+func (r Redaction) IsValid() error {
+	for _, detector := range r.Detectors {
+		if !slices.ContainsFunc(redactionDetectors, func(rule redactionRule) bool { return rule.name == detector }) {
+			return errors.New("unknown redaction detector: " + detector)
+		}
+	}
+
+	for _, customPattern := range r.CustomPatterns {
+		if customPattern.Name == "" {
+			return errors.New("custom redaction pattern name cannot be empty")
+		}
+
+		if _, err := regexp.Compile(customPattern.Pattern); err != nil {
+			return errors.Wrap(err, "invalid custom redaction pattern "+customPattern.Name)
+		}
+	}
+
+	return nil
+}
+
+// NewRedactor compiles the configured detectors and custom patterns.
+// The redactor doesn't redact anything if redaction isn't enabled.
+func (r Redaction) NewRedactor() (*Redactor, error) {
+	redactor := &Redactor{}
+	if !r.Enabled {
+		return redactor, nil
+	}
+
+	if err := r.IsValid(); err != nil {
+		return nil, err
+	}
+
+	for _, rule := range redactionDetectors {
+		if len(r.Detectors) == 0 || slices.Contains(r.Detectors, rule.name) {
+			redactor.rules = append(redactor.rules, rule)
+		}
+	}
+
+	for _, customPattern := range r.CustomPatterns {
+		redactor.rules = append(redactor.rules, redactionRule{
+			name:    customPattern.Name,
+			pattern: regexp.MustCompile(customPattern.Pattern),
+		})
+	}
+
+	return redactor, nil
+}
+
+// Redact returns the text with the detected personal details replaced,
+// along with the number of redactions made.
+func (r *Redactor) Redact(text string) (string, int) {
+	count := 0
+	for _, rule := range r.rules {
+		placeholder := "[redacted " + rule.name + "]"
+		text = rule.pattern.ReplaceAllStringFunc(text, func(match string) string {
+			if match == "" {
+				return match
+			}
+
+			count++
+			return placeholder
+		})
+	}
+
+	return text, count
+}
+
+// RedactResponse redacts the answers to the survey's text questions in place.
+// It returns the number of redactions made.
+func (r *Redactor) RedactResponse(response *SurveyResponse, questions SurveyQuestions) int {
+	if len(r.rules) == 0 {
+		return 0
+	}
+
+	count := 0
+	for _, question := range questions.Questions {
+		if question.Type != QuestionType {
+			continue
+		}
+
+		answer, ok := response.Response[question.ID]
+		if !ok || answer == "" {
+			continue
+		}
+
+		redacted, redactions := r.Redact(answer)
+		response.Response[question.ID] = redacted
+		count += redactions
+	}
+
+	return count
+}
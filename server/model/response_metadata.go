@@ -0,0 +1,71 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	ClientTypeWeb     = "web"
+	ClientTypeDesktop = "desktop"
+	ClientTypeMobile  = "mobile"
+)
+
+// ResponseMetadata is the context a response was submitted in,
+// used for segmenting the responses in the survey report.
+// It isn't captured for anonymous surveys.
+type ResponseMetadata struct {
+	// ClientType is the type of client the response was submitted from, one of web, desktop or mobile.
+	ClientType string `json:"clientType,omitempty"`
+
+	DeliveredAt  int64 `json:"deliveredAt,omitempty"` // time the survey post was created
+	FirstRatedAt int64 `json:"firstRatedAt,omitempty"`
+	CompletedAt  int64 `json:"completedAt,omitempty"`
+
+	// Teams holds the teams the user was a member of when submitting the response.
+	Teams []ResponseTeam `json:"teams,omitempty"`
+}
+
+type ResponseTeam struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+}
+
+// GetClientType determines the type of client from the user agent of its request.
+func GetClientType(userAgent string) string {
+	switch {
+	case strings.Contains(userAgent, "Mattermost/") || strings.Contains(userAgent, "Electron/"):
+		return ClientTypeDesktop
+	case strings.Contains(userAgent, "Mobile") || strings.Contains(userAgent, "Android"):
+		return ClientTypeMobile
+	default:
+		return ClientTypeWeb
+	}
+}
+
+// ToReportColumns returns the client type, the seconds from delivery to the first rating
+// and to completing the survey, and the user's teams. Values that weren't captured are empty.
+func (m ResponseMetadata) ToReportColumns() []string {
+	teamNames := make([]string, 0, len(m.Teams))
+	for _, team := range m.Teams {
+		teamNames = append(teamNames, team.DisplayName)
+	}
+
+	return []string{
+		m.ClientType,
+		secondsSince(m.DeliveredAt, m.FirstRatedAt),
+		secondsSince(m.DeliveredAt, m.CompletedAt),
+		strings.Join(teamNames, ", "),
+	}
+}
+
+func secondsSince(start, end int64) string {
+	if start == 0 || end == 0 || end < start {
+		return ""
+	}
+
+	return strconv.FormatInt((end-start)/1000, 10)
+}
@@ -0,0 +1,208 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"math"
+	"sort"
+	"strconv"
+)
+
+const (
+	SegmentTypeOverall     = "overall"
+	SegmentTypeRatingGroup = "rating_group"
+	SegmentTypeTeam        = "team"
+)
+
+// SegmentKey identifies a segment of a survey's respondents: all of them, a rating group or a team.
+// The ID is empty for the overall segment, the rating group for rating groups and the team ID for teams.
+type SegmentKey struct {
+	Type string
+	ID   string
+}
+
+// SegmentCounts are the rating groups and sentiment scores of the responses in a segment,
+// or the change a response makes to them when used as a delta.
+type SegmentCounts struct {
+	// TeamName is the latest display name of a team segment, kept in case the team is renamed
+	// during the survey. It's empty when the change doesn't come with a team name.
+	TeamName string
+
+	// Promoters, Passives and Detractors count the rated responses, only for team segments.
+	Promoters  int64
+	Passives   int64
+	Detractors int64
+
+	// SentimentRespondents counts the responses with scored answers, and SentimentAnswers their scored answers.
+	// SentimentTotal is the sum of the scores in thousandths, as scores are rounded to three decimals,
+	// so adding and removing scores doesn't accumulate rounding errors.
+	SentimentRespondents int64
+	SentimentAnswers     int64
+	SentimentTotal       int64
+}
+
+// SegmentCountsDelta is the change saving or withdrawing a response makes to a segment's counts.
+type SegmentCountsDelta struct {
+	SegmentKey
+	SegmentCounts
+}
+
+// SurveySegmentCounts are the counts of a survey's segments, kept up to date as responses are saved
+// so the team NPS and sentiment breakdowns don't need to scan the responses.
+type SurveySegmentCounts map[SegmentKey]*SegmentCounts
+
+func (c *SegmentCounts) isZero() bool {
+	return c.Promoters == 0 && c.Passives == 0 && c.Detractors == 0 &&
+		c.SentimentRespondents == 0 && c.SentimentAnswers == 0 && c.SentimentTotal == 0
+}
+
+// GetRespondents returns the number of rated responses of a team segment.
+func (c *SegmentCounts) GetRespondents() int64 {
+	return c.Promoters + c.Passives + c.Detractors
+}
+
+func (c *SegmentCounts) toSentimentAverage() SentimentAverage {
+	average := SentimentAverage{
+		Respondents: c.SentimentRespondents,
+		AnswerCount: c.SentimentAnswers,
+	}
+
+	if c.SentimentAnswers > 0 {
+		average.Average = math.Round(float64(c.SentimentTotal)/float64(c.SentimentAnswers)) / 1000
+	}
+
+	return average
+}
+
+func (c SurveySegmentCounts) get(key SegmentKey) *SegmentCounts {
+	counts, ok := c[key]
+	if !ok {
+		counts = &SegmentCounts{}
+		c[key] = counts
+	}
+
+	return counts
+}
+
+// AddResponse counts the response in its segments, or discounts it if factor is -1, such as for
+// a replaced response. The response's rating is counted towards each of the teams in its metadata,
+// and its sentiment scores overall, in its rating group and in its teams. The rating question is
+// empty for surveys without one, which only counts the sentiment scores overall and by team.
+func (c SurveySegmentCounts) AddResponse(ratingQuestion Question, response *SurveyResponse, factor int64) {
+	if response == nil {
+		return
+	}
+
+	var promoterFactor, neutralFactor, detractorFactor int
+	ratingGroup := ""
+	rating, err := strconv.Atoi(response.Response[ratingQuestion.ID])
+	rated := ratingQuestion.ID != "" && err == nil
+	if rated {
+		scale := ratingQuestion.GetRatingScale()
+		promoterFactor, neutralFactor, detractorFactor = scale.GetRatingGroupFactors(rating)
+		ratingGroup = scale.GetRatingGroup(rating)
+	}
+
+	var sentimentTotal int64
+	for _, score := range response.Sentiment {
+		sentimentTotal += int64(math.Round(score * 1000))
+	}
+
+	scored := len(response.Sentiment) > 0
+	addSentiment := func(counts *SegmentCounts) {
+		if scored {
+			counts.SentimentRespondents += factor
+			counts.SentimentAnswers += factor * int64(len(response.Sentiment))
+			counts.SentimentTotal += factor * sentimentTotal
+		}
+	}
+
+	addSentiment(c.get(SegmentKey{Type: SegmentTypeOverall}))
+
+	if ratingGroup != "" {
+		addSentiment(c.get(SegmentKey{Type: SegmentTypeRatingGroup, ID: ratingGroup}))
+	}
+
+	for _, team := range response.Metadata.Teams {
+		teamCounts := c.get(SegmentKey{Type: SegmentTypeTeam, ID: team.ID})
+
+		// only counting a response brings the team's name up to date, so discounting
+		// an older version of a response can't bring back an older name
+		if factor > 0 {
+			teamCounts.TeamName = team.DisplayName
+		}
+
+		if rated {
+			teamCounts.Promoters += factor * int64(promoterFactor)
+			teamCounts.Passives += factor * int64(neutralFactor)
+			teamCounts.Detractors += factor * int64(detractorFactor)
+		}
+
+		addSentiment(teamCounts)
+	}
+}
+
+// ToDeltas returns the non-zero counts and the team name updates as deltas, sorted by segment
+// so concurrent transactions applying them lock the rows in the same order.
+func (c SurveySegmentCounts) ToDeltas() []SegmentCountsDelta {
+	deltas := []SegmentCountsDelta{}
+	for key, counts := range c {
+		if !counts.isZero() || counts.TeamName != "" {
+			deltas = append(deltas, SegmentCountsDelta{SegmentKey: key, SegmentCounts: *counts})
+		}
+	}
+
+	sort.Slice(deltas, func(i, j int) bool {
+		if deltas[i].Type != deltas[j].Type {
+			return deltas[i].Type < deltas[j].Type
+		}
+
+		return deltas[i].ID < deltas[j].ID
+	})
+
+	return deltas
+}
+
+// Equal tells whether both have the same counts, a segment that isn't counted being the same
+// as one with zero counts. Team names aren't compared, as they're only informative.
+func (c SurveySegmentCounts) Equal(other SurveySegmentCounts) bool {
+	countsEqual := func(a, b *SegmentCounts) bool {
+		if a == nil || b == nil {
+			return (a == nil || a.isZero()) && (b == nil || b.isZero())
+		}
+
+		withoutName, otherWithoutName := *a, *b
+		withoutName.TeamName, otherWithoutName.TeamName = "", ""
+		return withoutName == otherWithoutName
+	}
+
+	for key, counts := range c {
+		if !countsEqual(counts, other[key]) {
+			return false
+		}
+	}
+
+	for key, counts := range other {
+		if !countsEqual(c[key], counts) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetSegmentCountDeltas computes the change replacing the old response with the new one makes to the
+// survey's segment counts. The old response is nil for new responses, and the new response nil for
+// withdrawn responses. Only the ratings, metadata and sentiment scores of the responses are used,
+// which aren't encrypted.
+func GetSegmentCountDeltas(survey *Survey, oldResponse, newResponse *SurveyResponse) []SegmentCountsDelta {
+	// surveys without a rating question are counted with an empty question, which has no answers
+	ratingQuestion, _ := survey.GetSystemRatingQuestion()
+
+	counts := SurveySegmentCounts{}
+	counts.AddResponse(ratingQuestion, oldResponse, -1)
+	counts.AddResponse(ratingQuestion, newResponse, 1)
+
+	return counts.ToDeltas()
+}
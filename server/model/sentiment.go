@@ -0,0 +1,215 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// a negator inverts, at half strength, the valence of the words in the rest of its clause up to this many words
+	sentimentNegationScope  = 3
+	sentimentNegationFactor = -0.5
+
+	sentimentIntensifierFactor = 1.5
+
+	// sentimentNormalizationAlpha bounds the sum of the valences to the -1 to 1 range,
+	// with texts with more sentiment words approaching the bounds.
+	sentimentNormalizationAlpha = 15
+)
+
+// ScoreSentiment scores the sentiment of the text from -1, the most negative, to 1, the most positive,
+// from the valence of its words in the embedded lexicon. Text without any words of the lexicon scores 0.
+func ScoreSentiment(text string) float64 {
+	valence := 0.0
+
+	for _, clause := range splitClauses(text) {
+		negatedWords := 0
+		intensified := false
+
+		for _, word := range clause {
+			if sentimentNegators[word] {
+				negatedWords = sentimentNegationScope
+				intensified = false
+				continue
+			}
+
+			if wordValence, ok := sentimentLexicon[word]; ok {
+				if intensified {
+					wordValence *= sentimentIntensifierFactor
+				}
+
+				if negatedWords > 0 {
+					wordValence *= sentimentNegationFactor
+				}
+
+				valence += wordValence
+			}
+
+			intensified = sentimentIntensifiers[word]
+			if negatedWords > 0 {
+				negatedWords--
+			}
+		}
+	}
+
+	score := valence / math.Sqrt(valence*valence+sentimentNormalizationAlpha)
+	return math.Round(score*1000) / 1000
+}
+
+// ScoreSentiment scores the sentiment of each of the response's text answers.
+// Empty answers aren't scored.
+func (sr *SurveyResponse) ScoreSentiment(questions []Question) {
+	sr.Sentiment = map[string]float64{}
+
+	for _, question := range questions {
+		if question.Type != QuestionType {
+			continue
+		}
+
+		answer := strings.TrimSpace(sr.Response[question.ID])
+		if answer == "" {
+			continue
+		}
+
+		sr.Sentiment[question.ID] = ScoreSentiment(answer)
+	}
+}
+
+// GetMeanSentiment returns the mean sentiment score of the response's text answers,
+// and false if none of its answers were scored.
+func (sr *SurveyResponse) GetMeanSentiment() (float64, bool) {
+	if len(sr.Sentiment) == 0 {
+		return 0, false
+	}
+
+	total := 0.0
+	for _, score := range sr.Sentiment {
+		total += score
+	}
+
+	return total / float64(len(sr.Sentiment)), true
+}
+
+// formatSentiment formats the mean sentiment of the response for the survey report,
+// leaving it empty if none of the response's answers were scored.
+func (sr *SurveyResponse) formatSentiment() string {
+	sentiment, ok := sr.GetMeanSentiment()
+	if !ok {
+		return ""
+	}
+
+	return strconv.FormatFloat(sentiment, 'f', 3, 64)
+}
+
+// SentimentAverage is the average sentiment score of the text answers of a group of respondents.
+type SentimentAverage struct {
+	Respondents int64   `json:"respondents"`
+	AnswerCount int64   `json:"answerCount"`
+	Average     float64 `json:"average"`
+}
+
+// RatingGroupSentiment is the average sentiment of the respondents in a rating group.
+// It's withheld if the group has too few respondents to keep them anonymous.
+type RatingGroupSentiment struct {
+	Group    string `json:"group"`
+	Withheld bool   `json:"withheld"`
+	SentimentAverage
+}
+
+// TeamSentiment is the average sentiment of the respondents who were members of the team when answering.
+type TeamSentiment struct {
+	TeamID   string `json:"teamID"`
+	TeamName string `json:"teamName"`
+	SentimentAverage
+}
+
+// SurveySentiment is the average sentiment of a survey's text answers, overall,
+// by rating group and by team. Teams with fewer than MinRespondents respondents
+// are withheld, as with the team NPS breakdown. The results of anonymous surveys
+// with too few respondents are withheld altogether.
+type SurveySentiment struct {
+	SurveyID        string `json:"surveyID"`
+	MinRespondents  int    `json:"minRespondents"`
+	ResultsWithheld bool   `json:"resultsWithheld"`
+	SentimentAverage
+	RatingGroups  []RatingGroupSentiment `json:"ratingGroups"`
+	Teams         []TeamSentiment        `json:"teams"`
+	WithheldTeams int                    `json:"withheldTeams"`
+}
+
+// NewSurveySentiment computes the average sentiments from the survey's segment counts, with the teams
+// sorted by name. For anonymous surveys, the results and rating groups with fewer respondents than
+// the minimum group size are withheld.
+func NewSurveySentiment(survey *Survey, segmentCounts SurveySegmentCounts) *SurveySentiment {
+	anonymity := survey.Anonymity
+
+	surveySentiment := &SurveySentiment{
+		SurveyID:       survey.ID,
+		MinRespondents: anonymity.GetMinGroupSize(),
+		RatingGroups:   []RatingGroupSentiment{},
+		Teams:          []TeamSentiment{},
+	}
+
+	getCounts := func(key SegmentKey) *SegmentCounts {
+		if counts, ok := segmentCounts[key]; ok {
+			return counts
+		}
+
+		return &SegmentCounts{}
+	}
+
+	overall := getCounts(SegmentKey{Type: SegmentTypeOverall})
+	if anonymity.Enabled && !anonymity.IsGroupReportable(overall.SentimentRespondents) {
+		surveySentiment.ResultsWithheld = true
+		return surveySentiment
+	}
+
+	surveySentiment.SentimentAverage = overall.toSentimentAverage()
+
+	// the rating groups are only known if the survey has a rating question
+	if _, err := survey.GetSystemRatingQuestion(); err == nil {
+		for _, group := range RatingGroups {
+			groupSentiment := RatingGroupSentiment{Group: group}
+
+			if groupCounts := getCounts(SegmentKey{Type: SegmentTypeRatingGroup, ID: group}); anonymity.Enabled && !anonymity.IsGroupReportable(groupCounts.SentimentRespondents) {
+				groupSentiment.Withheld = true
+			} else {
+				groupSentiment.SentimentAverage = groupCounts.toSentimentAverage()
+			}
+
+			surveySentiment.RatingGroups = append(surveySentiment.RatingGroups, groupSentiment)
+		}
+	}
+
+	for key, counts := range segmentCounts {
+		if key.Type != SegmentTypeTeam || counts.SentimentRespondents == 0 {
+			continue
+		}
+
+		if counts.SentimentRespondents < int64(surveySentiment.MinRespondents) {
+			surveySentiment.WithheldTeams++
+			continue
+		}
+
+		surveySentiment.Teams = append(surveySentiment.Teams, TeamSentiment{
+			TeamID:           key.ID,
+			TeamName:         counts.TeamName,
+			SentimentAverage: counts.toSentimentAverage(),
+		})
+	}
+
+	sort.Slice(surveySentiment.Teams, func(i, j int) bool {
+		if surveySentiment.Teams[i].TeamName != surveySentiment.Teams[j].TeamName {
+			return surveySentiment.Teams[i].TeamName < surveySentiment.Teams[j].TeamName
+		}
+
+		return surveySentiment.Teams[i].TeamID < surveySentiment.Teams[j].TeamID
+	})
+
+	return surveySentiment
+}
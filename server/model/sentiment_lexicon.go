@@ -0,0 +1,51 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+// sentimentLexicon holds the valence of English words, from -3 for the most negative
+// to 3 for the most positive, with a focus on the words used in product feedback.
+var sentimentLexicon = map[string]float64{
+	"good": 2, "great": 3, "excellent": 3, "amazing": 3, "awesome": 3, "fantastic": 3, "wonderful": 3,
+	"outstanding": 3, "superb": 3, "perfect": 3, "love": 3, "loved": 3, "loving": 2, "loves": 3,
+	"like": 1, "liked": 1, "likes": 1, "enjoy": 2, "enjoyed": 2, "enjoying": 2, "nice": 2, "cool": 1,
+	"happy": 2, "glad": 2, "pleased": 2, "satisfied": 2, "delighted": 3, "impressed": 2,
+	"impressive": 2, "helpful": 2, "useful": 2, "valuable": 2, "easy": 2, "easier": 2, "simple": 1,
+	"intuitive": 2, "smooth": 2, "seamless": 2, "clean": 1, "fast": 2, "faster": 2, "quick": 1,
+	"quicker": 1, "responsive": 2, "reliable": 2, "stable": 2, "solid": 2, "efficient": 2,
+	"productive": 2, "convenient": 2, "flexible": 1, "powerful": 2, "best": 3, "better": 2,
+	"improved": 2, "improvement": 1, "improvements": 1, "thanks": 2, "thank": 2, "appreciate": 2,
+	"appreciated": 2, "recommend": 2, "recommended": 2, "works": 1, "working": 1, "worked": 1,
+	"beautiful": 2, "polished": 2, "friendly": 2, "fine": 1, "okay": 1, "ok": 1, "fun": 2,
+	"exciting": 2, "excited": 2, "brilliant": 3, "comfortable": 1, "handy": 1, "essential": 1,
+	"secure": 1, "consistent": 1, "accessible": 1, "bad": -2, "terrible": -3, "awful": -3,
+	"horrible": -3, "worst": -3, "worse": -2, "poor": -2, "poorly": -2, "hate": -3, "hated": -3,
+	"hates": -3, "dislike": -2, "disliked": -2, "annoying": -2, "annoyed": -2, "annoys": -2,
+	"frustrating": -3, "frustrated": -3, "frustration": -2, "confusing": -2, "confused": -2,
+	"unclear": -1, "complicated": -2, "difficult": -2, "hard": -1, "slow": -2, "slower": -2,
+	"sluggish": -2, "laggy": -2, "lag": -1, "broken": -3, "bug": -1, "bugs": -2, "buggy": -2,
+	"crash": -2, "crashes": -2, "crashed": -2, "crashing": -2, "fail": -2, "fails": -2, "failed": -2,
+	"failing": -2, "failure": -2, "error": -1, "errors": -2, "issue": -1, "issues": -1, "problem": -1,
+	"problems": -2, "unstable": -2, "unreliable": -2, "useless": -3, "unusable": -3, "clunky": -2,
+	"cluttered": -1, "messy": -2, "ugly": -2, "missing": -1, "lacking": -1, "lacks": -1,
+	"disappointed": -2, "disappointing": -2, "unhappy": -2, "sad": -2, "angry": -3, "upset": -2,
+	"painful": -2, "pain": -2, "tedious": -2, "inconsistent": -1, "outdated": -1, "noisy": -1,
+	"overwhelming": -2, "overwhelmed": -2, "stuck": -2, "lost": -1, "waste": -2, "wasted": -2,
+	"wrong": -2, "lose": -1, "losing": -1, "impossible": -2, "worried": -1, "concern": -1,
+	"concerns": -1, "sucks": -3, "meh": -1, "mediocre": -1, "glitchy": -2, "glitch": -1,
+	"glitches": -1, "irritating": -2, "inefficient": -2, "insecure": -2, "expensive": -1,
+	"limited": -1, "spam": -2,
+}
+
+// sentimentNegators invert the valence of the words following them in the same clause.
+var sentimentNegators = map[string]bool{
+	"not": true, "no": true, "never": true, "nothing": true, "without": true, "hardly": true, "barely": true, "nor": true,
+	"don't": true, "doesn't": true, "didn't": true, "isn't": true, "wasn't": true, "aren't": true, "weren't": true,
+	"can't": true, "cannot": true, "couldn't": true, "won't": true, "wouldn't": true, "shouldn't": true, "haven't": true,
+}
+
+// sentimentIntensifiers strengthen the valence of the word following them.
+var sentimentIntensifiers = map[string]bool{
+	"very": true, "really": true, "extremely": true, "super": true, "so": true, "too": true, "incredibly": true,
+	"totally": true, "absolutely": true, "completely": true, "quite": true, "highly": true,
+}
@@ -4,6 +4,7 @@
 package model
 
 import (
+	"reflect"
 	"slices"
 	"time"
 
@@ -39,6 +40,11 @@ type Survey struct {
 	Duration        int             `json:"duration"`
 	SurveyQuestions SurveyQuestions `json:"surveyQuestions"`
 	Status          string          `json:"status"`
+	Customization   Customization   `json:"customization"`
+	Anonymity       Anonymity       `json:"anonymity"`
+
+	// ResponsesEditable lets users edit their complete response until the survey ends.
+	ResponsesEditable bool `json:"responsesEditable"`
 }
 
 func (s *Survey) SetDefaults() {
@@ -82,6 +88,18 @@ func (s *Survey) IsValid() error {
 		return errors.New("survey status cannot be empty")
 	}
 
+	if err := s.SurveyQuestions.IsValid(); err != nil {
+		return errors.Wrap(err, "survey questions are invalid")
+	}
+
+	if err := s.Customization.IsValid(); err != nil {
+		return errors.Wrap(err, "survey customization is invalid")
+	}
+
+	if err := s.Anonymity.IsValid(); err != nil {
+		return errors.Wrap(err, "survey anonymity is invalid")
+	}
+
 	return nil
 }
 
@@ -96,19 +114,33 @@ func (s *Survey) ShouldSurveyStop() bool {
 }
 
 func (s *Survey) GetSystemRatingQuestionID() (string, error) {
-	var questionID string
+	question, err := s.GetSystemRatingQuestion()
+	if err != nil {
+		return "", err
+	}
+
+	return question.ID, nil
+}
+
+// GetRatingScale returns the scale of the system rating question, the NPS scale if there's none.
+func (s *Survey) GetRatingScale() RatingScale {
+	question, err := s.GetSystemRatingQuestion()
+	if err != nil {
+		return DefaultRatingScale
+	}
+
+	return question.GetRatingScale()
+}
+
+// GetSystemRatingQuestion returns the linear scale question the survey's score is computed from.
+func (s *Survey) GetSystemRatingQuestion() (Question, error) {
 	for _, question := range s.SurveyQuestions.Questions {
 		if question.System && question.Type == QuestionTypeLinearScale {
-			questionID = question.ID
-			break
+			return question, nil
 		}
 	}
 
-	if questionID == "" {
-		return "", errors.New("no system rating question found")
-	}
-
-	return questionID, nil
+	return Question{}, errors.New("no system rating question found")
 }
 
 func (s *Survey) GetEndTime() time.Time {
@@ -133,6 +165,14 @@ func (s *Survey) IsEqual(survey *Survey) bool {
 		return false
 	}
 
+	if s.SurveyQuestions.DefaultLocale != survey.SurveyQuestions.DefaultLocale {
+		return false
+	}
+
+	if !reflect.DeepEqual(s.SurveyQuestions.Translations, survey.SurveyQuestions.Translations) {
+		return false
+	}
+
 	if s.TeamFilterType != survey.TeamFilterType {
 		return false
 	}
@@ -141,8 +181,21 @@ func (s *Survey) IsEqual(survey *Survey) bool {
 		return false
 	}
 
+	if s.Customization != survey.Customization {
+		return false
+	}
+
+	if s.Anonymity != survey.Anonymity {
+		return false
+	}
+
+	if s.ResponsesEditable != survey.ResponsesEditable {
+		return false
+	}
+
 	questionsEqual := slices.EqualFunc(s.SurveyQuestions.Questions, survey.SurveyQuestions.Questions, func(a, b Question) bool {
-		return a.Text == b.Text && a.Type == b.Type && a.System == b.System
+		return a.Text == b.Text && a.Type == b.Type && a.System == b.System &&
+			a.GetRatingScale() == b.GetRatingScale()
 	})
 
 	return questionsEqual
@@ -154,4 +207,15 @@ type Question struct {
 	Type      string `json:"type"`
 	System    bool   `json:"system"`
 	Mandatory bool   `json:"mandatory"`
+
+	// Scale is the rating scale of a linear scale question, the NPS scale if it isn't set.
+	Scale *RatingScale `json:"scale,omitempty"`
+}
+
+func (q Question) GetRatingScale() RatingScale {
+	if q.Scale == nil {
+		return DefaultRatingScale
+	}
+
+	return *q.Scale
 }
@@ -0,0 +1,292 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	QuestionMatchByID   = "id"
+	QuestionMatchByText = "text"
+)
+
+// MetricComparison is a metric of a survey next to the same metric of the survey it's compared to.
+type MetricComparison struct {
+	Value      float64 `json:"value"`
+	BaseValue  float64 `json:"baseValue"`
+	Difference float64 `json:"difference"`
+}
+
+// AnswerComparison is the number of responses with an answer, or in a rating group, in both surveys,
+// along with their share of the responses in percent.
+type AnswerComparison struct {
+	Answer    string           `json:"answer"`
+	Count     int64            `json:"count"`
+	BaseCount int64            `json:"baseCount"`
+	Share     MetricComparison `json:"share"`
+}
+
+// QuestionComparison compares the answers to a question of the survey with the answers to the matching
+// question of the base survey. Questions match by ID or, failing that, by identical text. Linear scale
+// and choice questions compare the share of each answer, and linear scale questions their mean rating.
+type QuestionComparison struct {
+	QuestionID     string             `json:"questionID"`
+	BaseQuestionID string             `json:"baseQuestionID"`
+	MatchedBy      string             `json:"matchedBy"`
+	Text           string             `json:"text"`
+	Type           string             `json:"type"`
+	ResponseCount  MetricComparison   `json:"responseCount"`
+	MeanRating     *MetricComparison  `json:"meanRating,omitempty"`
+	Answers        []AnswerComparison `json:"answers,omitempty"`
+}
+
+// TeamComparison compares the NPS of a team in both surveys. The NPS of a survey is
+// nil if the team wasn't reported in it, in which case the NPS isn't compared.
+type TeamComparison struct {
+	TeamID     string         `json:"teamID"`
+	TeamName   string         `json:"teamName"`
+	NPS        *float64       `json:"nps"`
+	BaseNPS    *float64       `json:"baseNPS"`
+	Comparison *NPSComparison `json:"comparison,omitempty"`
+}
+
+// SurveyComparison is the difference of a survey's results from the results of a base survey, such as
+// the same survey run the previous quarter. Only the response counts and rates are compared if the results
+// of either survey are withheld to keep its respondents anonymous.
+type SurveyComparison struct {
+	SurveyID        string           `json:"surveyID"`
+	BaseSurveyID    string           `json:"baseSurveyID"`
+	ResultsWithheld bool             `json:"resultsWithheld"`
+	ResponseCount   MetricComparison `json:"responseCount"`
+	ResponseRate    MetricComparison `json:"responseRate"`
+
+	NPS          *NPSComparison     `json:"nps,omitempty"`
+	RatingGroups []AnswerComparison `json:"ratingGroups"`
+
+	Questions                []QuestionComparison `json:"questions"`
+	UnmatchedQuestionIDs     []string             `json:"unmatchedQuestionIDs"`
+	UnmatchedBaseQuestionIDs []string             `json:"unmatchedBaseQuestionIDs"`
+
+	Teams []TeamComparison `json:"teams"`
+}
+
+func newMetricComparison(value, baseValue float64) MetricComparison {
+	return MetricComparison{
+		Value:      value,
+		BaseValue:  baseValue,
+		Difference: value - baseValue,
+	}
+}
+
+func newAnswerComparison(answer string, count, total, baseCount, baseTotal int64) AnswerComparison {
+	return AnswerComparison{
+		Answer:    answer,
+		Count:     count,
+		BaseCount: baseCount,
+		Share:     newMetricComparison(percentage(count, total), percentage(baseCount, baseTotal)),
+	}
+}
+
+func percentage(count, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return float64(count) / float64(total) * 100
+}
+
+// NewSurveyComparison compares the survey's stat and team NPS with the base survey's. The stats must
+// include the question stats, and their unreportable results must already be withheld.
+func NewSurveyComparison(stat, baseStat *SurveyStat, teamNPS, baseTeamNPS *SurveyTeamNPS) *SurveyComparison {
+	comparison := &SurveyComparison{
+		SurveyID:                 stat.ID,
+		BaseSurveyID:             baseStat.ID,
+		ResultsWithheld:          stat.ResultsWithheld || baseStat.ResultsWithheld,
+		ResponseCount:            newMetricComparison(float64(stat.ResponseCount), float64(baseStat.ResponseCount)),
+		ResponseRate:             newMetricComparison(stat.GetResponseRate(), baseStat.GetResponseRate()),
+		RatingGroups:             []AnswerComparison{},
+		Questions:                []QuestionComparison{},
+		UnmatchedQuestionIDs:     []string{},
+		UnmatchedBaseQuestionIDs: []string{},
+		Teams:                    []TeamComparison{},
+	}
+
+	if comparison.ResultsWithheld {
+		return comparison
+	}
+
+	counts, baseCounts := stat.GetNPSGroupCounts(), baseStat.GetNPSGroupCounts()
+	npsComparison := counts.CompareTo(baseCounts, stat.GetRatingScale())
+	comparison.NPS = &npsComparison
+
+	comparison.RatingGroups = []AnswerComparison{
+		newAnswerComparison(RatingGroupPromoters, counts.Promoters, counts.Total(), baseCounts.Promoters, baseCounts.Total()),
+		newAnswerComparison(RatingGroupPassives, counts.Passives, counts.Total(), baseCounts.Passives, baseCounts.Total()),
+		newAnswerComparison(RatingGroupDetractors, counts.Detractors, counts.Total(), baseCounts.Detractors, baseCounts.Total()),
+	}
+
+	comparison.compareQuestions(stat, baseStat)
+	comparison.compareTeams(teamNPS, baseTeamNPS)
+
+	return comparison
+}
+
+// compareQuestions matches the survey's questions with the base survey's questions, first by ID and
+// then by identical text, and compares their answers. Questions only match if they're of the same type,
+// and for linear scale questions, on the same rating scale.
+func (c *SurveyComparison) compareQuestions(stat, baseStat *SurveyStat) {
+	questions := stat.SurveyQuestions.Questions
+	baseQuestions := baseStat.SurveyQuestions.Questions
+
+	matches := make([]int, len(questions))
+	matchedBy := make([]string, len(questions))
+	baseMatched := make([]bool, len(baseQuestions))
+
+	matchQuestions := func(by string, isMatch func(question, baseQuestion Question) bool) {
+		for i, question := range questions {
+			if matchedBy[i] != "" {
+				continue
+			}
+
+			for j, baseQuestion := range baseQuestions {
+				if !baseMatched[j] && isComparableQuestion(question, baseQuestion) && isMatch(question, baseQuestion) {
+					matches[i], matchedBy[i], baseMatched[j] = j, by, true
+					break
+				}
+			}
+		}
+	}
+
+	matchQuestions(QuestionMatchByID, func(question, baseQuestion Question) bool {
+		return question.ID == baseQuestion.ID
+	})
+	matchQuestions(QuestionMatchByText, func(question, baseQuestion Question) bool {
+		text := strings.TrimSpace(question.Text)
+		return text != "" && text == strings.TrimSpace(baseQuestion.Text)
+	})
+
+	for i, question := range questions {
+		if matchedBy[i] == "" {
+			c.UnmatchedQuestionIDs = append(c.UnmatchedQuestionIDs, question.ID)
+			continue
+		}
+
+		baseQuestion := baseQuestions[matches[i]]
+		questionComparison := newQuestionComparison(question, getQuestionStat(stat, question.ID), getQuestionStat(baseStat, baseQuestion.ID))
+		questionComparison.BaseQuestionID = baseQuestion.ID
+		questionComparison.MatchedBy = matchedBy[i]
+		c.Questions = append(c.Questions, questionComparison)
+	}
+
+	for j, baseQuestion := range baseQuestions {
+		if !baseMatched[j] {
+			c.UnmatchedBaseQuestionIDs = append(c.UnmatchedBaseQuestionIDs, baseQuestion.ID)
+		}
+	}
+}
+
+func isComparableQuestion(question, baseQuestion Question) bool {
+	if question.Type != baseQuestion.Type {
+		return false
+	}
+
+	return question.Type != QuestionTypeLinearScale || question.GetRatingScale() == baseQuestion.GetRatingScale()
+}
+
+func getQuestionStat(stat *SurveyStat, questionID string) QuestionStat {
+	for _, questionStat := range stat.QuestionStats {
+		if questionStat.QuestionID == questionID {
+			return questionStat
+		}
+	}
+
+	return QuestionStat{QuestionID: questionID}
+}
+
+func newQuestionComparison(question Question, questionStat, baseQuestionStat QuestionStat) QuestionComparison {
+	comparison := QuestionComparison{
+		QuestionID:    question.ID,
+		Text:          question.Text,
+		Type:          question.Type,
+		ResponseCount: newMetricComparison(float64(questionStat.ResponseCount), float64(baseQuestionStat.ResponseCount)),
+	}
+
+	total, baseTotal := questionStat.ResponseCount, baseQuestionStat.ResponseCount
+
+	switch question.Type {
+	case QuestionType:
+		// text answers are free text, so only the number of answers is compared
+	case QuestionTypeLinearScale:
+		scale := question.GetRatingScale()
+		for rating := scale.Min; rating <= scale.Max; rating++ {
+			comparison.Answers = append(comparison.Answers, newAnswerComparison(
+				strconv.Itoa(rating),
+				getDistributionCount(questionStat.RatingDistribution, rating-scale.Min), total,
+				getDistributionCount(baseQuestionStat.RatingDistribution, rating-scale.Min), baseTotal,
+			))
+		}
+
+		if questionStat.MeanRating != nil && baseQuestionStat.MeanRating != nil {
+			meanRating := newMetricComparison(*questionStat.MeanRating, *baseQuestionStat.MeanRating)
+			comparison.MeanRating = &meanRating
+		}
+	}
+
+	return comparison
+}
+
+func getDistributionCount(distribution []int64, index int) int64 {
+	if index < 0 || index >= len(distribution) {
+		return 0
+	}
+
+	return distribution[index]
+}
+
+// compareTeams compares the NPS of the teams reported in either survey, sorted by team name.
+func (c *SurveyComparison) compareTeams(teamNPS, baseTeamNPS *SurveyTeamNPS) {
+	teams := map[string]*TeamComparison{}
+	baseCounts := map[string]NPSGroupCounts{}
+
+	for _, team := range baseTeamNPS.Teams {
+		baseNPS := team.NPS
+		teams[team.TeamID] = &TeamComparison{TeamID: team.TeamID, TeamName: team.TeamName, BaseNPS: &baseNPS}
+		baseCounts[team.TeamID] = NPSGroupCounts{Promoters: team.Promoters, Passives: team.Passives, Detractors: team.Detractors}
+	}
+
+	for _, team := range teamNPS.Teams {
+		teamComparison, ok := teams[team.TeamID]
+		if !ok {
+			teamComparison = &TeamComparison{TeamID: team.TeamID}
+			teams[team.TeamID] = teamComparison
+		}
+
+		// the survey's team name is the latest, in case the team was renamed since the base survey
+		nps := team.NPS
+		teamComparison.TeamName = team.TeamName
+		teamComparison.NPS = &nps
+
+		if ok {
+			counts := NPSGroupCounts{Promoters: team.Promoters, Passives: team.Passives, Detractors: team.Detractors}
+			// teams are compared on their NPS, which is what they report whatever the scale's score type
+			npsComparison := counts.CompareTo(baseCounts[team.TeamID], DefaultRatingScale)
+			teamComparison.Comparison = &npsComparison
+		}
+	}
+
+	for _, teamComparison := range teams {
+		c.Teams = append(c.Teams, *teamComparison)
+	}
+
+	sort.Slice(c.Teams, func(i, j int) bool {
+		if c.Teams[i].TeamName != c.Teams[j].TeamName {
+			return c.Teams[i].TeamName < c.Teams[j].TeamName
+		}
+
+		return c.Teams[i].TeamID < c.Teams[j].TeamID
+	})
+}
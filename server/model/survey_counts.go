@@ -0,0 +1,90 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strconv"
+)
+
+// SurveyCountsDelta is the change saving a response makes to the survey's counters.
+type SurveyCountsDelta struct {
+	Responses  int
+	Completed  int
+	Promoters  int
+	Passives   int
+	Detractors int
+	Redactions int
+}
+
+func (d SurveyCountsDelta) IsZero() bool {
+	return d == SurveyCountsDelta{}
+}
+
+// SurveyResponseSave is a response to save along with the changes it makes,
+// which the store applies in a single transaction.
+type SurveyResponseSave struct {
+	Response *SurveyResponse
+
+	// Existing is the saved response the new response replaces, nil for new responses.
+	// A partial response is updated, while a complete response is edited,
+	// requiring Revision to keep its previous version.
+	Existing *SurveyResponse
+	Revision *SurveyResponseRevision
+
+	Counts        SurveyCountsDelta
+	AnswerCounts  []QuestionAnswerCountDelta
+	SegmentCounts []SegmentCountsDelta
+}
+
+// SurveyResponseWithdrawal is a response to delete along with the changes deleting it makes,
+// which the store applies in a single transaction.
+type SurveyResponseWithdrawal struct {
+	Response *SurveyResponse
+
+	Counts        SurveyCountsDelta
+	AnswerCounts  []QuestionAnswerCountDelta
+	SegmentCounts []SegmentCountsDelta
+}
+
+// SurveyCounts are the survey's denormalized counters.
+type SurveyCounts struct {
+	Receipts   int64 `json:"receipts"`
+	Opened     int64 `json:"opened"`
+	Responses  int64 `json:"responses"`
+	Completed  int64 `json:"completed"`
+	Promoters  int64 `json:"promoters"`
+	Passives   int64 `json:"passives"`
+	Detractors int64 `json:"detractors"`
+}
+
+// AddResponse counts the response, classifying its rating into its rating group
+// on the rating question's scale. Responses without a valid rating are counted
+// without a rating group, as their rating was never counted when saving them.
+func (c *SurveyCounts) AddResponse(response *SurveyResponse, ratingQuestion Question) {
+	c.Responses++
+	if response.ResponseType == ResponseTypeComplete {
+		c.Completed++
+	}
+
+	rating, err := strconv.Atoi(response.Response[ratingQuestion.ID])
+	if err != nil {
+		return
+	}
+
+	promoterFactor, neutralFactor, detractorFactor := ratingQuestion.GetRatingScale().GetRatingGroupFactors(rating)
+	c.Promoters += int64(promoterFactor)
+	c.Passives += int64(neutralFactor)
+	c.Detractors += int64(detractorFactor)
+}
+
+// SurveyCountsReconciliation is the result of recomputing a survey's counters.
+type SurveyCountsReconciliation struct {
+	SurveyID string       `json:"surveyID"`
+	Stored   SurveyCounts `json:"stored"`
+	Actual   SurveyCounts `json:"actual"`
+}
+
+func (r *SurveyCountsReconciliation) HasDiscrepancies() bool {
+	return r.Stored != r.Actual
+}
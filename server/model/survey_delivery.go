@@ -0,0 +1,22 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+const (
+	SurveyDeliveryStatusDelivered = "delivered"
+	SurveyDeliveryStatusOpened    = "opened"
+)
+
+// SurveyDelivery records a survey being sent to a user.
+type SurveyDelivery struct {
+	UserID   string `json:"userId"`
+	SurveyID string `json:"surveyId"`
+
+	// PostID is the latest survey post sent to the user, which changes when the survey is resent.
+	PostID      string `json:"postId"`
+	DeliveredAt int64  `json:"deliveredAt"`
+
+	// Status is either delivered or, once the user has viewed the survey post, opened.
+	Status string `json:"status"`
+}
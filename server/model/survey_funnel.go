@@ -0,0 +1,21 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+// SurveyFunnel tracks how many users reached each stage of a survey.
+type SurveyFunnel struct {
+	Delivered int64                `json:"delivered"`
+	Opened    int64                `json:"opened"` // users who viewed the survey post
+	Rated     int64                `json:"rated"`
+	Completed int64                `json:"completed"`
+	Questions []QuestionFunnelStep `json:"questions,omitempty"`
+}
+
+type QuestionFunnelStep struct {
+	QuestionID string `json:"questionID"`
+	Answered   int64  `json:"answered"`
+
+	// DropOff is the number of responses that didn't answer the question.
+	DropOff int64 `json:"dropOff"`
+}
@@ -4,6 +4,8 @@
 package model
 
 import (
+	"fmt"
+
 	mmModel "github.com/mattermost/mattermost/server/public/model"
 	"github.com/pkg/errors"
 
@@ -13,15 +15,32 @@ import (
 const (
 	ResponseTypeComplete = "complete"
 	ResponseTypePartial  = "partial"
+
+	MaxIdempotencyKeyLength = 64
 )
 
 type SurveyResponse struct {
 	ID           string            `json:"ID"`
-	UserID       string            `json:"userID"`
+	UserID       string            `json:"userID"` // pseudonym of the user for anonymous surveys
 	SurveyID     string            `json:"surveyID"`
 	Response     map[string]string `json:"response"` // map of question ID to response
 	CreateAt     int64             `json:"createAt"`
+	UpdateAt     int64             `json:"updateAt"`    // last time a complete response was edited
+	CompletedAt  int64             `json:"completedAt"` // first time the response was complete, kept when it's edited
 	ResponseType string            `json:"responseType"`
+	Locale       string            `json:"locale"` // locale of the survey content the user answered
+	Metadata     ResponseMetadata  `json:"metadata"`
+
+	// Sentiment holds the sentiment score of each text answer, by question ID, from -1 to 1.
+	Sentiment map[string]float64 `json:"sentiment,omitempty"`
+
+	// RedactionCount is the number of redactions counted for the response in the survey's
+	// redaction count, which is the most redactions made in any version of the response.
+	RedactionCount int64 `json:"-"`
+
+	// IdempotencyKey is the key of the last request that saved the response,
+	// used to ignore retries of a submission that was already saved.
+	IdempotencyKey string `json:"-"`
 }
 
 func (sr *SurveyResponse) SetDefaults() {
@@ -59,11 +78,39 @@ func (sr *SurveyResponse) IsValid() error {
 		return errors.New("survey response type cannot be empty")
 	}
 
+	if len(sr.IdempotencyKey) > MaxIdempotencyKeyLength {
+		return fmt.Errorf("survey response idempotency key cannot be longer than %d characters", MaxIdempotencyKeyLength)
+	}
+
 	return nil
 }
 
 func (sr *SurveyResponse) ToReportRow(surveyQuestions []Question) []string {
-	row := []string{sr.UserID, utils.FormatUnixTimeMillis(sr.CreateAt)}
+	row := []string{sr.UserID, utils.FormatUnixTimeMillis(sr.CreateAt), sr.Locale}
+	row = append(row, sr.Metadata.ToReportColumns()...)
+	row = append(row, sr.formatSentiment())
+
+	for _, question := range surveyQuestions {
+		answer, ok := sr.Response[question.ID]
+		if ok {
+			row = append(row, answer)
+		}
+	}
+
+	return row
+}
+
+// ToAnonymousReportRow is the report row of a response to an anonymous survey, which leaves out
+// the respondent, the response metadata and when the response was submitted, as the submission
+// time could be matched with the time the user's survey post was updated. The locale is only included
+// if includeLocale is set, as it could identify the respondent when few responses share the locale.
+func (sr *SurveyResponse) ToAnonymousReportRow(surveyQuestions []Question, includeLocale bool) []string {
+	locale := ""
+	if includeLocale {
+		locale = sr.Locale
+	}
+
+	row := []string{locale, sr.formatSentiment()}
 
 	for _, question := range surveyQuestions {
 		answer, ok := sr.Response[question.ID]
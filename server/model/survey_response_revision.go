@@ -0,0 +1,35 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"github.com/mattermost/mattermost-plugin-user-survey/server/utils"
+)
+
+// SurveyResponseRevision is a previous version of a survey response,
+// saved each time a complete response is edited.
+type SurveyResponseRevision struct {
+	ID         string            `json:"id"`
+	ResponseID string            `json:"responseID"`
+	SurveyID   string            `json:"surveyID"`
+	Response   map[string]string `json:"response"`
+	Locale     string            `json:"locale"`
+	CreateAt   int64             `json:"createAt"` // when this version of the response was submitted
+}
+
+func NewSurveyResponseRevision(response *SurveyResponse) *SurveyResponseRevision {
+	createAt := response.UpdateAt
+	if createAt == 0 {
+		createAt = response.CreateAt
+	}
+
+	return &SurveyResponseRevision{
+		ID:         utils.NewID(),
+		ResponseID: response.ID,
+		SurveyID:   response.SurveyID,
+		Response:   response.Response,
+		Locale:     response.Locale,
+		CreateAt:   createAt,
+	}
+}
@@ -15,6 +15,38 @@ type SurveyStat struct {
 	PassiveCount   int64 `json:"passiveCount"`
 	PromoterCount  int64 `json:"promoterCount"`
 	DetractorCount int64 `json:"detractorCount"`
+	OpenedCount    int64 `json:"openedCount"`
+	CompletedCount int64 `json:"completedCount"`
+
+	// RedactionCount is the number of personal details redacted from the survey's text answers.
+	RedactionCount int64 `json:"redactionCount"`
+
+	// QuestionFunnel holds the per-question drop-off, in the order of the survey questions.
+	// It's only populated when fetching the stat of a single survey.
+	QuestionFunnel []QuestionFunnelStep `json:"questionFunnel,omitempty"`
+
+	// QuestionStats holds the aggregates of each question's answers, in the order of the survey questions.
+	// It's only populated when fetching the stat of a single survey.
+	QuestionStats []QuestionStat `json:"questionStats,omitempty"`
+
+	// ResultsWithheld is set for anonymous surveys without enough
+	// responses to report their results, in which case the rating counts are zero.
+	ResultsWithheld bool `json:"resultsWithheld"`
+}
+
+// WithholdUnreportableResults clears the rating counts of anonymous surveys
+// that don't have enough responses yet to report them without identifying the respondents.
+func (stat *SurveyStat) WithholdUnreportableResults() {
+	if stat.Anonymity.IsGroupReportable(stat.ResponseCount) {
+		return
+	}
+
+	stat.PassiveCount = 0
+	stat.PromoterCount = 0
+	stat.DetractorCount = 0
+	stat.QuestionFunnel = nil
+	stat.QuestionStats = nil
+	stat.ResultsWithheld = true
 }
 
 func (stat *SurveyStat) ToMetadata() map[string]interface{} {
@@ -29,5 +61,57 @@ func (stat *SurveyStat) ToMetadata() map[string]interface{} {
 		"promoter_count":  stat.PromoterCount,
 		"detractor_count": stat.DetractorCount,
 		"nps_score":       utils.CalculateNPS(stat.PromoterCount, stat.DetractorCount, stat.PassiveCount),
+		"nps_confidence":  stat.GetNPSGroupCounts().ConfidenceInterval(),
+		"score":           stat.GetScore(),
+		"score_type":      stat.GetRatingScale().GetScoreType(),
+		"anonymous":       stat.Anonymity.Enabled,
+		"funnel":          stat.GetFunnel(),
+		"redaction_count": stat.RedactionCount,
+		"question_stats":  stat.QuestionStats,
+	}
+}
+
+// GetScore returns the survey's score on its rating scale, which is the NPS for the default scale.
+func (stat *SurveyStat) GetScore() float64 {
+	return stat.GetRatingScale().CalculateScore(stat.GetNPSGroupCounts())
+}
+
+// GetResponseRate returns the percentage of the users the survey was delivered to who responded.
+func (stat *SurveyStat) GetResponseRate() float64 {
+	return percentage(stat.ResponseCount, stat.ReceiptCount)
+}
+
+func (stat *SurveyStat) GetNPSGroupCounts() NPSGroupCounts {
+	return NPSGroupCounts{
+		Promoters:  stat.PromoterCount,
+		Passives:   stat.PassiveCount,
+		Detractors: stat.DetractorCount,
+	}
+}
+
+// GetFunnel returns the number of users reaching each stage of the survey,
+// from receiving it to completing it, along with the per-question drop-off.
+// Rated counts all responses, as responses are first saved when the user rates.
+func (stat *SurveyStat) GetFunnel() SurveyFunnel {
+	return SurveyFunnel{
+		Delivered: stat.ReceiptCount,
+		Opened:    stat.OpenedCount,
+		Rated:     stat.ResponseCount,
+		Completed: stat.CompletedCount,
+		Questions: stat.QuestionFunnel,
+	}
+}
+
+// SetQuestionAnswerCounts populates the question funnel from the
+// number of responses that answered each question.
+func (stat *SurveyStat) SetQuestionAnswerCounts(answerCounts map[string]int64) {
+	stat.QuestionFunnel = make([]QuestionFunnelStep, 0, len(stat.SurveyQuestions.Questions))
+	for _, question := range stat.SurveyQuestions.Questions {
+		answered := answerCounts[question.ID]
+		stat.QuestionFunnel = append(stat.QuestionFunnel, QuestionFunnelStep{
+			QuestionID: question.ID,
+			Answered:   answered,
+			DropOff:    max(stat.ResponseCount-answered, 0),
+		})
 	}
 }
@@ -0,0 +1,123 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	TimeSeriesIntervalDay  = "day"
+	TimeSeriesIntervalHour = "hour"
+)
+
+// SurveyActivityCounts are the number of deliveries, ratings and completions of a survey
+// in each time interval, keyed by the interval's start time in milliseconds.
+type SurveyActivityCounts struct {
+	Deliveries  map[int64]int64
+	Ratings     map[int64]int64
+	Completions map[int64]int64
+}
+
+// SurveyTimeSeriesPoint is the survey activity in the interval starting at Time.
+// Ratings count the responses first saved in the interval, as responses
+// are first saved when the user rates.
+type SurveyTimeSeriesPoint struct {
+	Time        int64 `json:"time"`
+	Deliveries  int64 `json:"deliveries"`
+	Ratings     int64 `json:"ratings"`
+	Completions int64 `json:"completions"`
+}
+
+// SurveyTimeSeries is the survey activity per day or hour, in UTC, from the start
+// of the survey until it ended or until now for surveys in progress. For anonymous
+// surveys, the ratings and completions of a day with fewer than the minimum group size
+// of them are counted in the following day instead, so a single response can't be
+// dated. Those of the last days are withheld until enough responses join them.
+type SurveyTimeSeries struct {
+	SurveyID string                  `json:"surveyID"`
+	Interval string                  `json:"interval"`
+	Points   []SurveyTimeSeriesPoint `json:"points"`
+}
+
+// GetTimeSeriesIntervalMillis returns the length of the time series interval in milliseconds.
+func GetTimeSeriesIntervalMillis(interval string) (int64, error) {
+	switch interval {
+	case TimeSeriesIntervalDay:
+		return (24 * time.Hour).Milliseconds(), nil
+	case TimeSeriesIntervalHour:
+		return time.Hour.Milliseconds(), nil
+	default:
+		return 0, errors.New("unknown time series interval: " + interval)
+	}
+}
+
+// NewSurveyTimeSeries builds the time series of the survey's activity until the given time,
+// with a point for every interval so gaps in the activity show as zeroes.
+func NewSurveyTimeSeries(survey *Survey, interval string, counts SurveyActivityCounts, until time.Time) (*SurveyTimeSeries, error) {
+	intervalMillis, err := GetTimeSeriesIntervalMillis(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	endTime := survey.GetEndTime()
+	if survey.Status != SurveyStatusEnded && until.Before(endTime) {
+		endTime = until
+	}
+
+	first := survey.StartTime - survey.StartTime%intervalMillis
+	last := endTime.UnixMilli() - endTime.UnixMilli()%intervalMillis
+
+	// activity outside of the survey's run, such as responses saved
+	// after the survey ended, extends the series so nothing is left out
+	for _, intervalCounts := range []map[int64]int64{counts.Deliveries, counts.Ratings, counts.Completions} {
+		for intervalStart := range intervalCounts {
+			first = min(first, intervalStart)
+			last = max(last, intervalStart)
+		}
+	}
+
+	timeSeries := &SurveyTimeSeries{
+		SurveyID: survey.ID,
+		Interval: interval,
+		Points:   []SurveyTimeSeriesPoint{},
+	}
+
+	for intervalStart := first; intervalStart <= last; intervalStart += intervalMillis {
+		timeSeries.Points = append(timeSeries.Points, SurveyTimeSeriesPoint{
+			Time:        intervalStart,
+			Deliveries:  counts.Deliveries[intervalStart],
+			Ratings:     counts.Ratings[intervalStart],
+			Completions: counts.Completions[intervalStart],
+		})
+	}
+
+	if survey.Anonymity.Enabled {
+		timeSeries.mergeSmallIntervals(int64(survey.Anonymity.GetMinGroupSize()))
+	}
+
+	return timeSeries, nil
+}
+
+// mergeSmallIntervals carries the ratings and completions of the intervals with fewer
+// than minGroupSize of them over to the following intervals until there are enough.
+func (ts *SurveyTimeSeries) mergeSmallIntervals(minGroupSize int64) {
+	var ratings, completions int64
+	for i := range ts.Points {
+		point := &ts.Points[i]
+		ratings += point.Ratings
+		completions += point.Completions
+		point.Ratings, point.Completions = 0, 0
+
+		if ratings >= minGroupSize {
+			point.Ratings, ratings = ratings, 0
+		}
+
+		if completions >= minGroupSize {
+			point.Completions, completions = completions, 0
+		}
+	}
+}
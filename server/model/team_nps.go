@@ -0,0 +1,93 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"sort"
+	"strconv"
+)
+
+// TeamNPS is the NPS of the responses from the members of a team.
+type TeamNPS struct {
+	TeamID   string `json:"teamID"`
+	TeamName string `json:"teamName"`
+
+	// Respondents is the number of responses with a rating from the team's members.
+	Respondents int64   `json:"respondents"`
+	Promoters   int64   `json:"promoters"`
+	Passives    int64   `json:"passives"`
+	Detractors  int64   `json:"detractors"`
+	NPS         float64 `json:"nps"`
+
+	NPSConfidence NPSConfidenceInterval `json:"npsConfidence"`
+}
+
+// SurveyTeamNPS is a survey's NPS segmented by the teams the respondents were members
+// of when answering. A response counts towards each of its respondent's teams.
+// Teams with fewer respondents than MinRespondents are left out so their members
+// can't be identified, and only counted in WithheldTeams.
+type SurveyTeamNPS struct {
+	SurveyID       string    `json:"surveyID"`
+	MinRespondents int       `json:"minRespondents"`
+	Teams          []TeamNPS `json:"teams"`
+	WithheldTeams  int       `json:"withheldTeams"`
+}
+
+// NewSurveyTeamNPS computes the NPS of the teams with at least minRespondents respondents
+// from the survey's segment counts, sorted by team name.
+func NewSurveyTeamNPS(surveyID string, minRespondents int, segmentCounts SurveySegmentCounts) *SurveyTeamNPS {
+	surveyTeamNPS := &SurveyTeamNPS{
+		SurveyID:       surveyID,
+		MinRespondents: minRespondents,
+		Teams:          []TeamNPS{},
+	}
+
+	for key, counts := range segmentCounts {
+		respondents := counts.GetRespondents()
+		if key.Type != SegmentTypeTeam || respondents == 0 {
+			continue
+		}
+
+		if respondents < int64(minRespondents) {
+			surveyTeamNPS.WithheldTeams++
+			continue
+		}
+
+		groupCounts := NPSGroupCounts{Promoters: counts.Promoters, Passives: counts.Passives, Detractors: counts.Detractors}
+		surveyTeamNPS.Teams = append(surveyTeamNPS.Teams, TeamNPS{
+			TeamID:        key.ID,
+			TeamName:      counts.TeamName,
+			Respondents:   respondents,
+			Promoters:     counts.Promoters,
+			Passives:      counts.Passives,
+			Detractors:    counts.Detractors,
+			NPS:           groupCounts.NPS(),
+			NPSConfidence: groupCounts.ConfidenceInterval(),
+		})
+	}
+
+	sort.Slice(surveyTeamNPS.Teams, func(i, j int) bool {
+		if surveyTeamNPS.Teams[i].TeamName != surveyTeamNPS.Teams[j].TeamName {
+			return surveyTeamNPS.Teams[i].TeamName < surveyTeamNPS.Teams[j].TeamName
+		}
+
+		return surveyTeamNPS.Teams[i].TeamID < surveyTeamNPS.Teams[j].TeamID
+	})
+
+	return surveyTeamNPS
+}
+
+// ToReportRow returns the team's NPS as a row of the team NPS report.
+func (t TeamNPS) ToReportRow() []string {
+	return []string{
+		t.TeamID,
+		t.TeamName,
+		strconv.FormatInt(t.Respondents, 10),
+		strconv.FormatInt(t.Promoters, 10),
+		strconv.FormatInt(t.Passives, 10),
+		strconv.FormatInt(t.Detractors, 10),
+		strconv.FormatFloat(t.NPS, 'f', 2, 64),
+		strconv.FormatFloat(t.NPSConfidence.MarginOfError, 'f', 2, 64),
+	}
+}
@@ -0,0 +1,364 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// TextAnalyticsTopTermCount is the number of keywords and phrases kept for each question and rating group.
+	TextAnalyticsTopTermCount = 20
+
+	// TextAnalyticsMinTermAnswers is the number of answers a term must appear in to be kept,
+	// so that words from a single answer, such as names, don't show in the results.
+	TextAnalyticsMinTermAnswers = 2
+
+	TextTermTypeKeyword = "keyword"
+	TextTermTypePhrase  = "phrase"
+
+	textAnalyticsAllGroups = "all"
+
+	// phrases are the bigrams and trigrams of the answers
+	minPhraseLength = 2
+	maxPhraseLength = 3
+)
+
+var redactionPlaceholderPattern = regexp.MustCompile(`\[redacted [^\]]*\]`)
+
+// TermCount is how many answers a keyword or phrase appears in, and how many times it appears in total.
+type TermCount struct {
+	Term        string `json:"term"`
+	Answers     int64  `json:"answers"`
+	Occurrences int64  `json:"occurrences"`
+}
+
+// RatingGroupTextAnalytics are the top keywords of the answers of the respondents in a rating group.
+// The keywords are withheld if the group has too few answers to keep its respondents anonymous.
+type RatingGroupTextAnalytics struct {
+	Group       string      `json:"group"`
+	AnswerCount int64       `json:"answerCount"`
+	Withheld    bool        `json:"withheld"`
+	Keywords    []TermCount `json:"keywords"`
+}
+
+// QuestionTextAnalytics are the top keywords and phrases of the answers to a text question,
+// overall and by the rating group of the respondents.
+type QuestionTextAnalytics struct {
+	QuestionID   string                     `json:"questionID"`
+	QuestionText string                     `json:"questionText"`
+	AnswerCount  int64                      `json:"answerCount"`
+	Withheld     bool                       `json:"withheld"`
+	Keywords     []TermCount                `json:"keywords"`
+	Phrases      []TermCount                `json:"phrases"`
+	RatingGroups []RatingGroupTextAnalytics `json:"ratingGroups"`
+}
+
+// SurveyTextAnalytics are the keywords and themes extracted from the text answers of an ended survey.
+type SurveyTextAnalytics struct {
+	SurveyID  string                  `json:"surveyID"`
+	CreateAt  int64                   `json:"createAt"`
+	Questions []QuestionTextAnalytics `json:"questions"`
+}
+
+type textTermCounts map[string]*TermCount
+
+type questionTextCounts struct {
+	answerCount       int64
+	keywords          textTermCounts
+	phrases           textTermCounts
+	groupAnswerCounts map[string]int64
+	groupKeywords     map[string]textTermCounts
+}
+
+// TextAnalyzer extracts the keywords and phrases of the text answers of a survey's responses.
+type TextAnalyzer struct {
+	survey         *Survey
+	ratingQuestion *Question
+	questions      map[string]*questionTextCounts
+}
+
+func NewTextAnalyzer(survey *Survey) *TextAnalyzer {
+	analyzer := &TextAnalyzer{
+		survey:    survey,
+		questions: map[string]*questionTextCounts{},
+	}
+
+	if ratingQuestion, err := survey.GetSystemRatingQuestion(); err == nil {
+		analyzer.ratingQuestion = &ratingQuestion
+	}
+
+	for _, question := range survey.SurveyQuestions.Questions {
+		if question.Type != QuestionType {
+			continue
+		}
+
+		analyzer.questions[question.ID] = &questionTextCounts{
+			keywords:          textTermCounts{},
+			phrases:           textTermCounts{},
+			groupAnswerCounts: map[string]int64{},
+			groupKeywords:     map[string]textTermCounts{},
+		}
+	}
+
+	return analyzer
+}
+
+// AddResponse counts the keywords and phrases of the response's text answers, overall
+// and towards the rating group of the response's rating. The answers must be decrypted.
+func (a *TextAnalyzer) AddResponse(response *SurveyResponse) {
+	ratingGroup := ""
+	if a.ratingQuestion != nil {
+		if rating, err := strconv.Atoi(response.Response[a.ratingQuestion.ID]); err == nil {
+			ratingGroup = a.ratingQuestion.GetRatingScale().GetRatingGroup(rating)
+		}
+	}
+
+	for questionID, counts := range a.questions {
+		answer := strings.TrimSpace(response.Response[questionID])
+		if answer == "" {
+			continue
+		}
+
+		keywords, phrases := ExtractTextTerms(answer)
+
+		counts.answerCount++
+		counts.keywords.add(keywords)
+		counts.phrases.add(phrases)
+
+		if ratingGroup == "" {
+			continue
+		}
+
+		if _, ok := counts.groupKeywords[ratingGroup]; !ok {
+			counts.groupKeywords[ratingGroup] = textTermCounts{}
+		}
+
+		counts.groupAnswerCounts[ratingGroup]++
+		counts.groupKeywords[ratingGroup].add(keywords)
+	}
+}
+
+// ToSurveyTextAnalytics returns the top keywords and phrases of each text question, in the order
+// of the survey's questions. For anonymous surveys, the results of questions and rating groups with
+// fewer answers than the minimum group size are withheld.
+func (a *TextAnalyzer) ToSurveyTextAnalytics(createAt int64) *SurveyTextAnalytics {
+	analytics := &SurveyTextAnalytics{
+		SurveyID:  a.survey.ID,
+		CreateAt:  createAt,
+		Questions: []QuestionTextAnalytics{},
+	}
+
+	anonymity := a.survey.Anonymity
+
+	for _, question := range a.survey.SurveyQuestions.Questions {
+		counts, ok := a.questions[question.ID]
+		if !ok {
+			continue
+		}
+
+		questionAnalytics := QuestionTextAnalytics{
+			QuestionID:   question.ID,
+			QuestionText: question.Text,
+			AnswerCount:  counts.answerCount,
+			Keywords:     []TermCount{},
+			Phrases:      []TermCount{},
+			RatingGroups: []RatingGroupTextAnalytics{},
+		}
+
+		if anonymity.Enabled && !anonymity.IsGroupReportable(counts.answerCount) {
+			questionAnalytics.Withheld = true
+			analytics.Questions = append(analytics.Questions, questionAnalytics)
+			continue
+		}
+
+		questionAnalytics.Keywords = counts.keywords.top(TextAnalyticsTopTermCount)
+		questionAnalytics.Phrases = counts.phrases.top(TextAnalyticsTopTermCount)
+
+		// the rating groups are only known if the survey has a rating question
+		if a.ratingQuestion != nil {
+			for _, group := range RatingGroups {
+				groupAnalytics := RatingGroupTextAnalytics{
+					Group:       group,
+					AnswerCount: counts.groupAnswerCounts[group],
+					Keywords:    []TermCount{},
+				}
+
+				if anonymity.Enabled && !anonymity.IsGroupReportable(groupAnalytics.AnswerCount) {
+					groupAnalytics.Withheld = true
+				} else {
+					groupAnalytics.Keywords = counts.groupKeywords[group].top(TextAnalyticsTopTermCount)
+				}
+
+				questionAnalytics.RatingGroups = append(questionAnalytics.RatingGroups, groupAnalytics)
+			}
+		}
+
+		analytics.Questions = append(analytics.Questions, questionAnalytics)
+	}
+
+	return analytics
+}
+
+// ToReportRows returns the keywords and phrases of the question as rows of the text analytics report.
+// Withheld results have no rows.
+func (q QuestionTextAnalytics) ToReportRows() [][]string {
+	rows := [][]string{}
+
+	addRows := func(group, termType string, terms []TermCount) {
+		for _, term := range terms {
+			rows = append(rows, []string{
+				q.QuestionID,
+				q.QuestionText,
+				group,
+				termType,
+				term.Term,
+				strconv.FormatInt(term.Answers, 10),
+				strconv.FormatInt(term.Occurrences, 10),
+			})
+		}
+	}
+
+	addRows(textAnalyticsAllGroups, TextTermTypeKeyword, q.Keywords)
+	addRows(textAnalyticsAllGroups, TextTermTypePhrase, q.Phrases)
+
+	for _, group := range q.RatingGroups {
+		addRows(group.Group, TextTermTypeKeyword, group.Keywords)
+	}
+
+	return rows
+}
+
+// ExtractTextTerms returns the keywords of the text, which are its words other than stop words,
+// and its phrases, which are the runs of two or three keywords not broken by punctuation or stop words.
+// Redaction placeholders are left out.
+func ExtractTextTerms(text string) (keywords, phrases []string) {
+	for _, run := range tokenize(text) {
+		keywords = append(keywords, run...)
+
+		for length := minPhraseLength; length <= maxPhraseLength; length++ {
+			for start := 0; start+length <= len(run); start++ {
+				phrases = append(phrases, strings.Join(run[start:start+length], " "))
+			}
+		}
+	}
+
+	return keywords, phrases
+}
+
+// tokenize splits the text into runs of consecutive keywords. Runs are broken at
+// punctuation and stop words so phrases don't span clauses.
+func tokenize(text string) [][]string {
+	runs := [][]string{}
+	for _, clause := range splitClauses(text) {
+		run := []string{}
+
+		for _, word := range clause {
+			if !isKeyword(word) {
+				if len(run) > 0 {
+					runs = append(runs, run)
+				}
+
+				run = []string{}
+				continue
+			}
+
+			run = append(run, word)
+		}
+
+		if len(run) > 0 {
+			runs = append(runs, run)
+		}
+	}
+
+	return runs
+}
+
+// splitClauses splits the text into clauses at punctuation, and the clauses into lowercase words.
+// Redaction placeholders are left out.
+func splitClauses(text string) [][]string {
+	text = redactionPlaceholderPattern.ReplaceAllString(strings.ToLower(text), ".")
+
+	// apostrophes and hyphens are kept within words, such as in "don't" and "sign-in"
+	clauseTexts := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r) && r != '\'' && r != '’' && r != '-'
+	})
+
+	clauses := make([][]string, 0, len(clauseTexts))
+	for _, clauseText := range clauseTexts {
+		clause := []string{}
+
+		for _, word := range strings.Fields(clauseText) {
+			if word = strings.Trim(strings.ReplaceAll(word, "’", "'"), "'-"); word != "" {
+				clause = append(clause, word)
+			}
+		}
+
+		clauses = append(clauses, clause)
+	}
+
+	return clauses
+}
+
+func isKeyword(word string) bool {
+	if utf8.RuneCountInString(word) < 2 || stopWords[word] {
+		return false
+	}
+
+	// numbers, such as ratings mentioned in the answer, aren't keywords
+	return strings.IndexFunc(word, func(r rune) bool { return !unicode.IsDigit(r) }) != -1
+}
+
+// add counts the terms of an answer.
+func (c textTermCounts) add(terms []string) {
+	seen := map[string]bool{}
+
+	for _, term := range terms {
+		termCount, ok := c[term]
+		if !ok {
+			termCount = &TermCount{Term: term}
+			c[term] = termCount
+		}
+
+		termCount.Occurrences++
+		if !seen[term] {
+			termCount.Answers++
+			seen[term] = true
+		}
+	}
+}
+
+// top returns the n terms appearing in the most answers, leaving out
+// the terms appearing in fewer than TextAnalyticsMinTermAnswers answers.
+func (c textTermCounts) top(n int) []TermCount {
+	terms := []TermCount{}
+	for _, termCount := range c {
+		if termCount.Answers >= TextAnalyticsMinTermAnswers {
+			terms = append(terms, *termCount)
+		}
+	}
+
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].Answers != terms[j].Answers {
+			return terms[i].Answers > terms[j].Answers
+		}
+
+		if terms[i].Occurrences != terms[j].Occurrences {
+			return terms[i].Occurrences > terms[j].Occurrences
+		}
+
+		return terms[i].Term < terms[j].Term
+	})
+
+	if len(terms) > n {
+		terms = terms[:n]
+	}
+
+	return terms
+}
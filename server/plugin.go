@@ -69,14 +69,34 @@ func (p *Plugin) OnActivate() error {
 	p.app = app
 	p.apiHandlers = api
 
+	if err := p.startBackfillJob(); err != nil {
+		return err
+	}
+
 	if err := p.startManageSurveyJob(); err != nil {
 		return err
 	}
 
+	if err := p.startReconcileCountsJob(); err != nil {
+		return err
+	}
+
+	if err := p.startReencryptJob(); err != nil {
+		return err
+	}
+
+	if err := p.startTextAnalyticsJob(); err != nil {
+		return err
+	}
+
 	if err := p.clearStaleLocks(); err != nil {
 		return err
 	}
 
+	if err := p.registerCommands(); err != nil {
+		return err
+	}
+
 	if DebugBuild == "true" {
 		if err := p.registerDebugCommands(); err != nil {
 			return err
@@ -26,6 +26,42 @@ func (s *SQLStore) ResetData() error {
 		return errors.Wrap(err, "SQLStore.resetData: failed to reset data from survey_responses table")
 	}
 
+	_, err = s.getQueryBuilder().
+		Delete(s.tablePrefix + "survey_response_revisions").
+		Exec()
+
+	if err != nil {
+		s.pluginAPI.LogError("SQLStore.resetData: failed to reset data from survey_response_revisions table", "error", err.Error())
+		return errors.Wrap(err, "SQLStore.resetData: failed to reset data from survey_response_revisions table")
+	}
+
+	_, err = s.getQueryBuilder().
+		Delete(s.tablePrefix + "survey_deliveries").
+		Exec()
+
+	if err != nil {
+		s.pluginAPI.LogError("SQLStore.resetData: failed to reset data from survey_deliveries table", "error", err.Error())
+		return errors.Wrap(err, "SQLStore.resetData: failed to reset data from survey_deliveries table")
+	}
+
+	_, err = s.getQueryBuilder().
+		Delete(s.tablePrefix + "survey_text_analytics").
+		Exec()
+
+	if err != nil {
+		s.pluginAPI.LogError("SQLStore.resetData: failed to reset data from survey_text_analytics table", "error", err.Error())
+		return errors.Wrap(err, "SQLStore.resetData: failed to reset data from survey_text_analytics table")
+	}
+
+	_, err = s.getQueryBuilder().
+		Delete(s.tablePrefix + "survey_question_answer_counts").
+		Exec()
+
+	if err != nil {
+		s.pluginAPI.LogError("SQLStore.resetData: failed to reset data from survey_question_answer_counts table", "error", err.Error())
+		return errors.Wrap(err, "SQLStore.resetData: failed to reset data from survey_question_answer_counts table")
+	}
+
 	appErr := s.pluginAPI.KVDeleteAll()
 	if appErr != nil {
 		s.pluginAPI.LogError("SQLStore.resetData: failed to delete all KV store entries", "error", appErr.Error())
@@ -0,0 +1,54 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"database/sql"
+	"strings"
+
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// IsEncryptionKeyInUse returns whether any survey response or response revision
+// has answers encrypted with the key, which are lost if the key is removed.
+// Only answers starting with the key's prefix are matched, as plaintext answers
+// can't start with it.
+func (s *SQLStore) IsEncryptionKeyInUse(keyID string) (bool, error) {
+	pattern := likeEscaper.Replace(model.EncryptedAnswerKeyPrefix(keyID)) + "%"
+
+	// the answers are matched, not the question IDs
+	encryptedAnswer := sq.Expr("EXISTS (SELECT 1 FROM jsonb_each_text(response) AS answers WHERE answers.value LIKE ?)", pattern)
+	if s.dbType == model.DBTypeMySQL {
+		encryptedAnswer = sq.Expr("JSON_SEARCH(response, 'one', ?) IS NOT NULL", pattern)
+	}
+
+	for _, table := range []string{"survey_responses", "survey_response_revisions"} {
+		var id string
+		err := s.getQueryBuilder().
+			Select("id").
+			From(s.tablePrefix + table).
+			Where(encryptedAnswer).
+			Limit(1).
+			QueryRow().
+			Scan(&id)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+
+		if err != nil {
+			s.pluginAPI.LogError("IsEncryptionKeyInUse: failed to query answers encrypted with key", "table", table, "keyID", keyID, "error", err.Error())
+			return false, errors.Wrap(err, "IsEncryptionKeyInUse: failed to query answers encrypted with key")
+		}
+
+		return true, nil
+	}
+
+	return false, nil
+}
@@ -0,0 +1 @@
+{{ dropColumnIfNeeded "survey" "customization" }}
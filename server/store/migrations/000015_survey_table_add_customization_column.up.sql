@@ -0,0 +1,2 @@
+{{if .postgres}}{{ addColumnIfNeeded "survey" "customization" "jsonb" "DEFAULT '{}'::jsonb" }}{{end}}
+{{if .mysql}}{{ addColumnIfNeeded "survey" "customization" "json" "DEFAULT ('{}')" }}{{end}}
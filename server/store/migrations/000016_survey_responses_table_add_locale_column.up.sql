@@ -0,0 +1 @@
+{{ addColumnIfNeeded "survey_responses" "locale" "varchar(32)" "NOT NULL DEFAULT ''" }}
@@ -0,0 +1,2 @@
+{{if .postgres}}{{ addColumnIfNeeded "survey" "anonymity" "jsonb" "DEFAULT '{}'::jsonb" }}{{end}}
+{{if .mysql}}{{ addColumnIfNeeded "survey" "anonymity" "json" "DEFAULT ('{}')" }}{{end}}
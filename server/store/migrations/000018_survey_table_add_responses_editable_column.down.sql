@@ -0,0 +1 @@
+{{ dropColumnIfNeeded "survey" "responses_editable" }}
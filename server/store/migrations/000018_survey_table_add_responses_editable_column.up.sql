@@ -0,0 +1 @@
+{{ addColumnIfNeeded "survey" "responses_editable" "BOOLEAN" "NOT NULL DEFAULT false" }}
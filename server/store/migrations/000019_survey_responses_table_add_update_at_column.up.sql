@@ -0,0 +1 @@
+{{ addColumnIfNeeded "survey_responses" "update_at" "BIGINT" "NOT NULL DEFAULT 0" }}
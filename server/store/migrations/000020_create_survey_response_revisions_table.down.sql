@@ -0,0 +1 @@
+DROP TABLE IF EXISTS {{.prefix}}survey_response_revisions;
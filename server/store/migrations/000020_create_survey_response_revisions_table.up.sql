@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}survey_response_revisions (
+    id VARCHAR(26) NOT NULL,
+    response_id VARCHAR(26) NOT NULL,
+    survey_id VARCHAR(26) NOT NULL,
+    {{if .postgres}}response jsonb DEFAULT '{}'::jsonb,{{end}}
+    {{if .mysql}}response json DEFAULT ('{}'),{{end}}
+    locale VARCHAR(32) NOT NULL DEFAULT '',
+    create_at BIGINT NOT NULL,
+    PRIMARY KEY (id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{ createIndexIfNeeded "survey_response_revisions" "response_id" }}
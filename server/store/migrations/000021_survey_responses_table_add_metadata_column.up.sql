@@ -0,0 +1,2 @@
+{{if .postgres}}{{ addColumnIfNeeded "survey_responses" "metadata" "jsonb" "DEFAULT '{}'::jsonb" }}{{end}}
+{{if .mysql}}{{ addColumnIfNeeded "survey_responses" "metadata" "json" "DEFAULT ('{}')" }}{{end}}
@@ -0,0 +1,2 @@
+{{ dropColumnIfNeeded "survey" "opened_count" }}
+{{ dropColumnIfNeeded "survey" "completed_count" }}
@@ -0,0 +1,8 @@
+{{ addColumnIfNeeded "survey" "opened_count" "bigint" "DEFAULT 0" }}
+{{ addColumnIfNeeded "survey" "completed_count" "bigint" "DEFAULT 0" }}
+
+UPDATE {{.prefix}}survey SET completed_count = (
+    SELECT COUNT(*) FROM {{.prefix}}survey_responses
+    WHERE {{.prefix}}survey_responses.survey_id = {{.prefix}}survey.id
+    AND {{.prefix}}survey_responses.response_type = 'complete'
+);
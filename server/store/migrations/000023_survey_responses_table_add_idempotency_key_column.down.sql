@@ -0,0 +1 @@
+{{ dropColumnIfNeeded "survey_responses" "idempotency_key" }}
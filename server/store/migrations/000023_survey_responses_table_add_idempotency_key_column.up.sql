@@ -0,0 +1 @@
+{{ addColumnIfNeeded "survey_responses" "idempotency_key" "VARCHAR(64)" "NOT NULL DEFAULT ''" }}
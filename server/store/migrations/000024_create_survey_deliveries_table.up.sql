@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}survey_deliveries (
+    user_id VARCHAR(26) NOT NULL,
+    survey_id VARCHAR(26) NOT NULL,
+    post_id VARCHAR(26) NOT NULL,
+    delivered_at BIGINT NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    PRIMARY KEY (user_id, survey_id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{ createIndexIfNeeded "survey_deliveries" "survey_id" }}
@@ -0,0 +1 @@
+{{ dropColumnIfNeeded "survey" "redaction_count" }}
@@ -0,0 +1 @@
+{{ addColumnIfNeeded "survey" "redaction_count" "bigint" "DEFAULT 0" }}
@@ -0,0 +1,13 @@
+{{ addColumnIfNeeded "survey_responses" "completed_at" "BIGINT" "NOT NULL DEFAULT 0" }}
+
+{{if .postgres}}
+UPDATE {{.prefix}}survey_responses
+SET completed_at = COALESCE((metadata ->> 'completedAt')::bigint, create_at)
+WHERE response_type = 'complete' AND completed_at = 0;
+{{end}}
+
+{{if .mysql}}
+UPDATE {{.prefix}}survey_responses
+SET completed_at = COALESCE(JSON_EXTRACT(metadata, '$.completedAt'), create_at)
+WHERE response_type = 'complete' AND completed_at = 0;
+{{end}}
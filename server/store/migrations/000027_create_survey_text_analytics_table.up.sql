@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}survey_text_analytics (
+    survey_id VARCHAR(26) NOT NULL,
+    create_at BIGINT NOT NULL,
+    {{if .postgres}}analytics jsonb NOT NULL,{{end}}
+    {{if .mysql}}analytics json NOT NULL,{{end}}
+    PRIMARY KEY (survey_id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
@@ -0,0 +1 @@
+{{ dropColumnIfNeeded "survey_responses" "sentiment" }}
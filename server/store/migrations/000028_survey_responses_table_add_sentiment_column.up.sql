@@ -0,0 +1,2 @@
+{{if .postgres}}{{ addColumnIfNeeded "survey_responses" "sentiment" "jsonb" "DEFAULT '{}'::jsonb" }}{{end}}
+{{if .mysql}}{{ addColumnIfNeeded "survey_responses" "sentiment" "json" "DEFAULT ('{}')" }}{{end}}
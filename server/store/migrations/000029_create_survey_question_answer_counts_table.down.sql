@@ -0,0 +1 @@
+DROP TABLE IF EXISTS {{.prefix}}survey_question_answer_counts;
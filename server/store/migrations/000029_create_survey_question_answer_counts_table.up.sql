@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}survey_question_answer_counts (
+    survey_id VARCHAR(26) NOT NULL,
+    question_id VARCHAR(100) NOT NULL,
+    answer VARCHAR(255) NOT NULL,
+    response_count BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (survey_id, question_id, answer)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
@@ -0,0 +1 @@
+{{ addColumnIfNeeded "survey_responses" "redaction_count" "bigint" "DEFAULT 0" }}
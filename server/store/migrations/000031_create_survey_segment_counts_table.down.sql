@@ -0,0 +1 @@
+DROP TABLE IF EXISTS {{.prefix}}survey_segment_counts;
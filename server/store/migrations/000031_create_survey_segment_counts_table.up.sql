@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}survey_segment_counts (
+    survey_id VARCHAR(26) NOT NULL,
+    segment_type VARCHAR(20) NOT NULL,
+    segment_id VARCHAR(26) NOT NULL,
+    team_name VARCHAR(64) NOT NULL DEFAULT '',
+    promoters BIGINT NOT NULL DEFAULT 0,
+    passives BIGINT NOT NULL DEFAULT 0,
+    detractors BIGINT NOT NULL DEFAULT 0,
+    sentiment_respondents BIGINT NOT NULL DEFAULT 0,
+    sentiment_answers BIGINT NOT NULL DEFAULT 0,
+    sentiment_total BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (survey_id, segment_type, segment_id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
@@ -18,6 +18,54 @@ type Store struct {
 	mock.Mock
 }
 
+// BackfillSurveyDeliveries provides a mock function with given fields: deliveries
+func (_m *Store) BackfillSurveyDeliveries(deliveries []*model.SurveyDelivery) error {
+	ret := _m.Called(deliveries)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BackfillSurveyDeliveries")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]*model.SurveyDelivery) error); ok {
+		r0 = rf(deliveries)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAllResponseRevisions provides a mock function with given fields: surveyID, lastRevisionID, perPage
+func (_m *Store) GetAllResponseRevisions(surveyID string, lastRevisionID string, perPage uint64) ([]*model.SurveyResponseRevision, error) {
+	ret := _m.Called(surveyID, lastRevisionID, perPage)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllResponseRevisions")
+	}
+
+	var r0 []*model.SurveyResponseRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, uint64) ([]*model.SurveyResponseRevision, error)); ok {
+		return rf(surveyID, lastRevisionID, perPage)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, uint64) []*model.SurveyResponseRevision); ok {
+		r0 = rf(surveyID, lastRevisionID, perPage)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.SurveyResponseRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, uint64) error); ok {
+		r1 = rf(surveyID, lastRevisionID, perPage)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAllResponses provides a mock function with given fields: surveyID, lastResponseID, perPage
 func (_m *Store) GetAllResponses(surveyID string, lastResponseID string, perPage uint64) ([]*model.SurveyResponse, error) {
 	ret := _m.Called(surveyID, lastResponseID, perPage)
@@ -48,6 +96,96 @@ func (_m *Store) GetAllResponses(surveyID string, lastResponseID string, perPage
 	return r0, r1
 }
 
+// GetAllSurveys provides a mock function with given fields: lastSurveyID, perPage
+func (_m *Store) GetAllSurveys(lastSurveyID string, perPage uint64) ([]*model.Survey, error) {
+	ret := _m.Called(lastSurveyID, perPage)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllSurveys")
+	}
+
+	var r0 []*model.Survey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, uint64) ([]*model.Survey, error)); ok {
+		return rf(lastSurveyID, perPage)
+	}
+	if rf, ok := ret.Get(0).(func(string, uint64) []*model.Survey); ok {
+		r0 = rf(lastSurveyID, perPage)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Survey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, uint64) error); ok {
+		r1 = rf(lastSurveyID, perPage)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEndedSurveyStats provides a mock function with given fields: questionID, offset, limit
+func (_m *Store) GetEndedSurveyStats(questionID string, offset uint64, limit uint64) ([]*model.SurveyStat, error) {
+	ret := _m.Called(questionID, offset, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEndedSurveyStats")
+	}
+
+	var r0 []*model.SurveyStat
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, uint64, uint64) ([]*model.SurveyStat, error)); ok {
+		return rf(questionID, offset, limit)
+	}
+	if rf, ok := ret.Get(0).(func(string, uint64, uint64) []*model.SurveyStat); ok {
+		r0 = rf(questionID, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.SurveyStat)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, uint64, uint64) error); ok {
+		r1 = rf(questionID, offset, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEndedSurveysWithoutTextAnalytics provides a mock function with given fields:
+func (_m *Store) GetEndedSurveysWithoutTextAnalytics() ([]*model.Survey, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEndedSurveysWithoutTextAnalytics")
+	}
+
+	var r0 []*model.Survey
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*model.Survey, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*model.Survey); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Survey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLatestEndedSurvey provides a mock function with given fields:
 func (_m *Store) GetLatestEndedSurvey() (*model.Survey, error) {
 	ret := _m.Called()
@@ -78,6 +216,96 @@ func (_m *Store) GetLatestEndedSurvey() (*model.Survey, error) {
 	return r0, r1
 }
 
+// GetQuestionAnswerCounts provides a mock function with given fields: surveyID, questionIDs
+func (_m *Store) GetQuestionAnswerCounts(surveyID string, questionIDs []string) (map[string]int64, error) {
+	ret := _m.Called(surveyID, questionIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetQuestionAnswerCounts")
+	}
+
+	var r0 map[string]int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, []string) (map[string]int64, error)); ok {
+		return rf(surveyID, questionIDs)
+	}
+	if rf, ok := ret.Get(0).(func(string, []string) map[string]int64); ok {
+		r0 = rf(surveyID, questionIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, []string) error); ok {
+		r1 = rf(surveyID, questionIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetQuestionAnswerValueCounts provides a mock function with given fields: surveyID, questionID
+func (_m *Store) GetQuestionAnswerValueCounts(surveyID string, questionID string) (map[string]int64, error) {
+	ret := _m.Called(surveyID, questionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetQuestionAnswerValueCounts")
+	}
+
+	var r0 map[string]int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (map[string]int64, error)); ok {
+		return rf(surveyID, questionID)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) map[string]int64); ok {
+		r0 = rf(surveyID, questionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(surveyID, questionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetResponseCountByLocale provides a mock function with given fields: surveyID
+func (_m *Store) GetResponseCountByLocale(surveyID string) (map[string]int64, error) {
+	ret := _m.Called(surveyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetResponseCountByLocale")
+	}
+
+	var r0 map[string]int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (map[string]int64, error)); ok {
+		return rf(surveyID)
+	}
+	if rf, ok := ret.Get(0).(func(string) map[string]int64); ok {
+		r0 = rf(surveyID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(surveyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetSchemaName provides a mock function with given fields:
 func (_m *Store) GetSchemaName() (string, error) {
 	ret := _m.Called()
@@ -106,6 +334,66 @@ func (_m *Store) GetSchemaName() (string, error) {
 	return r0, r1
 }
 
+// GetSurveyActivityCounts provides a mock function with given fields: surveyID, intervalMillis
+func (_m *Store) GetSurveyActivityCounts(surveyID string, intervalMillis int64) (*model.SurveyActivityCounts, error) {
+	ret := _m.Called(surveyID, intervalMillis)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSurveyActivityCounts")
+	}
+
+	var r0 *model.SurveyActivityCounts
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int64) (*model.SurveyActivityCounts, error)); ok {
+		return rf(surveyID, intervalMillis)
+	}
+	if rf, ok := ret.Get(0).(func(string, int64) *model.SurveyActivityCounts); ok {
+		r0 = rf(surveyID, intervalMillis)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SurveyActivityCounts)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int64) error); ok {
+		r1 = rf(surveyID, intervalMillis)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSurveyDelivery provides a mock function with given fields: userID, surveyID
+func (_m *Store) GetSurveyDelivery(userID string, surveyID string) (*model.SurveyDelivery, error) {
+	ret := _m.Called(userID, surveyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSurveyDelivery")
+	}
+
+	var r0 *model.SurveyDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*model.SurveyDelivery, error)); ok {
+		return rf(userID, surveyID)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *model.SurveyDelivery); ok {
+		r0 = rf(userID, surveyID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SurveyDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(userID, surveyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetSurveyResponse provides a mock function with given fields: userID, surveyID
 func (_m *Store) GetSurveyResponse(userID string, surveyID string) (*model.SurveyResponse, error) {
 	ret := _m.Called(userID, surveyID)
@@ -136,6 +424,64 @@ func (_m *Store) GetSurveyResponse(userID string, surveyID string) (*model.Surve
 	return r0, r1
 }
 
+// GetSurveyResponseRevisions provides a mock function with given fields: surveyID, responseID
+func (_m *Store) GetSurveyResponseRevisions(surveyID string, responseID string) ([]*model.SurveyResponseRevision, error) {
+	ret := _m.Called(surveyID, responseID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSurveyResponseRevisions")
+	}
+
+	var r0 []*model.SurveyResponseRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) ([]*model.SurveyResponseRevision, error)); ok {
+		return rf(surveyID, responseID)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) []*model.SurveyResponseRevision); ok {
+		r0 = rf(surveyID, responseID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.SurveyResponseRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(surveyID, responseID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSurveySegmentCounts provides a mock function with given fields: surveyID
+func (_m *Store) GetSurveySegmentCounts(surveyID string) (model.SurveySegmentCounts, error) {
+	ret := _m.Called(surveyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSurveySegmentCounts")
+	}
+
+	var r0 model.SurveySegmentCounts
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (model.SurveySegmentCounts, error)); ok {
+		return rf(surveyID)
+	}
+	if rf, ok := ret.Get(0).(func(string) model.SurveySegmentCounts); ok {
+		r0 = rf(surveyID)
+	} else {
+		r0 = ret.Get(0).(model.SurveySegmentCounts)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(surveyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetSurveyStat provides a mock function with given fields: surveyID
 func (_m *Store) GetSurveyStat(surveyID string) (*model.SurveyStat, error) {
 	ret := _m.Called(surveyID)
@@ -196,6 +542,36 @@ func (_m *Store) GetSurveyStatList() ([]*model.SurveyStat, error) {
 	return r0, r1
 }
 
+// GetSurveyTextAnalytics provides a mock function with given fields: surveyID
+func (_m *Store) GetSurveyTextAnalytics(surveyID string) (*model.SurveyTextAnalytics, error) {
+	ret := _m.Called(surveyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSurveyTextAnalytics")
+	}
+
+	var r0 *model.SurveyTextAnalytics
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.SurveyTextAnalytics, error)); ok {
+		return rf(surveyID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.SurveyTextAnalytics); ok {
+		r0 = rf(surveyID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SurveyTextAnalytics)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(surveyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetSurveysByID provides a mock function with given fields: surveyID
 func (_m *Store) GetSurveysByID(surveyID string) (*model.Survey, error) {
 	ret := _m.Called(surveyID)
@@ -294,22 +670,60 @@ func (_m *Store) IncrementSurveyReceiptCount(surveyID string) error {
 	return r0
 }
 
-// IncrementSurveyResponseCount provides a mock function with given fields: surveyID
-func (_m *Store) IncrementSurveyResponseCount(surveyID string) error {
-	ret := _m.Called(surveyID)
+// IsEncryptionKeyInUse provides a mock function with given fields: keyID
+func (_m *Store) IsEncryptionKeyInUse(keyID string) (bool, error) {
+	ret := _m.Called(keyID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for IncrementSurveyResponseCount")
+		panic("no return value specified for IsEncryptionKeyInUse")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(surveyID)
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (bool, error)); ok {
+		return rf(keyID)
+	}
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(keyID)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(bool)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(keyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkSurveyDeliveryOpened provides a mock function with given fields: userID, surveyID
+func (_m *Store) MarkSurveyDeliveryOpened(userID string, surveyID string) (bool, error) {
+	ret := _m.Called(userID, surveyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkSurveyDeliveryOpened")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (bool, error)); ok {
+		return rf(userID, surveyID)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) bool); ok {
+		r0 = rf(userID, surveyID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(userID, surveyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // Migrate provides a mock function with given fields: migrationTimeoutSeconds
@@ -330,6 +744,128 @@ func (_m *Store) Migrate(migrationTimeoutSeconds int) error {
 	return r0
 }
 
+// RebuildQuestionAnswerCounts provides a mock function with given fields: surveyID, questions
+func (_m *Store) RebuildQuestionAnswerCounts(surveyID string, questions []model.Question) error {
+	ret := _m.Called(surveyID, questions)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RebuildQuestionAnswerCounts")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []model.Question) error); ok {
+		r0 = rf(surveyID, questions)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RebuildSegmentCounts provides a mock function with given fields: surveyID, ratingQuestion
+func (_m *Store) RebuildSegmentCounts(surveyID string, ratingQuestion model.Question) error {
+	ret := _m.Called(surveyID, ratingQuestion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RebuildSegmentCounts")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, model.Question) error); ok {
+		r0 = rf(surveyID, ratingQuestion)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReconcileQuestionAnswerCounts provides a mock function with given fields: surveyID, questions
+func (_m *Store) ReconcileQuestionAnswerCounts(surveyID string, questions []model.Question) (bool, error) {
+	ret := _m.Called(surveyID, questions)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReconcileQuestionAnswerCounts")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, []model.Question) (bool, error)); ok {
+		return rf(surveyID, questions)
+	}
+	if rf, ok := ret.Get(0).(func(string, []model.Question) bool); ok {
+		r0 = rf(surveyID, questions)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, []model.Question) error); ok {
+		r1 = rf(surveyID, questions)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReconcileSegmentCounts provides a mock function with given fields: surveyID, ratingQuestion
+func (_m *Store) ReconcileSegmentCounts(surveyID string, ratingQuestion model.Question) (bool, error) {
+	ret := _m.Called(surveyID, ratingQuestion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReconcileSegmentCounts")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, model.Question) (bool, error)); ok {
+		return rf(surveyID, ratingQuestion)
+	}
+	if rf, ok := ret.Get(0).(func(string, model.Question) bool); ok {
+		r0 = rf(surveyID, ratingQuestion)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, model.Question) error); ok {
+		r1 = rf(surveyID, ratingQuestion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReconcileSurveyCounts provides a mock function with given fields: surveyID, ratingQuestion, reconcileDeliveries
+func (_m *Store) ReconcileSurveyCounts(surveyID string, ratingQuestion model.Question, reconcileDeliveries bool) (*model.SurveyCountsReconciliation, error) {
+	ret := _m.Called(surveyID, ratingQuestion, reconcileDeliveries)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReconcileSurveyCounts")
+	}
+
+	var r0 *model.SurveyCountsReconciliation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, model.Question, bool) (*model.SurveyCountsReconciliation, error)); ok {
+		return rf(surveyID, ratingQuestion, reconcileDeliveries)
+	}
+	if rf, ok := ret.Get(0).(func(string, model.Question, bool) *model.SurveyCountsReconciliation); ok {
+		r0 = rf(surveyID, ratingQuestion, reconcileDeliveries)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SurveyCountsReconciliation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, model.Question, bool) error); ok {
+		r1 = rf(surveyID, ratingQuestion, reconcileDeliveries)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ResetData provides a mock function with given fields:
 func (_m *Store) ResetData() error {
 	ret := _m.Called()
@@ -366,17 +902,53 @@ func (_m *Store) SaveSurvey(survey *model.Survey) error {
 	return r0
 }
 
-// SaveSurveyResponse provides a mock function with given fields: response
-func (_m *Store) SaveSurveyResponse(response *model.SurveyResponse) error {
-	ret := _m.Called(response)
+// SaveSurveyDelivery provides a mock function with given fields: delivery
+func (_m *Store) SaveSurveyDelivery(delivery *model.SurveyDelivery) error {
+	ret := _m.Called(delivery)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveSurveyDelivery")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.SurveyDelivery) error); ok {
+		r0 = rf(delivery)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveSurveyResponse provides a mock function with given fields: save
+func (_m *Store) SaveSurveyResponse(save *model.SurveyResponseSave) error {
+	ret := _m.Called(save)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SaveSurveyResponse")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.SurveyResponse) error); ok {
-		r0 = rf(response)
+	if rf, ok := ret.Get(0).(func(*model.SurveyResponseSave) error); ok {
+		r0 = rf(save)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveSurveyTextAnalytics provides a mock function with given fields: analytics
+func (_m *Store) SaveSurveyTextAnalytics(analytics *model.SurveyTextAnalytics) error {
+	ret := _m.Called(analytics)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveSurveyTextAnalytics")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.SurveyTextAnalytics) error); ok {
+		r0 = rf(analytics)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -432,6 +1004,24 @@ func (_m *Store) SurveysFromRows(rows *sql.Rows) ([]*model.Survey, error) {
 	return r0, r1
 }
 
+// UpdateQuestionAnswerCounts provides a mock function with given fields: surveyID, deltas
+func (_m *Store) UpdateQuestionAnswerCounts(surveyID string, deltas []model.QuestionAnswerCountDelta) error {
+	ret := _m.Called(surveyID, deltas)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateQuestionAnswerCounts")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []model.QuestionAnswerCountDelta) error); ok {
+		r0 = rf(surveyID, deltas)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateRatingGroupCount provides a mock function with given fields: surveyID, promoterFactor, neutralFactor, detractorFactor
 func (_m *Store) UpdateRatingGroupCount(surveyID string, promoterFactor int, neutralFactor int, detractorFactor int) error {
 	ret := _m.Called(surveyID, promoterFactor, neutralFactor, detractorFactor)
@@ -450,17 +1040,45 @@ func (_m *Store) UpdateRatingGroupCount(surveyID string, promoterFactor int, neu
 	return r0
 }
 
-// UpdateSurveyResponse provides a mock function with given fields: response
-func (_m *Store) UpdateSurveyResponse(response *model.SurveyResponse) error {
-	ret := _m.Called(response)
+// UpdateSurveyResponseAnswers provides a mock function with given fields: response, answers
+func (_m *Store) UpdateSurveyResponseAnswers(response *model.SurveyResponse, answers map[string]string) (bool, error) {
+	ret := _m.Called(response, answers)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSurveyResponseAnswers")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.SurveyResponse, map[string]string) (bool, error)); ok {
+		return rf(response, answers)
+	}
+	if rf, ok := ret.Get(0).(func(*model.SurveyResponse, map[string]string) bool); ok {
+		r0 = rf(response, answers)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.SurveyResponse, map[string]string) error); ok {
+		r1 = rf(response, answers)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateSurveyResponseRevisionAnswers provides a mock function with given fields: revisionID, answers
+func (_m *Store) UpdateSurveyResponseRevisionAnswers(revisionID string, answers map[string]string) error {
+	ret := _m.Called(revisionID, answers)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateSurveyResponse")
+		panic("no return value specified for UpdateSurveyResponseRevisionAnswers")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.SurveyResponse) error); ok {
-		r0 = rf(response)
+	if rf, ok := ret.Get(0).(func(string, map[string]string) error); ok {
+		r0 = rf(revisionID, answers)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -486,6 +1104,34 @@ func (_m *Store) UpdateSurveyStatus(surveyID string, status string) error {
 	return r0
 }
 
+// WithdrawSurveyResponse provides a mock function with given fields: withdrawal
+func (_m *Store) WithdrawSurveyResponse(withdrawal *model.SurveyResponseWithdrawal) (bool, error) {
+	ret := _m.Called(withdrawal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithdrawSurveyResponse")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.SurveyResponseWithdrawal) (bool, error)); ok {
+		return rf(withdrawal)
+	}
+	if rf, ok := ret.Get(0).(func(*model.SurveyResponseWithdrawal) bool); ok {
+		r0 = rf(withdrawal)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.SurveyResponseWithdrawal) error); ok {
+		r1 = rf(withdrawal)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewStore creates a new instance of Store. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewStore(t interface {
@@ -0,0 +1,315 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+const questionAnswerCountsBatchSize = 500
+
+// GetQuestionAnswerCounts returns the number of responses to the survey
+// that answered each of the questions, keyed by question ID.
+func (s *SQLStore) GetQuestionAnswerCounts(surveyID string, questionIDs []string) (map[string]int64, error) {
+	counts := map[string]int64{}
+	if len(questionIDs) == 0 {
+		return counts, nil
+	}
+
+	rows, err := s.getQueryBuilder().
+		Select("question_id", "COALESCE(SUM(response_count), 0)").
+		From(s.tablePrefix + "survey_question_answer_counts").
+		Where(sq.Eq{"survey_id": surveyID, "question_id": questionIDs}).
+		GroupBy("question_id").
+		Query()
+
+	if err != nil {
+		s.pluginAPI.LogError("GetQuestionAnswerCounts: failed to query question answer counts", "surveyID", surveyID, "error", err.Error())
+		return nil, errors.Wrap(err, "GetQuestionAnswerCounts: failed to query question answer counts")
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var questionID string
+		var count int64
+		if err := rows.Scan(&questionID, &count); err != nil {
+			s.pluginAPI.LogError("GetQuestionAnswerCounts: failed to scan row", "error", err.Error())
+			return nil, errors.Wrap(err, "GetQuestionAnswerCounts: failed to scan row")
+		}
+
+		counts[questionID] = count
+	}
+
+	for _, questionID := range questionIDs {
+		if _, ok := counts[questionID]; !ok {
+			counts[questionID] = 0
+		}
+	}
+
+	return counts, nil
+}
+
+// GetQuestionAnswerValueCounts returns the number of responses to the survey
+// with each answer to the question, keyed by the answer.
+func (s *SQLStore) GetQuestionAnswerValueCounts(surveyID, questionID string) (map[string]int64, error) {
+	rows, err := s.getQueryBuilder().
+		Select("answer", "response_count").
+		From(s.tablePrefix + "survey_question_answer_counts").
+		Where(sq.Eq{"survey_id": surveyID, "question_id": questionID}).
+		Where(sq.Gt{"response_count": 0}).
+		Query()
+
+	if err != nil {
+		s.pluginAPI.LogError("GetQuestionAnswerValueCounts: failed to query question answer counts", "surveyID", surveyID, "questionID", questionID, "error", err.Error())
+		return nil, errors.Wrap(err, "GetQuestionAnswerValueCounts: failed to query question answer counts")
+	}
+
+	defer rows.Close()
+
+	counts := map[string]int64{}
+	for rows.Next() {
+		var answer string
+		var count int64
+		if err := rows.Scan(&answer, &count); err != nil {
+			s.pluginAPI.LogError("GetQuestionAnswerValueCounts: failed to scan row", "error", err.Error())
+			return nil, errors.Wrap(err, "GetQuestionAnswerValueCounts: failed to scan row")
+		}
+
+		counts[answer] = count
+	}
+
+	return counts, nil
+}
+
+// UpdateQuestionAnswerCounts applies the changes to the survey's question answer counts,
+// such as when a response is withdrawn.
+func (s *SQLStore) UpdateQuestionAnswerCounts(surveyID string, deltas []model.QuestionAnswerCountDelta) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.pluginAPI.LogError("UpdateQuestionAnswerCounts: failed to begin transaction", "error", err.Error())
+		return errors.Wrap(err, "UpdateQuestionAnswerCounts: failed to begin transaction")
+	}
+
+	defer s.finalizeTransaction(tx)
+
+	if err := s.updateQuestionAnswerCounts(tx, surveyID, deltas); err != nil {
+		return errors.Wrap(err, "UpdateQuestionAnswerCounts: failed to update question answer counts")
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.pluginAPI.LogError("UpdateQuestionAnswerCounts: failed to commit transaction", "surveyID", surveyID, "error", err.Error())
+		return errors.Wrap(err, "UpdateQuestionAnswerCounts: failed to commit transaction")
+	}
+
+	return nil
+}
+
+// updateQuestionAnswerCounts applies the changes to the survey's question answer counts as part
+// of the transaction, adding the rows of answers that weren't counted yet. The survey's row is
+// locked first so a rebuild of the counts running meanwhile doesn't drop the changes.
+func (s *SQLStore) updateQuestionAnswerCounts(tx *sql.Tx, surveyID string, deltas []model.QuestionAnswerCountDelta) error {
+	if len(deltas) == 0 {
+		return nil
+	}
+
+	if err := s.lockSurvey(tx, surveyID); err != nil {
+		return errors.Wrap(err, "updateQuestionAnswerCounts: failed to lock survey")
+	}
+
+	increment := "ON CONFLICT (survey_id, question_id, answer) DO UPDATE SET response_count = " +
+		s.tablePrefix + "survey_question_answer_counts.response_count + EXCLUDED.response_count"
+	if s.dbType == model.DBTypeMySQL {
+		increment = "ON DUPLICATE KEY UPDATE response_count = response_count + VALUES(response_count)"
+	}
+
+	for start := 0; start < len(deltas); start += questionAnswerCountsBatchSize {
+		end := min(start+questionAnswerCountsBatchSize, len(deltas))
+
+		query := s.getQueryBuilder().
+			Insert(s.tablePrefix+"survey_question_answer_counts").
+			Columns("survey_id", "question_id", "answer", "response_count")
+
+		for _, delta := range deltas[start:end] {
+			query = query.Values(surveyID, delta.QuestionID, delta.Answer, delta.Delta)
+		}
+
+		if _, err := query.Suffix(increment).RunWith(tx).Exec(); err != nil {
+			s.pluginAPI.LogError("updateQuestionAnswerCounts: failed to update question answer counts", "surveyID", surveyID, "error", err.Error())
+			return errors.Wrap(err, "updateQuestionAnswerCounts: failed to update question answer counts")
+		}
+	}
+
+	return nil
+}
+
+// lockSurvey locks the survey's row until the end of the transaction.
+func (s *SQLStore) lockSurvey(tx *sql.Tx, surveyID string) error {
+	var lockedSurveyID string
+	err := s.getQueryBuilder().
+		Select("id").
+		From(s.tablePrefix + "survey").
+		Where(sq.Eq{"id": surveyID}).
+		Suffix("FOR UPDATE").
+		RunWith(tx).
+		QueryRow().
+		Scan(&lockedSurveyID)
+
+	if err != nil {
+		s.pluginAPI.LogError("lockSurvey: failed to lock survey", "surveyID", surveyID, "error", err.Error())
+		return errors.Wrap(err, "lockSurvey: failed to lock survey")
+	}
+
+	return nil
+}
+
+// ReconcileQuestionAnswerCounts compares the survey's question answer counts with the counts of its
+// responses and rebuilds them if they differ, returning whether they were rebuilt. The comparison doesn't
+// lock the survey, so it doesn't hold up saving responses while scanning them. A response saved meanwhile
+// can make the counts look different, which only costs rebuilding them with the survey locked.
+func (s *SQLStore) ReconcileQuestionAnswerCounts(surveyID string, questions []model.Question) (bool, error) {
+	actual, err := s.countQuestionAnswers(s.db, surveyID, questions)
+	if err != nil {
+		return false, errors.Wrap(err, "ReconcileQuestionAnswerCounts: failed to count question answers")
+	}
+
+	stored, err := s.getStoredQuestionAnswerCounts(surveyID)
+	if err != nil {
+		return false, errors.Wrap(err, "ReconcileQuestionAnswerCounts: failed to get stored question answer counts")
+	}
+
+	if stored.Equal(actual) {
+		return false, nil
+	}
+
+	if err := s.RebuildQuestionAnswerCounts(surveyID, questions); err != nil {
+		return false, errors.Wrap(err, "ReconcileQuestionAnswerCounts: failed to rebuild question answer counts")
+	}
+
+	return true, nil
+}
+
+// RebuildQuestionAnswerCounts recomputes the survey's question answer counts from its responses.
+// The survey's row is locked for the transaction, as when saving a response, so responses
+// saved meanwhile are counted once.
+func (s *SQLStore) RebuildQuestionAnswerCounts(surveyID string, questions []model.Question) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.pluginAPI.LogError("RebuildQuestionAnswerCounts: failed to begin transaction", "error", err.Error())
+		return errors.Wrap(err, "RebuildQuestionAnswerCounts: failed to begin transaction")
+	}
+
+	defer s.finalizeTransaction(tx)
+
+	if err := s.lockSurvey(tx, surveyID); err != nil {
+		return errors.Wrap(err, "RebuildQuestionAnswerCounts: failed to lock survey")
+	}
+
+	counts, err := s.countQuestionAnswers(tx, surveyID, questions)
+	if err != nil {
+		return errors.Wrap(err, "RebuildQuestionAnswerCounts: failed to count question answers")
+	}
+
+	_, err = s.getQueryBuilder().
+		Delete(s.tablePrefix + "survey_question_answer_counts").
+		Where(sq.Eq{"survey_id": surveyID}).
+		RunWith(tx).
+		Exec()
+
+	if err != nil {
+		s.pluginAPI.LogError("RebuildQuestionAnswerCounts: failed to delete question answer counts", "surveyID", surveyID, "error", err.Error())
+		return errors.Wrap(err, "RebuildQuestionAnswerCounts: failed to delete question answer counts")
+	}
+
+	if err := s.updateQuestionAnswerCounts(tx, surveyID, counts.ToDeltas()); err != nil {
+		return errors.Wrap(err, "RebuildQuestionAnswerCounts: failed to save question answer counts")
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.pluginAPI.LogError("RebuildQuestionAnswerCounts: failed to commit transaction", "surveyID", surveyID, "error", err.Error())
+		return errors.Wrap(err, "RebuildQuestionAnswerCounts: failed to commit transaction")
+	}
+
+	return nil
+}
+
+// countQuestionAnswers counts the answers of the survey's responses to the questions.
+// The rows are closed before returning, so the runner can be a transaction running
+// further queries.
+func (s *SQLStore) countQuestionAnswers(runner sq.BaseRunner, surveyID string, questions []model.Question) (model.QuestionAnswerCounts, error) {
+	rows, err := s.getQueryBuilder().
+		Select("response").
+		From(s.tablePrefix + "survey_responses").
+		Where(sq.Eq{"survey_id": surveyID}).
+		RunWith(runner).
+		Query()
+
+	if err != nil {
+		s.pluginAPI.LogError("countQuestionAnswers: failed to query survey responses", "surveyID", surveyID, "error", err.Error())
+		return nil, errors.Wrap(err, "countQuestionAnswers: failed to query survey responses")
+	}
+
+	defer rows.Close()
+
+	counts := model.QuestionAnswerCounts{}
+	for rows.Next() {
+		var response model.SurveyResponse
+		var responseString string
+
+		if err := rows.Scan(&responseString); err != nil {
+			s.pluginAPI.LogError("countQuestionAnswers: failed to scan survey response row", "surveyID", surveyID, "error", err.Error())
+			return nil, errors.Wrap(err, "countQuestionAnswers: failed to scan survey response row")
+		}
+
+		if err := json.Unmarshal([]byte(responseString), &response.Response); err != nil {
+			s.pluginAPI.LogError("countQuestionAnswers: failed to unmarshal response string", "surveyID", surveyID, "error", err.Error())
+			return nil, errors.Wrap(err, "countQuestionAnswers: failed to unmarshal response string")
+		}
+
+		counts.AddResponse(questions, &response, 1)
+	}
+
+	if err := rows.Err(); err != nil {
+		s.pluginAPI.LogError("countQuestionAnswers: failed to read survey responses", "surveyID", surveyID, "error", err.Error())
+		return nil, errors.Wrap(err, "countQuestionAnswers: failed to read survey responses")
+	}
+
+	return counts, nil
+}
+
+// getStoredQuestionAnswerCounts returns the survey's question answer counts as stored.
+func (s *SQLStore) getStoredQuestionAnswerCounts(surveyID string) (model.QuestionAnswerCounts, error) {
+	rows, err := s.getQueryBuilder().
+		Select("question_id", "answer", "response_count").
+		From(s.tablePrefix + "survey_question_answer_counts").
+		Where(sq.Eq{"survey_id": surveyID}).
+		Query()
+
+	if err != nil {
+		s.pluginAPI.LogError("getStoredQuestionAnswerCounts: failed to query question answer counts", "surveyID", surveyID, "error", err.Error())
+		return nil, errors.Wrap(err, "getStoredQuestionAnswerCounts: failed to query question answer counts")
+	}
+
+	defer rows.Close()
+
+	counts := model.QuestionAnswerCounts{}
+	for rows.Next() {
+		var key model.QuestionAnswerKey
+		var count int64
+		if err := rows.Scan(&key.QuestionID, &key.Answer, &count); err != nil {
+			s.pluginAPI.LogError("getStoredQuestionAnswerCounts: failed to scan row", "surveyID", surveyID, "error", err.Error())
+			return nil, errors.Wrap(err, "getStoredQuestionAnswerCounts: failed to scan row")
+		}
+
+		counts[key] = count
+	}
+
+	return counts, nil
+}
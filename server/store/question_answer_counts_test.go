@@ -0,0 +1,198 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+func TestQuestionAnswerCounts(t *testing.T) {
+	tests := []StoreTests{
+		testQuestionAnswerCounts,
+	}
+
+	testWithSupportedDatabases(t, tests)
+}
+
+func testQuestionAnswerCounts(t *testing.T, namePrefix string, sqlStore *SQLStore, tearDown func()) {
+	defer tearDown()
+
+	t.Run(namePrefix+" should add the deltas to the counts, inserting the answers not counted yet", func(t *testing.T) {
+		survey := createTestSurvey(t, sqlStore)
+		key := func(answer string) model.QuestionAnswerKey {
+			return model.QuestionAnswerKey{QuestionID: "rating", Answer: answer}
+		}
+
+		require.NoError(t, sqlStore.UpdateQuestionAnswerCounts(survey.ID, []model.QuestionAnswerCountDelta{
+			{QuestionAnswerKey: key("9"), Delta: 2},
+			{QuestionAnswerKey: key("4"), Delta: 1},
+		}))
+		require.NoError(t, sqlStore.UpdateQuestionAnswerCounts(survey.ID, []model.QuestionAnswerCountDelta{
+			{QuestionAnswerKey: key("9"), Delta: 1},
+			{QuestionAnswerKey: key("4"), Delta: -1},
+		}))
+
+		// answers down to zero are left out of the answer counts
+		counts, err := sqlStore.GetQuestionAnswerValueCounts(survey.ID, "rating")
+		require.NoError(t, err)
+		require.Equal(t, map[string]int64{"9": 3}, counts)
+	})
+
+	t.Run(namePrefix+" should update more answers than fit in a batch", func(t *testing.T) {
+		survey := createTestSurvey(t, sqlStore)
+
+		deltas := []model.QuestionAnswerCountDelta{}
+		for i := 0; i < questionAnswerCountsBatchSize+10; i++ {
+			deltas = append(deltas, model.QuestionAnswerCountDelta{
+				QuestionAnswerKey: model.QuestionAnswerKey{QuestionID: "rating", Answer: strconv.Itoa(i)},
+				Delta:             1,
+			})
+		}
+
+		require.NoError(t, sqlStore.UpdateQuestionAnswerCounts(survey.ID, deltas))
+
+		counts, err := sqlStore.GetQuestionAnswerCounts(survey.ID, []string{"rating"})
+		require.NoError(t, err)
+		require.Equal(t, map[string]int64{"rating": int64(len(deltas))}, counts)
+	})
+
+	t.Run(namePrefix+" should rebuild the counts that drifted from the responses", func(t *testing.T) {
+		survey := createTestSurvey(t, sqlStore)
+		questions := survey.SurveyQuestions.Questions
+
+		responses := []*model.SurveyResponse{
+			newTestResponse(survey, "9", "Great app"),
+			newTestResponse(survey, "9", ""),
+			newTestResponse(survey, "3", "Slow"),
+		}
+		for _, response := range responses {
+			require.NoError(t, sqlStore.SaveSurveyResponse(newTestResponseSave(survey, nil, response)))
+		}
+
+		rebuilt, err := sqlStore.ReconcileQuestionAnswerCounts(survey.ID, questions)
+		require.NoError(t, err)
+		require.False(t, rebuilt)
+
+		require.NoError(t, sqlStore.UpdateQuestionAnswerCounts(survey.ID, []model.QuestionAnswerCountDelta{
+			{QuestionAnswerKey: model.QuestionAnswerKey{QuestionID: "rating", Answer: "9"}, Delta: -1},
+			{QuestionAnswerKey: model.QuestionAnswerKey{QuestionID: "rating", Answer: "1"}, Delta: 4},
+		}))
+
+		rebuilt, err = sqlStore.ReconcileQuestionAnswerCounts(survey.ID, questions)
+		require.NoError(t, err)
+		require.True(t, rebuilt)
+
+		counts, err := sqlStore.GetQuestionAnswerValueCounts(survey.ID, "rating")
+		require.NoError(t, err)
+		require.Equal(t, map[string]int64{"9": 2, "3": 1}, counts)
+
+		answerCounts, err := sqlStore.GetQuestionAnswerCounts(survey.ID, []string{"rating", "text"})
+		require.NoError(t, err)
+		require.Equal(t, map[string]int64{"rating": 3, "text": 2}, answerCounts)
+	})
+
+	t.Run(namePrefix+" should build the counts of responses saved before they were counted", func(t *testing.T) {
+		survey := createTestSurvey(t, sqlStore)
+
+		for _, rating := range []string{"10", "6"} {
+			response := newTestResponse(survey, rating, "Okay")
+			require.NoError(t, sqlStore.SaveSurveyResponse(&model.SurveyResponseSave{Response: response}))
+		}
+
+		require.NoError(t, sqlStore.RebuildQuestionAnswerCounts(survey.ID, survey.SurveyQuestions.Questions))
+
+		counts, err := sqlStore.GetQuestionAnswerValueCounts(survey.ID, "rating")
+		require.NoError(t, err)
+		require.Equal(t, map[string]int64{"10": 1, "6": 1}, counts)
+	})
+}
+
+func TestSegmentCounts(t *testing.T) {
+	tests := []StoreTests{
+		testSegmentCounts,
+	}
+
+	testWithSupportedDatabases(t, tests)
+}
+
+func testSegmentCounts(t *testing.T, namePrefix string, sqlStore *SQLStore, tearDown func()) {
+	defer tearDown()
+
+	t.Run(namePrefix+" should build the counts of responses saved before they were counted", func(t *testing.T) {
+		survey := createTestSurvey(t, sqlStore)
+		ratingQuestion, err := survey.GetSystemRatingQuestion()
+		require.NoError(t, err)
+
+		responses := []*model.SurveyResponse{
+			newTestResponse(survey, "10", "Great app", testTeamA, testTeamB),
+			newTestResponse(survey, "7", "", testTeamA),
+			newTestResponse(survey, "1", "Slow and confusing", testTeamB),
+		}
+		for _, response := range responses {
+			require.NoError(t, sqlStore.SaveSurveyResponse(&model.SurveyResponseSave{Response: response}))
+		}
+
+		require.NoError(t, sqlStore.RebuildSegmentCounts(survey.ID, ratingQuestion))
+
+		segmentCounts, err := sqlStore.GetSurveySegmentCounts(survey.ID)
+		require.NoError(t, err)
+		require.True(t, newTestSegmentCounts(survey, responses...).Equal(segmentCounts))
+
+		teamA := segmentCounts[model.SegmentKey{Type: model.SegmentTypeTeam, ID: "team_a"}]
+		require.Equal(t, "Team A", teamA.TeamName)
+		require.Equal(t, int64(1), teamA.Promoters)
+		require.Equal(t, int64(1), teamA.Passives)
+	})
+
+	t.Run(namePrefix+" should rebuild the counts that drifted and keep those that didn't", func(t *testing.T) {
+		survey := createTestSurvey(t, sqlStore)
+		ratingQuestion, err := survey.GetSystemRatingQuestion()
+		require.NoError(t, err)
+
+		responses := []*model.SurveyResponse{
+			newTestResponse(survey, "9", "Great app", testTeamA),
+			newTestResponse(survey, "2", "Slow", testTeamA),
+		}
+		for _, response := range responses {
+			require.NoError(t, sqlStore.SaveSurveyResponse(newTestResponseSave(survey, nil, response)))
+		}
+
+		rebuilt, err := sqlStore.ReconcileSegmentCounts(survey.ID, ratingQuestion)
+		require.NoError(t, err)
+		require.False(t, rebuilt)
+
+		// a response saved without its segment counts
+		uncounted := newTestResponse(survey, "8", "Okay", testTeamB)
+		require.NoError(t, sqlStore.SaveSurveyResponse(&model.SurveyResponseSave{Response: uncounted}))
+
+		rebuilt, err = sqlStore.ReconcileSegmentCounts(survey.ID, ratingQuestion)
+		require.NoError(t, err)
+		require.True(t, rebuilt)
+
+		segmentCounts, err := sqlStore.GetSurveySegmentCounts(survey.ID)
+		require.NoError(t, err)
+		require.True(t, newTestSegmentCounts(survey, append(responses, uncounted)...).Equal(segmentCounts))
+	})
+
+	t.Run(namePrefix+" should only count the sentiment of surveys without a rating question", func(t *testing.T) {
+		survey := createTestSurvey(t, sqlStore)
+		response := newTestResponse(survey, "9", "Great app", testTeamA)
+		require.NoError(t, sqlStore.SaveSurveyResponse(&model.SurveyResponseSave{Response: response}))
+
+		require.NoError(t, sqlStore.RebuildSegmentCounts(survey.ID, model.Question{}))
+
+		segmentCounts, err := sqlStore.GetSurveySegmentCounts(survey.ID)
+		require.NoError(t, err)
+
+		teamA := segmentCounts[model.SegmentKey{Type: model.SegmentTypeTeam, ID: "team_a"}]
+		require.Zero(t, teamA.GetRespondents())
+		require.Equal(t, int64(1), teamA.SentimentRespondents)
+		require.NotContains(t, segmentCounts, model.SegmentKey{Type: model.SegmentTypeRatingGroup, ID: model.RatingGroupPromoters})
+	})
+}
@@ -0,0 +1,269 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+// GetSurveySegmentCounts returns the counts of the survey's segments.
+func (s *SQLStore) GetSurveySegmentCounts(surveyID string) (model.SurveySegmentCounts, error) {
+	rows, err := s.getQueryBuilder().
+		Select(
+			"segment_type",
+			"segment_id",
+			"team_name",
+			"promoters",
+			"passives",
+			"detractors",
+			"sentiment_respondents",
+			"sentiment_answers",
+			"sentiment_total",
+		).
+		From(s.tablePrefix + "survey_segment_counts").
+		Where(sq.Eq{"survey_id": surveyID}).
+		Query()
+
+	if err != nil {
+		s.pluginAPI.LogError("GetSurveySegmentCounts: failed to query segment counts", "surveyID", surveyID, "error", err.Error())
+		return nil, errors.Wrap(err, "GetSurveySegmentCounts: failed to query segment counts")
+	}
+
+	defer rows.Close()
+
+	segmentCounts := model.SurveySegmentCounts{}
+	for rows.Next() {
+		var key model.SegmentKey
+		var counts model.SegmentCounts
+		err := rows.Scan(
+			&key.Type,
+			&key.ID,
+			&counts.TeamName,
+			&counts.Promoters,
+			&counts.Passives,
+			&counts.Detractors,
+			&counts.SentimentRespondents,
+			&counts.SentimentAnswers,
+			&counts.SentimentTotal,
+		)
+
+		if err != nil {
+			s.pluginAPI.LogError("GetSurveySegmentCounts: failed to scan row", "surveyID", surveyID, "error", err.Error())
+			return nil, errors.Wrap(err, "GetSurveySegmentCounts: failed to scan row")
+		}
+
+		segmentCounts[key] = &counts
+	}
+
+	if err := rows.Err(); err != nil {
+		s.pluginAPI.LogError("GetSurveySegmentCounts: failed to read segment counts", "surveyID", surveyID, "error", err.Error())
+		return nil, errors.Wrap(err, "GetSurveySegmentCounts: failed to read segment counts")
+	}
+
+	return segmentCounts, nil
+}
+
+// updateSegmentCounts applies the changes to the survey's segment counts as part of the transaction,
+// adding the rows of segments that weren't counted yet. Team names are only replaced by non-empty names.
+// The survey's row is locked first so a rebuild of the counts running meanwhile doesn't drop the changes.
+func (s *SQLStore) updateSegmentCounts(tx *sql.Tx, surveyID string, deltas []model.SegmentCountsDelta) error {
+	if len(deltas) == 0 {
+		return nil
+	}
+
+	if err := s.lockSurvey(tx, surveyID); err != nil {
+		return errors.Wrap(err, "updateSegmentCounts: failed to lock survey")
+	}
+
+	table := s.tablePrefix + "survey_segment_counts"
+	increment := "ON CONFLICT (survey_id, segment_type, segment_id) DO UPDATE SET " +
+		"team_name = CASE WHEN EXCLUDED.team_name = '' THEN " + table + ".team_name ELSE EXCLUDED.team_name END, " +
+		"promoters = " + table + ".promoters + EXCLUDED.promoters, " +
+		"passives = " + table + ".passives + EXCLUDED.passives, " +
+		"detractors = " + table + ".detractors + EXCLUDED.detractors, " +
+		"sentiment_respondents = " + table + ".sentiment_respondents + EXCLUDED.sentiment_respondents, " +
+		"sentiment_answers = " + table + ".sentiment_answers + EXCLUDED.sentiment_answers, " +
+		"sentiment_total = " + table + ".sentiment_total + EXCLUDED.sentiment_total"
+	if s.dbType == model.DBTypeMySQL {
+		increment = "ON DUPLICATE KEY UPDATE " +
+			"team_name = IF(VALUES(team_name) = '', team_name, VALUES(team_name)), " +
+			"promoters = promoters + VALUES(promoters), " +
+			"passives = passives + VALUES(passives), " +
+			"detractors = detractors + VALUES(detractors), " +
+			"sentiment_respondents = sentiment_respondents + VALUES(sentiment_respondents), " +
+			"sentiment_answers = sentiment_answers + VALUES(sentiment_answers), " +
+			"sentiment_total = sentiment_total + VALUES(sentiment_total)"
+	}
+
+	for start := 0; start < len(deltas); start += questionAnswerCountsBatchSize {
+		end := min(start+questionAnswerCountsBatchSize, len(deltas))
+
+		query := s.getQueryBuilder().
+			Insert(table).
+			Columns(
+				"survey_id",
+				"segment_type",
+				"segment_id",
+				"team_name",
+				"promoters",
+				"passives",
+				"detractors",
+				"sentiment_respondents",
+				"sentiment_answers",
+				"sentiment_total",
+			)
+
+		for _, delta := range deltas[start:end] {
+			query = query.Values(
+				surveyID,
+				delta.Type,
+				delta.ID,
+				delta.TeamName,
+				delta.Promoters,
+				delta.Passives,
+				delta.Detractors,
+				delta.SentimentRespondents,
+				delta.SentimentAnswers,
+				delta.SentimentTotal,
+			)
+		}
+
+		if _, err := query.Suffix(increment).RunWith(tx).Exec(); err != nil {
+			s.pluginAPI.LogError("updateSegmentCounts: failed to update segment counts", "surveyID", surveyID, "error", err.Error())
+			return errors.Wrap(err, "updateSegmentCounts: failed to update segment counts")
+		}
+	}
+
+	return nil
+}
+
+// ReconcileSegmentCounts compares the survey's segment counts with the counts of its responses
+// and rebuilds them if they differ, returning whether they were rebuilt. As for the question answer
+// counts, the comparison doesn't lock the survey and only the rebuild does.
+func (s *SQLStore) ReconcileSegmentCounts(surveyID string, ratingQuestion model.Question) (bool, error) {
+	actual, err := s.countSegments(s.db, surveyID, ratingQuestion)
+	if err != nil {
+		return false, errors.Wrap(err, "ReconcileSegmentCounts: failed to count segments")
+	}
+
+	stored, err := s.GetSurveySegmentCounts(surveyID)
+	if err != nil {
+		return false, errors.Wrap(err, "ReconcileSegmentCounts: failed to get stored segment counts")
+	}
+
+	if stored.Equal(actual) {
+		return false, nil
+	}
+
+	if err := s.RebuildSegmentCounts(surveyID, ratingQuestion); err != nil {
+		return false, errors.Wrap(err, "ReconcileSegmentCounts: failed to rebuild segment counts")
+	}
+
+	return true, nil
+}
+
+// RebuildSegmentCounts recomputes the survey's segment counts from its responses. The rating question
+// is empty for surveys without one. The survey's row is locked for the transaction, as when saving
+// a response, so responses saved meanwhile are counted once.
+func (s *SQLStore) RebuildSegmentCounts(surveyID string, ratingQuestion model.Question) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.pluginAPI.LogError("RebuildSegmentCounts: failed to begin transaction", "error", err.Error())
+		return errors.Wrap(err, "RebuildSegmentCounts: failed to begin transaction")
+	}
+
+	defer s.finalizeTransaction(tx)
+
+	if err := s.lockSurvey(tx, surveyID); err != nil {
+		return errors.Wrap(err, "RebuildSegmentCounts: failed to lock survey")
+	}
+
+	counts, err := s.countSegments(tx, surveyID, ratingQuestion)
+	if err != nil {
+		return errors.Wrap(err, "RebuildSegmentCounts: failed to count segments")
+	}
+
+	_, err = s.getQueryBuilder().
+		Delete(s.tablePrefix + "survey_segment_counts").
+		Where(sq.Eq{"survey_id": surveyID}).
+		RunWith(tx).
+		Exec()
+
+	if err != nil {
+		s.pluginAPI.LogError("RebuildSegmentCounts: failed to delete segment counts", "surveyID", surveyID, "error", err.Error())
+		return errors.Wrap(err, "RebuildSegmentCounts: failed to delete segment counts")
+	}
+
+	if err := s.updateSegmentCounts(tx, surveyID, counts.ToDeltas()); err != nil {
+		return errors.Wrap(err, "RebuildSegmentCounts: failed to save segment counts")
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.pluginAPI.LogError("RebuildSegmentCounts: failed to commit transaction", "surveyID", surveyID, "error", err.Error())
+		return errors.Wrap(err, "RebuildSegmentCounts: failed to commit transaction")
+	}
+
+	return nil
+}
+
+// countSegments counts the survey's responses in its segments. The rows are closed
+// before returning, so the runner can be a transaction running further queries.
+func (s *SQLStore) countSegments(runner sq.BaseRunner, surveyID string, ratingQuestion model.Question) (model.SurveySegmentCounts, error) {
+	rows, err := s.getQueryBuilder().
+		Select("response", "metadata", "sentiment").
+		From(s.tablePrefix + "survey_responses").
+		Where(sq.Eq{"survey_id": surveyID}).
+		RunWith(runner).
+		Query()
+
+	if err != nil {
+		s.pluginAPI.LogError("countSegments: failed to query survey responses", "surveyID", surveyID, "error", err.Error())
+		return nil, errors.Wrap(err, "countSegments: failed to query survey responses")
+	}
+
+	defer rows.Close()
+
+	counts := model.SurveySegmentCounts{}
+	for rows.Next() {
+		var response model.SurveyResponse
+		var responseString string
+		var metadataString string
+		var sentimentString string
+
+		if err := rows.Scan(&responseString, &metadataString, &sentimentString); err != nil {
+			s.pluginAPI.LogError("countSegments: failed to scan survey response row", "surveyID", surveyID, "error", err.Error())
+			return nil, errors.Wrap(err, "countSegments: failed to scan survey response row")
+		}
+
+		if err := json.Unmarshal([]byte(responseString), &response.Response); err != nil {
+			s.pluginAPI.LogError("countSegments: failed to unmarshal response string", "surveyID", surveyID, "error", err.Error())
+			return nil, errors.Wrap(err, "countSegments: failed to unmarshal response string")
+		}
+
+		if err := json.Unmarshal([]byte(metadataString), &response.Metadata); err != nil {
+			s.pluginAPI.LogError("countSegments: failed to unmarshal response metadata", "surveyID", surveyID, "error", err.Error())
+			return nil, errors.Wrap(err, "countSegments: failed to unmarshal response metadata")
+		}
+
+		if err := json.Unmarshal([]byte(sentimentString), &response.Sentiment); err != nil {
+			s.pluginAPI.LogError("countSegments: failed to unmarshal response sentiment", "surveyID", surveyID, "error", err.Error())
+			return nil, errors.Wrap(err, "countSegments: failed to unmarshal response sentiment")
+		}
+
+		counts.AddResponse(ratingQuestion, &response, 1)
+	}
+
+	if err := rows.Err(); err != nil {
+		s.pluginAPI.LogError("countSegments: failed to read survey responses", "surveyID", surveyID, "error", err.Error())
+		return nil, errors.Wrap(err, "countSegments: failed to read survey responses")
+	}
+
+	return counts, nil
+}
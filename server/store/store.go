@@ -17,18 +17,42 @@ type Store interface {
 	GetSchemaName() (string, error)
 	GetSurveysByStatus(status string) ([]*model.Survey, error)
 	GetSurveysByID(surveyID string) (*model.Survey, error)
+	GetAllSurveys(lastSurveyID string, perPage uint64) ([]*model.Survey, error)
 	SurveysFromRows(rows *sql.Rows) ([]*model.Survey, error)
 	SaveSurvey(survey *model.Survey) error
 	UpdateSurveyStatus(surveyID, status string) error
-	SaveSurveyResponse(response *model.SurveyResponse) error
+	SaveSurveyResponse(save *model.SurveyResponseSave) error
 	GetSurveyResponse(userID, surveyID string) (*model.SurveyResponse, error)
-	UpdateSurveyResponse(response *model.SurveyResponse) error
+	GetSurveyResponseRevisions(surveyID, responseID string) ([]*model.SurveyResponseRevision, error)
+	GetAllResponseRevisions(surveyID, lastRevisionID string, perPage uint64) ([]*model.SurveyResponseRevision, error)
+	UpdateSurveyResponseRevisionAnswers(revisionID string, answers map[string]string) error
+	UpdateSurveyResponseAnswers(response *model.SurveyResponse, answers map[string]string) (bool, error)
+	WithdrawSurveyResponse(withdrawal *model.SurveyResponseWithdrawal) (bool, error)
+	IsEncryptionKeyInUse(keyID string) (bool, error)
 	IncrementSurveyReceiptCount(surveyID string) error
-	IncrementSurveyResponseCount(surveyID string) error
 	GetSurveyStatList() ([]*model.SurveyStat, error)
 	GetSurveyStat(surveyID string) (*model.SurveyStat, error)
+	GetEndedSurveyStats(questionID string, offset, limit uint64) ([]*model.SurveyStat, error)
 	UpdateRatingGroupCount(surveyID string, promoterFactor, neutralFactor, detractorFactor int) error
+	ReconcileSurveyCounts(surveyID string, ratingQuestion model.Question, reconcileDeliveries bool) (*model.SurveyCountsReconciliation, error)
+	SaveSurveyDelivery(delivery *model.SurveyDelivery) error
+	GetSurveyDelivery(userID, surveyID string) (*model.SurveyDelivery, error)
+	MarkSurveyDeliveryOpened(userID, surveyID string) (bool, error)
+	BackfillSurveyDeliveries(deliveries []*model.SurveyDelivery) error
 	ResetData() error
 	GetAllResponses(surveyID, lastResponseID string, perPage uint64) ([]*model.SurveyResponse, error)
+	GetResponseCountByLocale(surveyID string) (map[string]int64, error)
+	GetQuestionAnswerCounts(surveyID string, questionIDs []string) (map[string]int64, error)
+	GetQuestionAnswerValueCounts(surveyID, questionID string) (map[string]int64, error)
+	UpdateQuestionAnswerCounts(surveyID string, deltas []model.QuestionAnswerCountDelta) error
+	RebuildQuestionAnswerCounts(surveyID string, questions []model.Question) error
+	ReconcileQuestionAnswerCounts(surveyID string, questions []model.Question) (bool, error)
+	GetSurveySegmentCounts(surveyID string) (model.SurveySegmentCounts, error)
+	RebuildSegmentCounts(surveyID string, ratingQuestion model.Question) error
+	ReconcileSegmentCounts(surveyID string, ratingQuestion model.Question) (bool, error)
+	GetSurveyActivityCounts(surveyID string, intervalMillis int64) (*model.SurveyActivityCounts, error)
 	GetLatestEndedSurvey() (*model.Survey, error)
+	SaveSurveyTextAnalytics(analytics *model.SurveyTextAnalytics) error
+	GetSurveyTextAnalytics(surveyID string) (*model.SurveyTextAnalytics, error)
+	GetEndedSurveysWithoutTextAnalytics() ([]*model.Survey, error)
 }
@@ -47,6 +47,8 @@ func (s *SQLStore) SurveysFromRows(rows *sql.Rows) ([]*model.Survey, error) {
 		var survey model.Survey
 		var excludedTeamIDsJSON string
 		var questionsJSON string
+		var customizationJSON string
+		var anonymityJSON string
 
 		err := rows.Scan(
 			&survey.ID,
@@ -58,6 +60,9 @@ func (s *SQLStore) SurveysFromRows(rows *sql.Rows) ([]*model.Survey, error) {
 			&questionsJSON,
 			&survey.Status,
 			&survey.TeamFilterType,
+			&customizationJSON,
+			&anonymityJSON,
+			&survey.ResponsesEditable,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "SurveysFromRows failed to scan survey row")
@@ -73,6 +78,16 @@ func (s *SQLStore) SurveysFromRows(rows *sql.Rows) ([]*model.Survey, error) {
 			return nil, errors.Wrap(err, "SurveysFromRows: failed to unmarshal survey questions string to survey")
 		}
 
+		err = json.Unmarshal([]byte(customizationJSON), &survey.Customization)
+		if err != nil {
+			return nil, errors.Wrap(err, "SurveysFromRows: failed to unmarshal survey customization string to survey")
+		}
+
+		err = json.Unmarshal([]byte(anonymityJSON), &survey.Anonymity)
+		if err != nil {
+			return nil, errors.Wrap(err, "SurveysFromRows: failed to unmarshal survey anonymity string to survey")
+		}
+
 		surveys = append(surveys, &survey)
 	}
 
@@ -80,7 +95,7 @@ func (s *SQLStore) SurveysFromRows(rows *sql.Rows) ([]*model.Survey, error) {
 }
 
 func (s *SQLStore) SaveSurvey(survey *model.Survey) error {
-	excludedTeamIDs, surveyQuestions, err := s.surveyExtractJSONFields(survey)
+	excludedTeamIDs, surveyQuestions, customization, anonymity, err := s.surveyExtractJSONFields(survey)
 	if err != nil {
 		return errors.Wrap(err, "SaveSurvey: failed to extract JSON fields")
 	}
@@ -98,6 +113,9 @@ func (s *SQLStore) SaveSurvey(survey *model.Survey) error {
 			surveyQuestions,
 			survey.Status,
 			survey.TeamFilterType,
+			customization,
+			anonymity,
+			survey.ResponsesEditable,
 		).Exec()
 
 	if err != nil {
@@ -121,15 +139,25 @@ func (s *SQLStore) UpdateSurveyStatus(surveyID, status string) error {
 	return nil
 }
 
-func (s *SQLStore) surveyExtractJSONFields(survey *model.Survey) (excludedTeamIDs, surveyQuestions []byte, err error) {
+func (s *SQLStore) surveyExtractJSONFields(survey *model.Survey) (excludedTeamIDs, surveyQuestions, customization, anonymity []byte, err error) {
 	excludedTeamIDs, err = s.MarshalJSONB(survey.FilterTeamIDs)
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "surveyExtractJSONFields: failed to marshal excluded team IDs")
+		return nil, nil, nil, nil, errors.Wrap(err, "surveyExtractJSONFields: failed to marshal excluded team IDs")
 	}
 
 	surveyQuestions, err = s.MarshalJSONB(survey.SurveyQuestions)
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "surveyExtractJSONFields: failed to marshal survey questions")
+		return nil, nil, nil, nil, errors.Wrap(err, "surveyExtractJSONFields: failed to marshal survey questions")
+	}
+
+	customization, err = s.MarshalJSONB(survey.Customization)
+	if err != nil {
+		return nil, nil, nil, nil, errors.Wrap(err, "surveyExtractJSONFields: failed to marshal survey customization")
+	}
+
+	anonymity, err = s.MarshalJSONB(survey.Anonymity)
+	if err != nil {
+		return nil, nil, nil, nil, errors.Wrap(err, "surveyExtractJSONFields: failed to marshal survey anonymity")
 	}
 
 	return
@@ -146,6 +174,9 @@ func (s *SQLStore) surveyColumns() []string {
 		"questions",
 		"status",
 		"team_filter_type",
+		"customization",
+		"anonymity",
+		"responses_editable",
 	}
 }
 
@@ -164,16 +195,27 @@ func (s *SQLStore) IncrementSurveyReceiptCount(surveyID string) error {
 	return nil
 }
 
-func (s *SQLStore) IncrementSurveyResponseCount(surveyID string) error {
+// updateSurveyCounts applies the change to the survey's counters as part of the transaction.
+func (s *SQLStore) updateSurveyCounts(tx *sql.Tx, surveyID string, delta model.SurveyCountsDelta) error {
+	if delta.IsZero() {
+		return nil
+	}
+
 	_, err := s.getQueryBuilder().
 		Update(s.tablePrefix+"survey").
-		Set("response_count", sq.Expr("response_count + 1")).
+		Set("response_count", sq.Expr("response_count + (?)", delta.Responses)).
+		Set("completed_count", sq.Expr("completed_count + (?)", delta.Completed)).
+		Set("promoters_count", sq.Expr("promoters_count + (?)", delta.Promoters)).
+		Set("passives_count", sq.Expr("passives_count + (?)", delta.Passives)).
+		Set("detractors_count", sq.Expr("detractors_count + (?)", delta.Detractors)).
+		Set("redaction_count", sq.Expr("redaction_count + (?)", delta.Redactions)).
 		Where(sq.Eq{"id": surveyID}).
+		RunWith(tx).
 		Exec()
 
 	if err != nil {
-		s.pluginAPI.LogError("IncrementSurveyResponseCount: failed to update survey response count", "survey_id", surveyID, "error", err.Error())
-		return errors.Wrap(err, "IncrementSurveyResponseCount: failed to update survey response count")
+		s.pluginAPI.LogError("updateSurveyCounts: failed to update survey counts", "survey_id", surveyID, "error", err.Error())
+		return errors.Wrap(err, "updateSurveyCounts: failed to update survey counts")
 	}
 
 	return nil
@@ -222,6 +264,39 @@ func (s *SQLStore) GetSurveysByID(surveyID string) (*model.Survey, error) {
 	return surveys[0], nil
 }
 
+// GetAllSurveys returns a page of all surveys ordered by ID, starting after lastSurveyID,
+// for jobs that go through every survey.
+func (s *SQLStore) GetAllSurveys(lastSurveyID string, perPage uint64) ([]*model.Survey, error) {
+	query := s.getQueryBuilder().
+		Select(s.surveyColumns()...).
+		From(s.tablePrefix + "survey").
+		OrderBy("id").
+		Limit(perPage)
+
+	if lastSurveyID != "" {
+		query = query.Where(sq.Gt{"id": lastSurveyID})
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.pluginAPI.LogError("GetAllSurveys: failed to query a page", "lastSurveyID", lastSurveyID, "perPage", perPage, "error", err.Error())
+		return nil, errors.Wrap(err, "GetAllSurveys: failed to query a page")
+	}
+	defer rows.Close()
+
+	surveys, err := s.SurveysFromRows(rows)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetAllSurveys: failed to map survey rows to surveys")
+	}
+
+	if err := rows.Err(); err != nil {
+		s.pluginAPI.LogError("GetAllSurveys: failed to read survey rows", "lastSurveyID", lastSurveyID, "error", err.Error())
+		return nil, errors.Wrap(err, "GetAllSurveys: failed to read survey rows")
+	}
+
+	return surveys, nil
+}
+
 func (s *SQLStore) GetLatestEndedSurvey() (*model.Survey, error) {
 	// using master DB query builder here because this function is generally used
 	// after a survey was ended in database. Reading from a read replica immediately after
@@ -256,3 +331,132 @@ func (s *SQLStore) GetLatestEndedSurvey() (*model.Survey, error) {
 
 	return surveys[0], nil
 }
+
+// ReconcileSurveyCounts recomputes the survey's receipt and opened counts from its deliveries
+// and its response counters from its responses, grouping the ratings on the rating question's scale.
+// The receipt and opened counts are kept as stored unless reconcileDeliveries is true, as they can
+// only be recomputed once the survey deliveries are all in the deliveries table.
+// The survey's row is locked for the transaction so responses saved meanwhile are counted once.
+// It returns the counts stored before reconciling along with the recomputed counts.
+func (s *SQLStore) ReconcileSurveyCounts(surveyID string, ratingQuestion model.Question, reconcileDeliveries bool) (*model.SurveyCountsReconciliation, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.pluginAPI.LogError("ReconcileSurveyCounts: failed to begin transaction", "error", err.Error())
+		return nil, errors.Wrap(err, "ReconcileSurveyCounts: failed to begin transaction")
+	}
+
+	defer s.finalizeTransaction(tx)
+
+	reconciliation := &model.SurveyCountsReconciliation{SurveyID: surveyID}
+	stored := &reconciliation.Stored
+
+	err = s.getQueryBuilder().
+		Select(
+			"receipt_count",
+			"opened_count",
+			"response_count",
+			"completed_count",
+			"promoters_count",
+			"passives_count",
+			"detractors_count",
+		).
+		From(s.tablePrefix+"survey").
+		Where(sq.Eq{"id": surveyID}).
+		Suffix("FOR UPDATE").
+		RunWith(tx).
+		QueryRow().
+		Scan(&stored.Receipts, &stored.Opened, &stored.Responses, &stored.Completed, &stored.Promoters, &stored.Passives, &stored.Detractors)
+
+	if err != nil {
+		s.pluginAPI.LogError("ReconcileSurveyCounts: failed to lock survey counts", "surveyID", surveyID, "error", err.Error())
+		return nil, errors.Wrap(err, "ReconcileSurveyCounts: failed to lock survey counts")
+	}
+
+	actual := &reconciliation.Actual
+	actual.Receipts, actual.Opened = stored.Receipts, stored.Opened
+
+	if reconcileDeliveries {
+		err = s.getQueryBuilder().
+			Select(
+				"COUNT(*)",
+				fmt.Sprintf("COALESCE(SUM(CASE WHEN status = '%s' THEN 1 ELSE 0 END), 0)", model.SurveyDeliveryStatusOpened),
+			).
+			From(s.tablePrefix+"survey_deliveries").
+			Where(sq.Eq{"survey_id": surveyID}).
+			RunWith(tx).
+			QueryRow().
+			Scan(&actual.Receipts, &actual.Opened)
+
+		if err != nil {
+			s.pluginAPI.LogError("ReconcileSurveyCounts: failed to count survey deliveries", "surveyID", surveyID, "error", err.Error())
+			return nil, errors.Wrap(err, "ReconcileSurveyCounts: failed to count survey deliveries")
+		}
+	}
+
+	rows, err := s.getQueryBuilder().
+		Select("response_type", "response").
+		From(s.tablePrefix + "survey_responses").
+		Where(sq.Eq{"survey_id": surveyID}).
+		RunWith(tx).
+		Query()
+
+	if err != nil {
+		s.pluginAPI.LogError("ReconcileSurveyCounts: failed to query survey responses", "surveyID", surveyID, "error", err.Error())
+		return nil, errors.Wrap(err, "ReconcileSurveyCounts: failed to query survey responses")
+	}
+
+	for rows.Next() {
+		var response model.SurveyResponse
+		var responseString string
+
+		if err := rows.Scan(&response.ResponseType, &responseString); err != nil {
+			rows.Close()
+			s.pluginAPI.LogError("ReconcileSurveyCounts: failed to scan survey response row", "surveyID", surveyID, "error", err.Error())
+			return nil, errors.Wrap(err, "ReconcileSurveyCounts: failed to scan survey response row")
+		}
+
+		if err := json.Unmarshal([]byte(responseString), &response.Response); err != nil {
+			rows.Close()
+			s.pluginAPI.LogError("ReconcileSurveyCounts: failed to unmarshal response string", "surveyID", surveyID, "error", err.Error())
+			return nil, errors.Wrap(err, "ReconcileSurveyCounts: failed to unmarshal response string")
+		}
+
+		actual.AddResponse(&response, ratingQuestion)
+	}
+
+	// the rows need closing before running the next query in the transaction
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		s.pluginAPI.LogError("ReconcileSurveyCounts: failed to read survey responses", "surveyID", surveyID, "error", err.Error())
+		return nil, errors.Wrap(err, "ReconcileSurveyCounts: failed to read survey responses")
+	}
+
+	if !reconciliation.HasDiscrepancies() {
+		return reconciliation, nil
+	}
+
+	_, err = s.getQueryBuilder().
+		Update(s.tablePrefix+"survey").
+		Set("receipt_count", actual.Receipts).
+		Set("opened_count", actual.Opened).
+		Set("response_count", actual.Responses).
+		Set("completed_count", actual.Completed).
+		Set("promoters_count", actual.Promoters).
+		Set("passives_count", actual.Passives).
+		Set("detractors_count", actual.Detractors).
+		Where(sq.Eq{"id": surveyID}).
+		RunWith(tx).
+		Exec()
+
+	if err != nil {
+		s.pluginAPI.LogError("ReconcileSurveyCounts: failed to update survey counts", "surveyID", surveyID, "error", err.Error())
+		return nil, errors.Wrap(err, "ReconcileSurveyCounts: failed to update survey counts")
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.pluginAPI.LogError("ReconcileSurveyCounts: failed to commit transaction", "surveyID", surveyID, "error", err.Error())
+		return nil, errors.Wrap(err, "ReconcileSurveyCounts: failed to commit transaction")
+	}
+
+	return reconciliation, nil
+}
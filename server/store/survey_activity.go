@@ -0,0 +1,87 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"fmt"
+
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+// GetSurveyActivityCounts returns the number of deliveries, ratings and completions
+// of the survey in each interval of the given length in milliseconds.
+func (s *SQLStore) GetSurveyActivityCounts(surveyID string, intervalMillis int64) (*model.SurveyActivityCounts, error) {
+	if intervalMillis <= 0 {
+		return nil, errors.New("GetSurveyActivityCounts: interval must be positive")
+	}
+
+	deliveries, err := s.getCountsByInterval("survey_deliveries", "delivered_at", surveyID, intervalMillis)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetSurveyActivityCounts: failed to count deliveries")
+	}
+
+	ratings, err := s.getCountsByInterval("survey_responses", "create_at", surveyID, intervalMillis)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetSurveyActivityCounts: failed to count ratings")
+	}
+
+	completions, err := s.getCountsByInterval("survey_responses", "completed_at", surveyID, intervalMillis)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetSurveyActivityCounts: failed to count completions")
+	}
+
+	return &model.SurveyActivityCounts{
+		Deliveries:  deliveries,
+		Ratings:     ratings,
+		Completions: completions,
+	}, nil
+}
+
+// getCountsByInterval counts the survey's rows in the table by the interval their time column falls in.
+// Rows with a zero time, such as responses that aren't complete yet, aren't counted.
+func (s *SQLStore) getCountsByInterval(table, timeColumn, surveyID string, intervalMillis int64) (map[int64]int64, error) {
+	// the interval is inlined rather than a placeholder, as the expression is grouped by
+	// and Postgres can't tell the placeholders in the SELECT and GROUP BY clauses are the same
+	divisionOperator := "/"
+	if s.dbType == model.DBTypeMySQL {
+		divisionOperator = "DIV"
+	}
+	intervalStart := fmt.Sprintf("(%s %s %d) * %d", timeColumn, divisionOperator, intervalMillis, intervalMillis)
+
+	rows, err := s.getQueryBuilder().
+		Select(intervalStart, "COUNT(*)").
+		From(s.tablePrefix + table).
+		Where(sq.Eq{"survey_id": surveyID}).
+		Where(sq.Gt{timeColumn: 0}).
+		GroupBy(intervalStart).
+		Query()
+
+	if err != nil {
+		s.pluginAPI.LogError("getCountsByInterval: failed to query counts", "table", table, "surveyID", surveyID, "error", err.Error())
+		return nil, errors.Wrapf(err, "getCountsByInterval: failed to query counts, table: %s", table)
+	}
+
+	defer rows.Close()
+
+	counts := map[int64]int64{}
+	for rows.Next() {
+		var intervalStartMillis, count int64
+		if err := rows.Scan(&intervalStartMillis, &count); err != nil {
+			s.pluginAPI.LogError("getCountsByInterval: failed to scan row", "table", table, "surveyID", surveyID, "error", err.Error())
+			return nil, errors.Wrapf(err, "getCountsByInterval: failed to scan row, table: %s", table)
+		}
+
+		counts[intervalStartMillis] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		s.pluginAPI.LogError("getCountsByInterval: failed to iterate rows", "table", table, "surveyID", surveyID, "error", err.Error())
+		return nil, errors.Wrapf(err, "getCountsByInterval: failed to iterate rows, table: %s", table)
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"database/sql"
+
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+const backfillSurveyDeliveriesBatchSize = 500
+
+// SaveSurveyDelivery records the survey being sent to the user. When the survey is resent,
+// only the post ID is updated, keeping the original delivery time and status.
+func (s *SQLStore) SaveSurveyDelivery(delivery *model.SurveyDelivery) error {
+	upsert := "ON CONFLICT (user_id, survey_id) DO UPDATE SET post_id = EXCLUDED.post_id"
+	if s.dbType == model.DBTypeMySQL {
+		upsert = "ON DUPLICATE KEY UPDATE post_id = VALUES(post_id)"
+	}
+
+	_, err := s.getQueryBuilder().
+		Insert(s.tablePrefix+"survey_deliveries").
+		Columns(s.surveyDeliveryColumns()...).
+		Values(delivery.UserID, delivery.SurveyID, delivery.PostID, delivery.DeliveredAt, delivery.Status).
+		Suffix(upsert).
+		Exec()
+
+	if err != nil {
+		s.pluginAPI.LogError("SaveSurveyDelivery: failed to save survey delivery", "userID", delivery.UserID, "surveyID", delivery.SurveyID, "error", err.Error())
+		return errors.Wrap(err, "SaveSurveyDelivery: failed to save survey delivery")
+	}
+
+	return nil
+}
+
+// GetSurveyDelivery returns the delivery of the survey to the user,
+// or nil if the survey wasn't sent to the user.
+func (s *SQLStore) GetSurveyDelivery(userID, surveyID string) (*model.SurveyDelivery, error) {
+	var delivery model.SurveyDelivery
+
+	err := s.getQueryBuilder().
+		Select(s.surveyDeliveryColumns()...).
+		From(s.tablePrefix+"survey_deliveries").
+		Where(sq.Eq{
+			"user_id":   userID,
+			"survey_id": surveyID,
+		}).
+		QueryRow().
+		Scan(&delivery.UserID, &delivery.SurveyID, &delivery.PostID, &delivery.DeliveredAt, &delivery.Status)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		s.pluginAPI.LogError("GetSurveyDelivery: failed to get survey delivery", "userID", userID, "surveyID", surveyID, "error", err.Error())
+		return nil, errors.Wrap(err, "GetSurveyDelivery: failed to get survey delivery")
+	}
+
+	return &delivery, nil
+}
+
+// MarkSurveyDeliveryOpened marks the survey delivered to the user as opened and increments
+// the survey's opened count, only the first time the user opens the survey.
+// It returns whether the delivery was marked as opened.
+func (s *SQLStore) MarkSurveyDeliveryOpened(userID, surveyID string) (bool, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.pluginAPI.LogError("MarkSurveyDeliveryOpened: failed to begin transaction", "error", err.Error())
+		return false, errors.Wrap(err, "MarkSurveyDeliveryOpened: failed to begin transaction")
+	}
+
+	defer s.finalizeTransaction(tx)
+
+	result, err := s.getQueryBuilder().
+		Update(s.tablePrefix+"survey_deliveries").
+		Set("status", model.SurveyDeliveryStatusOpened).
+		Where(sq.Eq{
+			"user_id":   userID,
+			"survey_id": surveyID,
+			"status":    model.SurveyDeliveryStatusDelivered,
+		}).
+		RunWith(tx).
+		Exec()
+
+	if err != nil {
+		s.pluginAPI.LogError("MarkSurveyDeliveryOpened: failed to update survey delivery status", "userID", userID, "surveyID", surveyID, "error", err.Error())
+		return false, errors.Wrap(err, "MarkSurveyDeliveryOpened: failed to update survey delivery status")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		s.pluginAPI.LogError("MarkSurveyDeliveryOpened: failed to get affected rows", "userID", userID, "surveyID", surveyID, "error", err.Error())
+		return false, errors.Wrap(err, "MarkSurveyDeliveryOpened: failed to get affected rows")
+	}
+
+	if rowsAffected == 0 {
+		// either the survey wasn't sent to the user or it was already opened
+		return false, nil
+	}
+
+	_, err = s.getQueryBuilder().
+		Update(s.tablePrefix+"survey").
+		Set("opened_count", sq.Expr("opened_count + 1")).
+		Where(sq.Eq{"id": surveyID}).
+		RunWith(tx).
+		Exec()
+
+	if err != nil {
+		s.pluginAPI.LogError("MarkSurveyDeliveryOpened: failed to update survey opened count", "surveyID", surveyID, "error", err.Error())
+		return false, errors.Wrap(err, "MarkSurveyDeliveryOpened: failed to update survey opened count")
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.pluginAPI.LogError("MarkSurveyDeliveryOpened: failed to commit transaction", "userID", userID, "surveyID", surveyID, "error", err.Error())
+		return false, errors.Wrap(err, "MarkSurveyDeliveryOpened: failed to commit transaction")
+	}
+
+	return true, nil
+}
+
+// BackfillSurveyDeliveries inserts deliveries recorded before the deliveries table existed.
+// Deliveries that are already in the table are left untouched.
+func (s *SQLStore) BackfillSurveyDeliveries(deliveries []*model.SurveyDelivery) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.pluginAPI.LogError("BackfillSurveyDeliveries: failed to begin transaction", "error", err.Error())
+		return errors.Wrap(err, "BackfillSurveyDeliveries: failed to begin transaction")
+	}
+
+	defer s.finalizeTransaction(tx)
+
+	skipExisting := "ON CONFLICT (user_id, survey_id) DO NOTHING"
+	if s.dbType == model.DBTypeMySQL {
+		skipExisting = "ON DUPLICATE KEY UPDATE user_id = user_id"
+	}
+
+	for start := 0; start < len(deliveries); start += backfillSurveyDeliveriesBatchSize {
+		end := start + backfillSurveyDeliveriesBatchSize
+		if end > len(deliveries) {
+			end = len(deliveries)
+		}
+
+		query := s.getQueryBuilder().
+			Insert(s.tablePrefix + "survey_deliveries").
+			Columns(s.surveyDeliveryColumns()...)
+
+		for _, delivery := range deliveries[start:end] {
+			query = query.Values(delivery.UserID, delivery.SurveyID, delivery.PostID, delivery.DeliveredAt, delivery.Status)
+		}
+
+		if _, err := query.Suffix(skipExisting).RunWith(tx).Exec(); err != nil {
+			s.pluginAPI.LogError("BackfillSurveyDeliveries: failed to insert survey deliveries", "error", err.Error())
+			return errors.Wrap(err, "BackfillSurveyDeliveries: failed to insert survey deliveries")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.pluginAPI.LogError("BackfillSurveyDeliveries: failed to commit transaction", "error", err.Error())
+		return errors.Wrap(err, "BackfillSurveyDeliveries: failed to commit transaction")
+	}
+
+	return nil
+}
+
+func (s *SQLStore) surveyDeliveryColumns() []string {
+	return []string{
+		"user_id",
+		"survey_id",
+		"post_id",
+		"delivered_at",
+		"status",
+	}
+}
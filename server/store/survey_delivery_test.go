@@ -0,0 +1,118 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+	"github.com/mattermost/mattermost-plugin-user-survey/server/utils"
+)
+
+func newTestDelivery(surveyID, status string) *model.SurveyDelivery {
+	return &model.SurveyDelivery{
+		UserID:      utils.NewID(),
+		SurveyID:    surveyID,
+		PostID:      utils.NewID(),
+		DeliveredAt: 1000,
+		Status:      status,
+	}
+}
+
+func TestSurveyDeliveries(t *testing.T) {
+	tests := []StoreTests{
+		testSurveyDeliveries,
+	}
+
+	testWithSupportedDatabases(t, tests)
+}
+
+func testSurveyDeliveries(t *testing.T, namePrefix string, sqlStore *SQLStore, tearDown func()) {
+	defer tearDown()
+
+	t.Run(namePrefix+" should only update the post of a resent survey", func(t *testing.T) {
+		survey := createTestSurvey(t, sqlStore)
+		delivery := newTestDelivery(survey.ID, model.SurveyDeliveryStatusDelivered)
+		require.NoError(t, sqlStore.SaveSurveyDelivery(delivery))
+
+		opened, err := sqlStore.MarkSurveyDeliveryOpened(delivery.UserID, survey.ID)
+		require.NoError(t, err)
+		require.True(t, opened)
+
+		resent := *delivery
+		resent.PostID = utils.NewID()
+		resent.DeliveredAt = 5000
+		resent.Status = model.SurveyDeliveryStatusDelivered
+		require.NoError(t, sqlStore.SaveSurveyDelivery(&resent))
+
+		saved, err := sqlStore.GetSurveyDelivery(delivery.UserID, survey.ID)
+		require.NoError(t, err)
+		require.Equal(t, &model.SurveyDelivery{
+			UserID:      delivery.UserID,
+			SurveyID:    survey.ID,
+			PostID:      resent.PostID,
+			DeliveredAt: 1000,
+			Status:      model.SurveyDeliveryStatusOpened,
+		}, saved)
+	})
+
+	t.Run(namePrefix+" should count a survey as opened the first time only", func(t *testing.T) {
+		survey := createTestSurvey(t, sqlStore)
+		delivery := newTestDelivery(survey.ID, model.SurveyDeliveryStatusDelivered)
+		require.NoError(t, sqlStore.SaveSurveyDelivery(delivery))
+
+		opened, err := sqlStore.MarkSurveyDeliveryOpened(delivery.UserID, survey.ID)
+		require.NoError(t, err)
+		require.True(t, opened)
+
+		opened, err = sqlStore.MarkSurveyDeliveryOpened(delivery.UserID, survey.ID)
+		require.NoError(t, err)
+		require.False(t, opened)
+
+		// the survey wasn't sent to this user
+		opened, err = sqlStore.MarkSurveyDeliveryOpened(utils.NewID(), survey.ID)
+		require.NoError(t, err)
+		require.False(t, opened)
+
+		requireSurveyCounts(t, sqlStore, survey.ID, model.SurveyCounts{Opened: 1})
+	})
+
+	t.Run(namePrefix+" should backfill deliveries in batches and leave existing ones untouched", func(t *testing.T) {
+		survey := createTestSurvey(t, sqlStore)
+		existing := newTestDelivery(survey.ID, model.SurveyDeliveryStatusOpened)
+		require.NoError(t, sqlStore.SaveSurveyDelivery(existing))
+
+		backfilled := *existing
+		backfilled.PostID = utils.NewID()
+		backfilled.Status = model.SurveyDeliveryStatusDelivered
+
+		deliveries := []*model.SurveyDelivery{&backfilled}
+		for i := 0; i < backfillSurveyDeliveriesBatchSize+10; i++ {
+			deliveries = append(deliveries, newTestDelivery(survey.ID, model.SurveyDeliveryStatusDelivered))
+		}
+
+		require.NoError(t, sqlStore.BackfillSurveyDeliveries(deliveries))
+
+		// backfilling again is no-op
+		require.NoError(t, sqlStore.BackfillSurveyDeliveries(deliveries))
+
+		saved, err := sqlStore.GetSurveyDelivery(existing.UserID, survey.ID)
+		require.NoError(t, err)
+		require.Equal(t, existing, saved)
+
+		saved, err = sqlStore.GetSurveyDelivery(deliveries[len(deliveries)-1].UserID, survey.ID)
+		require.NoError(t, err)
+		require.Equal(t, deliveries[len(deliveries)-1], saved)
+
+		ratingQuestion, err := survey.GetSystemRatingQuestion()
+		require.NoError(t, err)
+
+		reconciliation, err := sqlStore.ReconcileSurveyCounts(survey.ID, ratingQuestion, true)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(deliveries)), reconciliation.Actual.Receipts)
+		require.Equal(t, int64(1), reconciliation.Actual.Opened)
+	})
+}
@@ -14,11 +14,67 @@ import (
 	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
 )
 
-func (s *SQLStore) SaveSurveyResponse(response *model.SurveyResponse) error {
+// SaveSurveyResponse saves the response and applies its changes to the survey counters and question
+// answer counts in a single transaction, so the counters can't get out of sync with the saved responses.
+func (s *SQLStore) SaveSurveyResponse(save *model.SurveyResponseSave) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.pluginAPI.LogError("SaveSurveyResponse: failed to begin transaction", "error", err.Error())
+		return errors.Wrap(err, "SaveSurveyResponse: failed to begin transaction")
+	}
+
+	defer s.finalizeTransaction(tx)
+
+	switch {
+	case save.Existing == nil:
+		err = s.insertSurveyResponse(tx, save.Response)
+	case save.Existing.ResponseType == model.ResponseTypeComplete:
+		err = s.editSurveyResponse(tx, save.Response, save.Revision)
+	default:
+		err = s.updateSurveyResponse(tx, save.Response)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "SaveSurveyResponse: failed to save survey response")
+	}
+
+	if err := s.updateSurveyCounts(tx, save.Response.SurveyID, save.Counts); err != nil {
+		return errors.Wrap(err, "SaveSurveyResponse: failed to update survey counts")
+	}
+
+	if err := s.updateQuestionAnswerCounts(tx, save.Response.SurveyID, save.AnswerCounts); err != nil {
+		return errors.Wrap(err, "SaveSurveyResponse: failed to update question answer counts")
+	}
+
+	if err := s.updateSegmentCounts(tx, save.Response.SurveyID, save.SegmentCounts); err != nil {
+		return errors.Wrap(err, "SaveSurveyResponse: failed to update segment counts")
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.pluginAPI.LogError("SaveSurveyResponse: failed to commit transaction", "responseID", save.Response.ID, "error", err.Error())
+		return errors.Wrap(err, "SaveSurveyResponse: failed to commit transaction")
+	}
+
+	return nil
+}
+
+func (s *SQLStore) insertSurveyResponse(tx *sql.Tx, response *model.SurveyResponse) error {
 	questionResponseJSON, err := s.MarshalJSONB(response.Response)
 	if err != nil {
-		s.pluginAPI.LogError("SaveSurveyResponse: failed to marshal response map", "error", err.Error())
-		return errors.Wrap(err, "SaveSurveyResponse: failed to marshal response map")
+		s.pluginAPI.LogError("insertSurveyResponse: failed to marshal response map", "error", err.Error())
+		return errors.Wrap(err, "insertSurveyResponse: failed to marshal response map")
+	}
+
+	metadataJSON, err := s.MarshalJSONB(response.Metadata)
+	if err != nil {
+		s.pluginAPI.LogError("insertSurveyResponse: failed to marshal response metadata", "error", err.Error())
+		return errors.Wrap(err, "insertSurveyResponse: failed to marshal response metadata")
+	}
+
+	sentimentJSON, err := s.MarshalJSONB(response.Sentiment)
+	if err != nil {
+		s.pluginAPI.LogError("insertSurveyResponse: failed to marshal response sentiment", "error", err.Error())
+		return errors.Wrap(err, "insertSurveyResponse: failed to marshal response sentiment")
 	}
 
 	_, err = s.getQueryBuilder().
@@ -31,21 +87,47 @@ func (s *SQLStore) SaveSurveyResponse(response *model.SurveyResponse) error {
 			questionResponseJSON,
 			response.CreateAt,
 			response.ResponseType,
-		).Exec()
+			response.Locale,
+			response.UpdateAt,
+			metadataJSON,
+			response.IdempotencyKey,
+			response.CompletedAt,
+			sentimentJSON,
+			response.RedactionCount,
+		).
+		RunWith(tx).
+		Exec()
 
 	if err != nil {
-		s.pluginAPI.LogError("SaveSurveyResponse: failed to save survey response in database", "error", err.Error())
-		return errors.Wrap(err, "SaveSurveyResponse: failed to save survey response in database")
+		s.pluginAPI.LogError("insertSurveyResponse: failed to save survey response in database", "error", err.Error())
+		return errors.Wrap(err, "insertSurveyResponse: failed to save survey response in database")
 	}
 
 	return nil
 }
 
-func (s *SQLStore) UpdateSurveyResponse(response *model.SurveyResponse) error {
+// updateSurveyResponse updates a partial response.
+func (s *SQLStore) updateSurveyResponse(tx *sql.Tx, response *model.SurveyResponse) error {
+	if err := s.lockSurveyResponse(tx, response.ID, model.ResponseTypePartial); err != nil {
+		return errors.Wrap(err, "updateSurveyResponse: failed to lock survey response")
+	}
+
 	questionResponseJSON, err := s.MarshalJSONB(response.Response)
 	if err != nil {
-		s.pluginAPI.LogError("UpdateSurveyResponse: failed to marshal response map", "error", err.Error())
-		return errors.Wrap(err, "UpdateSurveyResponse: failed to marshal response map")
+		s.pluginAPI.LogError("updateSurveyResponse: failed to marshal response map", "error", err.Error())
+		return errors.Wrap(err, "updateSurveyResponse: failed to marshal response map")
+	}
+
+	metadataJSON, err := s.MarshalJSONB(response.Metadata)
+	if err != nil {
+		s.pluginAPI.LogError("updateSurveyResponse: failed to marshal response metadata", "error", err.Error())
+		return errors.Wrap(err, "updateSurveyResponse: failed to marshal response metadata")
+	}
+
+	sentimentJSON, err := s.MarshalJSONB(response.Sentiment)
+	if err != nil {
+		s.pluginAPI.LogError("updateSurveyResponse: failed to marshal response sentiment", "error", err.Error())
+		return errors.Wrap(err, "updateSurveyResponse: failed to marshal response sentiment")
 	}
 
 	_, err = s.getQueryBuilder().
@@ -53,19 +135,345 @@ func (s *SQLStore) UpdateSurveyResponse(response *model.SurveyResponse) error {
 		Set("response", questionResponseJSON).
 		Set("create_at", response.CreateAt).
 		Set("response_type", response.ResponseType).
+		Set("locale", response.Locale).
+		Set("metadata", metadataJSON).
+		Set("idempotency_key", response.IdempotencyKey).
+		Set("completed_at", response.CompletedAt).
+		Set("sentiment", sentimentJSON).
+		Set("redaction_count", response.RedactionCount).
+		Where(sq.Eq{"id": response.ID}).
+		RunWith(tx).
+		Exec()
+
+	if err != nil {
+		s.pluginAPI.LogError("updateSurveyResponse: failed to update survey response in database", "response_id", response.ID, "error", err.Error())
+		return errors.Wrap(err, "updateSurveyResponse: failed to update survey response in database")
+	}
+
+	return nil
+}
+
+// editSurveyResponse updates a complete response, saving its previous version as a revision.
+func (s *SQLStore) editSurveyResponse(tx *sql.Tx, response *model.SurveyResponse, revision *model.SurveyResponseRevision) error {
+	if revision == nil {
+		return errors.New("editSurveyResponse: a revision is required to edit a complete survey response")
+	}
+
+	if err := s.lockSurveyResponse(tx, response.ID, model.ResponseTypeComplete); err != nil {
+		return errors.Wrap(err, "editSurveyResponse: failed to lock survey response")
+	}
+
+	questionResponseJSON, err := s.MarshalJSONB(response.Response)
+	if err != nil {
+		s.pluginAPI.LogError("editSurveyResponse: failed to marshal response map", "error", err.Error())
+		return errors.Wrap(err, "editSurveyResponse: failed to marshal response map")
+	}
+
+	metadataJSON, err := s.MarshalJSONB(response.Metadata)
+	if err != nil {
+		s.pluginAPI.LogError("editSurveyResponse: failed to marshal response metadata", "error", err.Error())
+		return errors.Wrap(err, "editSurveyResponse: failed to marshal response metadata")
+	}
+
+	sentimentJSON, err := s.MarshalJSONB(response.Sentiment)
+	if err != nil {
+		s.pluginAPI.LogError("editSurveyResponse: failed to marshal response sentiment", "error", err.Error())
+		return errors.Wrap(err, "editSurveyResponse: failed to marshal response sentiment")
+	}
+
+	revisionResponseJSON, err := s.MarshalJSONB(revision.Response)
+	if err != nil {
+		s.pluginAPI.LogError("editSurveyResponse: failed to marshal revision response map", "error", err.Error())
+		return errors.Wrap(err, "editSurveyResponse: failed to marshal revision response map")
+	}
+
+	_, err = s.getQueryBuilder().
+		Insert(s.tablePrefix+"survey_response_revisions").
+		Columns(s.surveyResponseRevisionColumns()...).
+		Values(
+			revision.ID,
+			revision.ResponseID,
+			revision.SurveyID,
+			revisionResponseJSON,
+			revision.Locale,
+			revision.CreateAt,
+		).
+		RunWith(tx).
+		Exec()
+
+	if err != nil {
+		s.pluginAPI.LogError("editSurveyResponse: failed to save survey response revision in database", "responseID", response.ID, "error", err.Error())
+		return errors.Wrap(err, "editSurveyResponse: failed to save survey response revision in database")
+	}
+
+	_, err = s.getQueryBuilder().
+		Update(s.tablePrefix+"survey_responses").
+		Set("response", questionResponseJSON).
+		Set("update_at", response.UpdateAt).
+		Set("locale", response.Locale).
+		Set("metadata", metadataJSON).
+		Set("idempotency_key", response.IdempotencyKey).
+		Set("sentiment", sentimentJSON).
+		Set("redaction_count", response.RedactionCount).
+		Where(sq.Eq{"id": response.ID}).
+		RunWith(tx).
+		Exec()
+
+	if err != nil {
+		s.pluginAPI.LogError("editSurveyResponse: failed to update survey response in database", "responseID", response.ID, "error", err.Error())
+		return errors.Wrap(err, "editSurveyResponse: failed to update survey response in database")
+	}
+
+	return nil
+}
+
+// lockSurveyResponse locks the response's row for the rest of the transaction,
+// failing with ErrSurveyResponseConflict if the response is no longer of the expected type.
+func (s *SQLStore) lockSurveyResponse(tx *sql.Tx, responseID, expectedResponseType string) error {
+	var responseType string
+	err := s.getQueryBuilder().
+		Select("response_type").
+		From(s.tablePrefix + "survey_responses").
+		Where(sq.Eq{"id": responseID}).
+		Suffix("FOR UPDATE").
+		RunWith(tx).
+		QueryRow().
+		Scan(&responseType)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrSurveyResponseConflict
+	}
+
+	if err != nil {
+		s.pluginAPI.LogError("lockSurveyResponse: failed to lock survey response row", "responseID", responseID, "error", err.Error())
+		return errors.Wrap(err, "lockSurveyResponse: failed to lock survey response row")
+	}
+
+	if responseType != expectedResponseType {
+		return ErrSurveyResponseConflict
+	}
+
+	return nil
+}
+
+func (s *SQLStore) GetSurveyResponseRevisions(surveyID, responseID string) ([]*model.SurveyResponseRevision, error) {
+	rows, err := s.getQueryBuilder().
+		Select(s.surveyResponseRevisionColumns()...).
+		From(s.tablePrefix + "survey_response_revisions").
 		Where(sq.Eq{
-			"id":            response.ID,
-			"response_type": model.ResponseTypePartial,
-		}).Exec()
+			"survey_id":   surveyID,
+			"response_id": responseID,
+		}).
+		OrderBy("create_at").
+		Query()
+
+	if err != nil {
+		s.pluginAPI.LogError("GetSurveyResponseRevisions: failed to query survey response revisions from database", "surveyID", surveyID, "responseID", responseID, "error", err.Error())
+		return nil, errors.Wrap(err, "GetSurveyResponseRevisions: failed to query survey response revisions from database")
+	}
 
+	revisions, err := s.surveyResponseRevisionsFromRows(rows)
 	if err != nil {
-		s.pluginAPI.LogError("UpdateSurveyResponse: failed to update survey response in database", "response_id", response.ID, "error", err.Error())
-		return errors.Wrap(err, "UpdateSurveyResponse: failed to update survey response in database")
+		return nil, errors.Wrap(err, "GetSurveyResponseRevisions: failed to get survey response revisions from rows")
+	}
+
+	return revisions, nil
+}
+
+// GetAllResponseRevisions returns a page of the revisions of all responses to the survey.
+func (s *SQLStore) GetAllResponseRevisions(surveyID, lastRevisionID string, perPage uint64) ([]*model.SurveyResponseRevision, error) {
+	query := s.getQueryBuilder().
+		Select(s.surveyResponseRevisionColumns()...).
+		From(s.tablePrefix + "survey_response_revisions").
+		Where(sq.Eq{"survey_id": surveyID}).
+		OrderBy("id").
+		Limit(perPage)
+
+	if lastRevisionID != "" {
+		query = query.Where(sq.Gt{"id": lastRevisionID})
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.pluginAPI.LogError("GetAllResponseRevisions: failed to query a page", "surveyID", surveyID, "lastRevisionID", lastRevisionID, "perPage", perPage, "error", err.Error())
+		return nil, errors.Wrap(err, "GetAllResponseRevisions: failed to query a page")
+	}
+
+	revisions, err := s.surveyResponseRevisionsFromRows(rows)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetAllResponseRevisions: failed to get survey response revisions from rows")
+	}
+
+	return revisions, nil
+}
+
+func (s *SQLStore) surveyResponseRevisionsFromRows(rows *sql.Rows) ([]*model.SurveyResponseRevision, error) {
+	defer rows.Close()
+
+	revisions := []*model.SurveyResponseRevision{}
+	for rows.Next() {
+		var revision model.SurveyResponseRevision
+		var responseString string
+
+		err := rows.Scan(
+			&revision.ID,
+			&revision.ResponseID,
+			&revision.SurveyID,
+			&responseString,
+			&revision.Locale,
+			&revision.CreateAt,
+		)
+		if err != nil {
+			s.pluginAPI.LogError("surveyResponseRevisionsFromRows: failed to scan row", "error", err.Error())
+			return nil, errors.Wrap(err, "surveyResponseRevisionsFromRows: failed to scan row")
+		}
+
+		if err := json.Unmarshal([]byte(responseString), &revision.Response); err != nil {
+			s.pluginAPI.LogError("surveyResponseRevisionsFromRows: failed to unmarshal response string", "error", err.Error())
+			return nil, errors.Wrap(err, "surveyResponseRevisionsFromRows: failed to unmarshal response string")
+		}
+
+		revisions = append(revisions, &revision)
+	}
+
+	return revisions, nil
+}
+
+// UpdateSurveyResponseRevisionAnswers replaces the answers of the revision, such as when re-encrypting them.
+func (s *SQLStore) UpdateSurveyResponseRevisionAnswers(revisionID string, answers map[string]string) error {
+	answersJSON, err := s.MarshalJSONB(answers)
+	if err != nil {
+		s.pluginAPI.LogError("UpdateSurveyResponseRevisionAnswers: failed to marshal answers", "error", err.Error())
+		return errors.Wrap(err, "UpdateSurveyResponseRevisionAnswers: failed to marshal answers")
+	}
+
+	_, err = s.getQueryBuilder().
+		Update(s.tablePrefix+"survey_response_revisions").
+		Set("response", answersJSON).
+		Where(sq.Eq{"id": revisionID}).
+		Exec()
+
+	if err != nil {
+		s.pluginAPI.LogError("UpdateSurveyResponseRevisionAnswers: failed to update survey response revision", "revisionID", revisionID, "error", err.Error())
+		return errors.Wrap(err, "UpdateSurveyResponseRevisionAnswers: failed to update survey response revision")
 	}
 
 	return nil
 }
 
+// UpdateSurveyResponseAnswers replaces the answers of the response, such as when re-encrypting them.
+// The answers are only replaced if the response hasn't been updated since it was read,
+// otherwise false is returned, so newer answers aren't overwritten.
+func (s *SQLStore) UpdateSurveyResponseAnswers(response *model.SurveyResponse, answers map[string]string) (bool, error) {
+	answersJSON, err := s.MarshalJSONB(answers)
+	if err != nil {
+		s.pluginAPI.LogError("UpdateSurveyResponseAnswers: failed to marshal answers", "error", err.Error())
+		return false, errors.Wrap(err, "UpdateSurveyResponseAnswers: failed to marshal answers")
+	}
+
+	result, err := s.getQueryBuilder().
+		Update(s.tablePrefix+"survey_responses").
+		Set("response", answersJSON).
+		Where(sq.Eq{
+			"id":              response.ID,
+			"response_type":   response.ResponseType,
+			"update_at":       response.UpdateAt,
+			"idempotency_key": response.IdempotencyKey,
+		}).
+		Exec()
+
+	if err != nil {
+		s.pluginAPI.LogError("UpdateSurveyResponseAnswers: failed to update survey response", "responseID", response.ID, "error", err.Error())
+		return false, errors.Wrap(err, "UpdateSurveyResponseAnswers: failed to update survey response")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		s.pluginAPI.LogError("UpdateSurveyResponseAnswers: failed to get affected rows", "responseID", response.ID, "error", err.Error())
+		return false, errors.Wrap(err, "UpdateSurveyResponseAnswers: failed to get affected rows")
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (s *SQLStore) surveyResponseRevisionColumns() []string {
+	return []string{
+		"id",
+		"response_id",
+		"survey_id",
+		"response",
+		"locale",
+		"create_at",
+	}
+}
+
+// WithdrawSurveyResponse deletes the response along with its revisions and applies the changes
+// deleting it makes to the survey counters and question answer counts in a single transaction.
+// It returns false without changing the counters if the response was already deleted.
+func (s *SQLStore) WithdrawSurveyResponse(withdrawal *model.SurveyResponseWithdrawal) (bool, error) {
+	responseID := withdrawal.Response.ID
+	surveyID := withdrawal.Response.SurveyID
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.pluginAPI.LogError("WithdrawSurveyResponse: failed to begin transaction", "error", err.Error())
+		return false, errors.Wrap(err, "WithdrawSurveyResponse: failed to begin transaction")
+	}
+
+	defer s.finalizeTransaction(tx)
+
+	_, err = s.getQueryBuilder().
+		Delete(s.tablePrefix + "survey_response_revisions").
+		Where(sq.Eq{"response_id": responseID}).
+		RunWith(tx).
+		Exec()
+
+	if err != nil {
+		s.pluginAPI.LogError("WithdrawSurveyResponse: failed to delete survey response revisions from database", "responseID", responseID, "error", err.Error())
+		return false, errors.Wrap(err, "WithdrawSurveyResponse: failed to delete survey response revisions from database")
+	}
+
+	result, err := s.getQueryBuilder().
+		Delete(s.tablePrefix + "survey_responses").
+		Where(sq.Eq{"id": responseID}).
+		RunWith(tx).
+		Exec()
+
+	if err != nil {
+		s.pluginAPI.LogError("WithdrawSurveyResponse: failed to delete survey response from database", "responseID", responseID, "error", err.Error())
+		return false, errors.Wrap(err, "WithdrawSurveyResponse: failed to delete survey response from database")
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		s.pluginAPI.LogError("WithdrawSurveyResponse: failed to get deleted row count", "responseID", responseID, "error", err.Error())
+		return false, errors.Wrap(err, "WithdrawSurveyResponse: failed to get deleted row count")
+	}
+
+	if deleted == 0 {
+		return false, nil
+	}
+
+	if err := s.updateSurveyCounts(tx, surveyID, withdrawal.Counts); err != nil {
+		return false, errors.Wrap(err, "WithdrawSurveyResponse: failed to update survey counts")
+	}
+
+	if err := s.updateQuestionAnswerCounts(tx, surveyID, withdrawal.AnswerCounts); err != nil {
+		return false, errors.Wrap(err, "WithdrawSurveyResponse: failed to update question answer counts")
+	}
+
+	if err := s.updateSegmentCounts(tx, surveyID, withdrawal.SegmentCounts); err != nil {
+		return false, errors.Wrap(err, "WithdrawSurveyResponse: failed to update segment counts")
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.pluginAPI.LogError("WithdrawSurveyResponse: failed to commit transaction", "responseID", responseID, "error", err.Error())
+		return false, errors.Wrap(err, "WithdrawSurveyResponse: failed to commit transaction")
+	}
+
+	return true, nil
+}
+
 func (s *SQLStore) GetSurveyResponse(userID, surveyID string) (*model.SurveyResponse, error) {
 	rows, err := s.getQueryBuilder().
 		Select(s.surveyResponseColumns()...).
@@ -104,6 +512,13 @@ func (s *SQLStore) surveyResponseColumns() []string {
 		"response",
 		"create_at",
 		"response_type",
+		"locale",
+		"update_at",
+		"metadata",
+		"idempotency_key",
+		"completed_at",
+		"sentiment",
+		"redaction_count",
 	}
 }
 
@@ -113,6 +528,8 @@ func (s *SQLStore) surveyResponsesFromRows(rows *sql.Rows) ([]*model.SurveyRespo
 	for rows.Next() {
 		var surveyResponse model.SurveyResponse
 		var responseString string
+		var metadataString string
+		var sentimentString string
 
 		err := rows.Scan(
 			&surveyResponse.ID,
@@ -121,6 +538,13 @@ func (s *SQLStore) surveyResponsesFromRows(rows *sql.Rows) ([]*model.SurveyRespo
 			&responseString,
 			&surveyResponse.CreateAt,
 			&surveyResponse.ResponseType,
+			&surveyResponse.Locale,
+			&surveyResponse.UpdateAt,
+			&metadataString,
+			&surveyResponse.IdempotencyKey,
+			&surveyResponse.CompletedAt,
+			&sentimentString,
+			&surveyResponse.RedactionCount,
 		)
 
 		if err != nil {
@@ -134,6 +558,18 @@ func (s *SQLStore) surveyResponsesFromRows(rows *sql.Rows) ([]*model.SurveyRespo
 			return nil, errors.Wrap(err, "surveyResponsesFromRows: failed to unmarshal response string")
 		}
 
+		err = json.Unmarshal([]byte(metadataString), &surveyResponse.Metadata)
+		if err != nil {
+			s.pluginAPI.LogError("surveyResponsesFromRows: failed to unmarshal response metadata", "error", err.Error())
+			return nil, errors.Wrap(err, "surveyResponsesFromRows: failed to unmarshal response metadata")
+		}
+
+		err = json.Unmarshal([]byte(sentimentString), &surveyResponse.Sentiment)
+		if err != nil {
+			s.pluginAPI.LogError("surveyResponsesFromRows: failed to unmarshal response sentiment", "error", err.Error())
+			return nil, errors.Wrap(err, "surveyResponsesFromRows: failed to unmarshal response sentiment")
+		}
+
 		surveyResponses = append(surveyResponses, &surveyResponse)
 	}
 
@@ -167,3 +603,38 @@ func (s *SQLStore) GetAllResponses(surveyID, lastResponseID string, perPage uint
 
 	return surveyResponses, nil
 }
+
+func (s *SQLStore) GetResponseCountByLocale(surveyID string) (map[string]int64, error) {
+	rows, err := s.getQueryBuilder().
+		Select("locale", "COUNT(*)").
+		From(s.tablePrefix + "survey_responses").
+		Where(sq.Eq{"survey_id": surveyID}).
+		GroupBy("locale").
+		Query()
+
+	if err != nil {
+		s.pluginAPI.LogError("GetResponseCountByLocale: failed to query response count by locale", "surveyID", surveyID, "error", err.Error())
+		return nil, errors.Wrap(err, "GetResponseCountByLocale: failed to query response count by locale")
+	}
+
+	defer rows.Close()
+
+	counts := map[string]int64{}
+	for rows.Next() {
+		var locale string
+		var count int64
+		if err := rows.Scan(&locale, &count); err != nil {
+			s.pluginAPI.LogError("GetResponseCountByLocale: failed to scan row", "error", err.Error())
+			return nil, errors.Wrap(err, "GetResponseCountByLocale: failed to scan row")
+		}
+
+		counts[locale] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		s.pluginAPI.LogError("GetResponseCountByLocale: failed to iterate rows", "surveyID", surveyID, "error", err.Error())
+		return nil, errors.Wrap(err, "GetResponseCountByLocale: failed to iterate rows")
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,288 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+	"github.com/mattermost/mattermost-plugin-user-survey/server/utils"
+)
+
+var (
+	testTeamA = model.ResponseTeam{ID: "team_a", DisplayName: "Team A"}
+	testTeamB = model.ResponseTeam{ID: "team_b", DisplayName: "Team B"}
+)
+
+// createTestSurvey saves an in progress survey with a system rating question "rating" and a text question "text".
+func createTestSurvey(t *testing.T, sqlStore *SQLStore) *model.Survey {
+	survey := &model.Survey{
+		ID:        utils.NewID(),
+		CreateAt:  1000,
+		UpdateAt:  1000,
+		StartTime: 1000,
+		Duration:  7,
+		Status:    model.SurveyStatusInProgress,
+		SurveyQuestions: model.SurveyQuestions{
+			Questions: []model.Question{
+				{ID: "rating", System: true, Type: model.QuestionTypeLinearScale},
+				{ID: "text", Type: model.QuestionType},
+			},
+		},
+	}
+
+	require.NoError(t, sqlStore.SaveSurvey(survey))
+	return survey
+}
+
+func newTestResponse(survey *model.Survey, rating, text string, teams ...model.ResponseTeam) *model.SurveyResponse {
+	response := &model.SurveyResponse{
+		ID:           utils.NewID(),
+		UserID:       utils.NewID(),
+		SurveyID:     survey.ID,
+		Response:     map[string]string{"rating": rating},
+		CreateAt:     2000,
+		CompletedAt:  2000,
+		ResponseType: model.ResponseTypeComplete,
+		Locale:       "en",
+		Metadata:     model.ResponseMetadata{Teams: teams},
+		Sentiment:    map[string]float64{},
+	}
+
+	if text != "" {
+		response.Response["text"] = text
+		response.Sentiment["text"] = model.ScoreSentiment(text)
+	}
+
+	return response
+}
+
+// newTestResponseSave builds the save of the response replacing the existing one, with the changes it makes to the counts.
+func newTestResponseSave(survey *model.Survey, existing, response *model.SurveyResponse) *model.SurveyResponseSave {
+	ratingQuestion, _ := survey.GetSystemRatingQuestion()
+
+	var oldCounts, newCounts model.SurveyCounts
+	if existing != nil {
+		oldCounts.AddResponse(existing, ratingQuestion)
+	}
+	newCounts.AddResponse(response, ratingQuestion)
+
+	save := &model.SurveyResponseSave{
+		Response: response,
+		Existing: existing,
+		Counts: model.SurveyCountsDelta{
+			Responses:  int(newCounts.Responses - oldCounts.Responses),
+			Completed:  int(newCounts.Completed - oldCounts.Completed),
+			Promoters:  int(newCounts.Promoters - oldCounts.Promoters),
+			Passives:   int(newCounts.Passives - oldCounts.Passives),
+			Detractors: int(newCounts.Detractors - oldCounts.Detractors),
+		},
+		AnswerCounts:  model.GetQuestionAnswerCountDeltas(survey.SurveyQuestions.Questions, existing, response),
+		SegmentCounts: model.GetSegmentCountDeltas(survey, existing, response),
+	}
+
+	if existing != nil && existing.ResponseType == model.ResponseTypeComplete {
+		save.Revision = model.NewSurveyResponseRevision(existing)
+	}
+
+	return save
+}
+
+func newTestSegmentCounts(survey *model.Survey, responses ...*model.SurveyResponse) model.SurveySegmentCounts {
+	ratingQuestion, _ := survey.GetSystemRatingQuestion()
+
+	segmentCounts := model.SurveySegmentCounts{}
+	for _, response := range responses {
+		segmentCounts.AddResponse(ratingQuestion, response, 1)
+	}
+
+	return segmentCounts
+}
+
+func requireSurveyCounts(t *testing.T, sqlStore *SQLStore, surveyID string, expected model.SurveyCounts) {
+	stat, err := sqlStore.GetSurveyStat(surveyID)
+	require.NoError(t, err)
+	require.Equal(t, expected, model.SurveyCounts{
+		Receipts:   stat.ReceiptCount,
+		Opened:     stat.OpenedCount,
+		Responses:  stat.ResponseCount,
+		Completed:  stat.CompletedCount,
+		Promoters:  stat.PromoterCount,
+		Passives:   stat.PassiveCount,
+		Detractors: stat.DetractorCount,
+	})
+}
+
+func TestSurveyResponses(t *testing.T) {
+	tests := []StoreTests{
+		testSurveyResponses,
+	}
+
+	testWithSupportedDatabases(t, tests)
+}
+
+func testSurveyResponses(t *testing.T, namePrefix string, sqlStore *SQLStore, tearDown func()) {
+	defer tearDown()
+
+	t.Run(namePrefix+" should save a new response along with its counts", func(t *testing.T) {
+		survey := createTestSurvey(t, sqlStore)
+		response := newTestResponse(survey, "9", "Great app", testTeamA)
+
+		require.NoError(t, sqlStore.SaveSurveyResponse(newTestResponseSave(survey, nil, response)))
+
+		saved, err := sqlStore.GetSurveyResponse(response.UserID, survey.ID)
+		require.NoError(t, err)
+		require.Equal(t, response.Response, saved.Response)
+		require.Equal(t, response.Metadata.Teams, saved.Metadata.Teams)
+		require.Equal(t, response.Sentiment, saved.Sentiment)
+
+		requireSurveyCounts(t, sqlStore, survey.ID, model.SurveyCounts{Responses: 1, Completed: 1, Promoters: 1})
+
+		answerCounts, err := sqlStore.GetQuestionAnswerCounts(survey.ID, []string{"rating", "text"})
+		require.NoError(t, err)
+		require.Equal(t, map[string]int64{"rating": 1, "text": 1}, answerCounts)
+
+		ratingCounts, err := sqlStore.GetQuestionAnswerValueCounts(survey.ID, "rating")
+		require.NoError(t, err)
+		require.Equal(t, map[string]int64{"9": 1}, ratingCounts)
+
+		segmentCounts, err := sqlStore.GetSurveySegmentCounts(survey.ID)
+		require.NoError(t, err)
+		require.True(t, newTestSegmentCounts(survey, response).Equal(segmentCounts))
+		require.Equal(t, "Team A", segmentCounts[model.SegmentKey{Type: model.SegmentTypeTeam, ID: "team_a"}].TeamName)
+	})
+
+	t.Run(namePrefix+" should move the counts of an edited response and keep its previous version", func(t *testing.T) {
+		survey := createTestSurvey(t, sqlStore)
+		response := newTestResponse(survey, "9", "Great app", testTeamA)
+		require.NoError(t, sqlStore.SaveSurveyResponse(newTestResponseSave(survey, nil, response)))
+
+		existing, err := sqlStore.GetSurveyResponse(response.UserID, survey.ID)
+		require.NoError(t, err)
+
+		renamedTeamA := model.ResponseTeam{ID: "team_a", DisplayName: "Team A renamed"}
+		edited := newTestResponse(survey, "2", "Slow and confusing", renamedTeamA)
+		edited.ID, edited.UserID, edited.UpdateAt = existing.ID, existing.UserID, 3000
+		require.NoError(t, sqlStore.SaveSurveyResponse(newTestResponseSave(survey, existing, edited)))
+
+		revisions, err := sqlStore.GetSurveyResponseRevisions(survey.ID, response.ID)
+		require.NoError(t, err)
+		require.Len(t, revisions, 1)
+		require.Equal(t, existing.Response, revisions[0].Response)
+
+		requireSurveyCounts(t, sqlStore, survey.ID, model.SurveyCounts{Responses: 1, Completed: 1, Detractors: 1})
+
+		ratingCounts, err := sqlStore.GetQuestionAnswerValueCounts(survey.ID, "rating")
+		require.NoError(t, err)
+		require.Equal(t, map[string]int64{"2": 1}, ratingCounts)
+
+		segmentCounts, err := sqlStore.GetSurveySegmentCounts(survey.ID)
+		require.NoError(t, err)
+		require.True(t, newTestSegmentCounts(survey, edited).Equal(segmentCounts))
+		require.Equal(t, "Team A renamed", segmentCounts[model.SegmentKey{Type: model.SegmentTypeTeam, ID: "team_a"}].TeamName)
+	})
+
+	t.Run(namePrefix+" should roll back the counts of a save that conflicts with the stored response", func(t *testing.T) {
+		survey := createTestSurvey(t, sqlStore)
+		response := newTestResponse(survey, "9", "Great app", testTeamA)
+		require.NoError(t, sqlStore.SaveSurveyResponse(newTestResponseSave(survey, nil, response)))
+
+		// the response was completed meanwhile, so it can't be updated as a partial response
+		stale := *response
+		stale.ResponseType = model.ResponseTypePartial
+		update := newTestResponse(survey, "3", "", testTeamA)
+		update.ID, update.UserID = response.ID, response.UserID
+
+		err := sqlStore.SaveSurveyResponse(newTestResponseSave(survey, &stale, update))
+		require.ErrorIs(t, err, ErrSurveyResponseConflict)
+
+		requireSurveyCounts(t, sqlStore, survey.ID, model.SurveyCounts{Responses: 1, Completed: 1, Promoters: 1})
+
+		ratingCounts, err := sqlStore.GetQuestionAnswerValueCounts(survey.ID, "rating")
+		require.NoError(t, err)
+		require.Equal(t, map[string]int64{"9": 1}, ratingCounts)
+
+		segmentCounts, err := sqlStore.GetSurveySegmentCounts(survey.ID)
+		require.NoError(t, err)
+		require.True(t, newTestSegmentCounts(survey, response).Equal(segmentCounts))
+	})
+
+	t.Run(namePrefix+" should discount a withdrawn response once", func(t *testing.T) {
+		survey := createTestSurvey(t, sqlStore)
+		response := newTestResponse(survey, "7", "Okay", testTeamA, testTeamB)
+		other := newTestResponse(survey, "10", "", testTeamB)
+		require.NoError(t, sqlStore.SaveSurveyResponse(newTestResponseSave(survey, nil, response)))
+		require.NoError(t, sqlStore.SaveSurveyResponse(newTestResponseSave(survey, nil, other)))
+
+		withdrawal := &model.SurveyResponseWithdrawal{
+			Response:      response,
+			Counts:        model.SurveyCountsDelta{Responses: -1, Completed: -1, Passives: -1},
+			AnswerCounts:  model.GetQuestionAnswerCountDeltas(survey.SurveyQuestions.Questions, response, nil),
+			SegmentCounts: model.GetSegmentCountDeltas(survey, response, nil),
+		}
+
+		withdrawn, err := sqlStore.WithdrawSurveyResponse(withdrawal)
+		require.NoError(t, err)
+		require.True(t, withdrawn)
+
+		// a concurrent withdrawal that lost the race doesn't discount the response again
+		withdrawn, err = sqlStore.WithdrawSurveyResponse(withdrawal)
+		require.NoError(t, err)
+		require.False(t, withdrawn)
+
+		saved, err := sqlStore.GetSurveyResponse(response.UserID, survey.ID)
+		require.NoError(t, err)
+		require.Nil(t, saved)
+
+		requireSurveyCounts(t, sqlStore, survey.ID, model.SurveyCounts{Responses: 1, Completed: 1, Promoters: 1})
+
+		answerCounts, err := sqlStore.GetQuestionAnswerCounts(survey.ID, []string{"rating", "text"})
+		require.NoError(t, err)
+		require.Equal(t, map[string]int64{"rating": 1, "text": 0}, answerCounts)
+
+		segmentCounts, err := sqlStore.GetSurveySegmentCounts(survey.ID)
+		require.NoError(t, err)
+		require.True(t, newTestSegmentCounts(survey, other).Equal(segmentCounts))
+	})
+
+	t.Run(namePrefix+" should count responses saved concurrently once each", func(t *testing.T) {
+		survey := createTestSurvey(t, sqlStore)
+
+		responses := []*model.SurveyResponse{}
+		for _, rating := range []string{"10", "9", "8", "7", "6", "5", "4", "3"} {
+			responses = append(responses, newTestResponse(survey, rating, "Great app", testTeamA, testTeamB))
+		}
+
+		var wg sync.WaitGroup
+		errs := make([]error, len(responses))
+		for i, response := range responses {
+			wg.Add(1)
+			go func(i int, response *model.SurveyResponse) {
+				defer wg.Done()
+				errs[i] = sqlStore.SaveSurveyResponse(newTestResponseSave(survey, nil, response))
+			}(i, response)
+		}
+		wg.Wait()
+
+		for _, err := range errs {
+			require.NoError(t, err)
+		}
+
+		requireSurveyCounts(t, sqlStore, survey.ID, model.SurveyCounts{Responses: 8, Completed: 8, Promoters: 2, Passives: 2, Detractors: 4})
+
+		rebuilt, err := sqlStore.ReconcileQuestionAnswerCounts(survey.ID, survey.SurveyQuestions.Questions)
+		require.NoError(t, err)
+		require.False(t, rebuilt)
+
+		ratingQuestion, err := survey.GetSystemRatingQuestion()
+		require.NoError(t, err)
+
+		rebuilt, err = sqlStore.ReconcileSegmentCounts(survey.ID, ratingQuestion)
+		require.NoError(t, err)
+		require.False(t, rebuilt)
+	})
+}
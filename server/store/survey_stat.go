@@ -62,6 +62,40 @@ func (s *SQLStore) GetSurveyStat(surveyID string) (*model.SurveyStat, error) {
 	return surveyStats[0], nil
 }
 
+// GetEndedSurveyStats returns a page of the stats of the ended surveys, oldest survey first.
+// If questionID is set, only the surveys with the question are returned.
+func (s *SQLStore) GetEndedSurveyStats(questionID string, offset, limit uint64) ([]*model.SurveyStat, error) {
+	query := s.getQueryBuilder().
+		Select(s.surveyStatColumns()...).
+		From(s.tablePrefix+"survey").
+		Where(sq.Eq{"status": model.SurveyStatusEnded}).
+		OrderBy("start_time ASC", "id ASC").
+		Offset(offset).
+		Limit(limit)
+
+	if questionID != "" {
+		questionJSON, err := json.Marshal([]map[string]string{{"id": questionID}})
+		if err != nil {
+			s.pluginAPI.LogError("GetEndedSurveyStats: failed to marshal question filter", "questionID", questionID, "error", err.Error())
+			return nil, errors.Wrap(err, "GetEndedSurveyStats: failed to marshal question filter")
+		}
+
+		if s.dbType == model.DBTypeMySQL {
+			query = query.Where("JSON_CONTAINS(questions, ?, '$.questions')", string(questionJSON))
+		} else {
+			query = query.Where("questions -> 'questions' @> ?::jsonb", string(questionJSON))
+		}
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.pluginAPI.LogError("GetEndedSurveyStats: failed to query survey stats from database", "questionID", questionID, "offset", offset, "limit", limit, "error", err.Error())
+		return nil, errors.Wrap(err, "GetEndedSurveyStats: failed to query survey stats from database")
+	}
+
+	return s.surveyStatsFromRows(rows)
+}
+
 func (s *SQLStore) surveyStatColumns() []string {
 	surveyStateColumns := []string{
 		"receipt_count",
@@ -69,6 +103,9 @@ func (s *SQLStore) surveyStatColumns() []string {
 		"passives_count",
 		"promoters_count",
 		"detractors_count",
+		"opened_count",
+		"completed_count",
+		"redaction_count",
 	}
 
 	return append(s.surveyColumns(), surveyStateColumns...)
@@ -81,6 +118,8 @@ func (s *SQLStore) surveyStatsFromRows(rows *sql.Rows) ([]*model.SurveyStat, err
 		var surveyStat model.SurveyStat
 		var excludedTeamIDsJSON string
 		var questionsJSON string
+		var customizationJSON string
+		var anonymityJSON string
 
 		err := rows.Scan(
 			&surveyStat.ID,
@@ -92,11 +131,17 @@ func (s *SQLStore) surveyStatsFromRows(rows *sql.Rows) ([]*model.SurveyStat, err
 			&questionsJSON,
 			&surveyStat.Status,
 			&surveyStat.TeamFilterType,
+			&customizationJSON,
+			&anonymityJSON,
+			&surveyStat.ResponsesEditable,
 			&surveyStat.ReceiptCount,
 			&surveyStat.ResponseCount,
 			&surveyStat.PassiveCount,
 			&surveyStat.PromoterCount,
 			&surveyStat.DetractorCount,
+			&surveyStat.OpenedCount,
+			&surveyStat.CompletedCount,
+			&surveyStat.RedactionCount,
 		)
 		if err != nil {
 			s.pluginAPI.LogError("surveyStatsFromRows: failed to scan survey stat row", "error", err.Error())
@@ -115,6 +160,18 @@ func (s *SQLStore) surveyStatsFromRows(rows *sql.Rows) ([]*model.SurveyStat, err
 			return nil, errors.Wrap(err, "surveyStatsFromRows: failed to unmarshal survey questions string to survey")
 		}
 
+		err = json.Unmarshal([]byte(customizationJSON), &surveyStat.Customization)
+		if err != nil {
+			s.pluginAPI.LogError("surveyStatsFromRows: failed to unmarshal survey customization string to survey", "error", err.Error())
+			return nil, errors.Wrap(err, "surveyStatsFromRows: failed to unmarshal survey customization string to survey")
+		}
+
+		err = json.Unmarshal([]byte(anonymityJSON), &surveyStat.Anonymity)
+		if err != nil {
+			s.pluginAPI.LogError("surveyStatsFromRows: failed to unmarshal survey anonymity string to survey", "error", err.Error())
+			return nil, errors.Wrap(err, "surveyStatsFromRows: failed to unmarshal survey anonymity string to survey")
+		}
+
 		surveyStats = append(surveyStats, &surveyStat)
 	}
 
@@ -0,0 +1,78 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+func TestReconcileSurveyCounts(t *testing.T) {
+	tests := []StoreTests{
+		testReconcileSurveyCounts,
+	}
+
+	testWithSupportedDatabases(t, tests)
+}
+
+func testReconcileSurveyCounts(t *testing.T, namePrefix string, sqlStore *SQLStore, tearDown func()) {
+	defer tearDown()
+
+	setupDriftedSurvey := func(t *testing.T) (*model.Survey, model.Question) {
+		survey := createTestSurvey(t, sqlStore)
+
+		for _, status := range []string{model.SurveyDeliveryStatusDelivered, model.SurveyDeliveryStatusOpened} {
+			delivery := newTestDelivery(survey.ID, status)
+			require.NoError(t, sqlStore.SaveSurveyDelivery(delivery))
+		}
+
+		// counted responses
+		for _, rating := range []string{"10", "8"} {
+			response := newTestResponse(survey, rating, "")
+			require.NoError(t, sqlStore.SaveSurveyResponse(newTestResponseSave(survey, nil, response)))
+		}
+
+		// a response saved without its counts, and a receipt counted twice
+		uncounted := newTestResponse(survey, "2", "")
+		require.NoError(t, sqlStore.SaveSurveyResponse(&model.SurveyResponseSave{Response: uncounted}))
+		for i := 0; i < 3; i++ {
+			require.NoError(t, sqlStore.IncrementSurveyReceiptCount(survey.ID))
+		}
+
+		ratingQuestion, err := survey.GetSystemRatingQuestion()
+		require.NoError(t, err)
+
+		return survey, ratingQuestion
+	}
+
+	t.Run(namePrefix+" should fix the counts that drifted", func(t *testing.T) {
+		survey, ratingQuestion := setupDriftedSurvey(t)
+
+		reconciliation, err := sqlStore.ReconcileSurveyCounts(survey.ID, ratingQuestion, true)
+		require.NoError(t, err)
+		require.True(t, reconciliation.HasDiscrepancies())
+		require.Equal(t, model.SurveyCounts{Receipts: 3, Responses: 2, Completed: 2, Promoters: 1, Passives: 1}, reconciliation.Stored)
+
+		expected := model.SurveyCounts{Receipts: 2, Opened: 1, Responses: 3, Completed: 3, Promoters: 1, Passives: 1, Detractors: 1}
+		require.Equal(t, expected, reconciliation.Actual)
+		requireSurveyCounts(t, sqlStore, survey.ID, expected)
+
+		reconciliation, err = sqlStore.ReconcileSurveyCounts(survey.ID, ratingQuestion, true)
+		require.NoError(t, err)
+		require.False(t, reconciliation.HasDiscrepancies())
+	})
+
+	t.Run(namePrefix+" should keep the receipt and opened counts until the deliveries are backfilled", func(t *testing.T) {
+		survey, ratingQuestion := setupDriftedSurvey(t)
+
+		reconciliation, err := sqlStore.ReconcileSurveyCounts(survey.ID, ratingQuestion, false)
+		require.NoError(t, err)
+		require.True(t, reconciliation.HasDiscrepancies())
+
+		requireSurveyCounts(t, sqlStore, survey.ID, model.SurveyCounts{Receipts: 3, Responses: 3, Completed: 3, Promoters: 1, Passives: 1, Detractors: 1})
+	})
+}
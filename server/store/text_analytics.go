@@ -0,0 +1,96 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-user-survey/server/model"
+)
+
+// SaveSurveyTextAnalytics saves the survey's text analytics, replacing its previous analytics.
+func (s *SQLStore) SaveSurveyTextAnalytics(analytics *model.SurveyTextAnalytics) error {
+	analyticsJSON, err := s.MarshalJSONB(analytics)
+	if err != nil {
+		s.pluginAPI.LogError("SaveSurveyTextAnalytics: failed to marshal text analytics", "surveyID", analytics.SurveyID, "error", err.Error())
+		return errors.Wrap(err, "SaveSurveyTextAnalytics: failed to marshal text analytics")
+	}
+
+	upsert := "ON CONFLICT (survey_id) DO UPDATE SET create_at = EXCLUDED.create_at, analytics = EXCLUDED.analytics"
+	if s.dbType == model.DBTypeMySQL {
+		upsert = "ON DUPLICATE KEY UPDATE create_at = VALUES(create_at), analytics = VALUES(analytics)"
+	}
+
+	_, err = s.getQueryBuilder().
+		Insert(s.tablePrefix+"survey_text_analytics").
+		Columns("survey_id", "create_at", "analytics").
+		Values(analytics.SurveyID, analytics.CreateAt, analyticsJSON).
+		Suffix(upsert).
+		Exec()
+
+	if err != nil {
+		s.pluginAPI.LogError("SaveSurveyTextAnalytics: failed to save text analytics", "surveyID", analytics.SurveyID, "error", err.Error())
+		return errors.Wrap(err, "SaveSurveyTextAnalytics: failed to save text analytics")
+	}
+
+	return nil
+}
+
+// GetSurveyTextAnalytics returns the survey's text analytics,
+// or nil if the survey's text answers weren't analyzed yet.
+func (s *SQLStore) GetSurveyTextAnalytics(surveyID string) (*model.SurveyTextAnalytics, error) {
+	var analyticsJSON string
+
+	err := s.getQueryBuilder().
+		Select("analytics").
+		From(s.tablePrefix + "survey_text_analytics").
+		Where(sq.Eq{"survey_id": surveyID}).
+		QueryRow().
+		Scan(&analyticsJSON)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		s.pluginAPI.LogError("GetSurveyTextAnalytics: failed to get text analytics", "surveyID", surveyID, "error", err.Error())
+		return nil, errors.Wrap(err, "GetSurveyTextAnalytics: failed to get text analytics")
+	}
+
+	var analytics model.SurveyTextAnalytics
+	if err := json.Unmarshal([]byte(analyticsJSON), &analytics); err != nil {
+		s.pluginAPI.LogError("GetSurveyTextAnalytics: failed to unmarshal text analytics", "surveyID", surveyID, "error", err.Error())
+		return nil, errors.Wrap(err, "GetSurveyTextAnalytics: failed to unmarshal text analytics")
+	}
+
+	return &analytics, nil
+}
+
+// GetEndedSurveysWithoutTextAnalytics returns the ended surveys whose text answers weren't analyzed yet.
+func (s *SQLStore) GetEndedSurveysWithoutTextAnalytics() ([]*model.Survey, error) {
+	rows, err := s.getQueryBuilder().
+		Select(s.surveyColumns()...).
+		From(s.tablePrefix + "survey").
+		Where(sq.Eq{"status": model.SurveyStatusEnded}).
+		Where(fmt.Sprintf("id NOT IN (SELECT survey_id FROM %ssurvey_text_analytics)", s.tablePrefix)).
+		OrderBy("start_time ASC").
+		Query()
+
+	if err != nil {
+		s.pluginAPI.LogError("GetEndedSurveysWithoutTextAnalytics: failed to get surveys", "error", err.Error())
+		return nil, errors.Wrap(err, "GetEndedSurveysWithoutTextAnalytics: failed to get surveys")
+	}
+
+	surveys, err := s.SurveysFromRows(rows)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetEndedSurveysWithoutTextAnalytics: failed to map survey rows to surveys")
+	}
+
+	return surveys, nil
+}
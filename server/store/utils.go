@@ -4,6 +4,7 @@
 package store
 
 import (
+	"database/sql"
 	"strings"
 
 	"github.com/mattermost/squirrel"
@@ -14,6 +15,10 @@ import (
 
 var (
 	ErrUnsupportedDatabaseType = errors.New("database type is unsupported")
+
+	// ErrSurveyResponseConflict is returned when a survey response was changed
+	// by another request since it was read.
+	ErrSurveyResponseConflict = errors.New("survey response was changed by another request")
 )
 
 // replaceVars replaces instances of variable placeholders with the
@@ -50,3 +55,10 @@ func (s *SQLStore) GetSchemaName() (string, error) {
 
 	return result, nil
 }
+
+// finalizeTransaction rolls back the transaction unless it was already committed.
+func (s *SQLStore) finalizeTransaction(tx *sql.Tx) {
+	if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		s.pluginAPI.LogError("finalizeTransaction: failed to rollback transaction", "error", err.Error())
+	}
+}
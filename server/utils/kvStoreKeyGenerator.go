@@ -3,20 +3,60 @@
 
 package utils
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 const (
 	UserLockKeyPrefix = "user_lock_"
+
+	UserSurveySentStatusKeyPrefix = "user_survey_status_"
+	UserSurveyOpenedKeyPrefix     = "user_survey_opened_"
+
+	KeyPseudonymSecret = "survey_pseudonym_secret"
+
+	KeySurveyDeliveriesBackfilled     = "survey_deliveries_backfilled"
+	KeyQuestionAnswerCountsBackfilled = "question_answer_counts_backfilled"
+	KeySegmentCountsBackfilled        = "segment_counts_backfilled"
 )
 
 func KeyUserSurveySentStatus(userID, surveyID string) string {
-	return fmt.Sprintf("user_survey_status_%s_%s", userID, surveyID)
+	return UserSurveySentStatusKeyPrefix + userID + "_" + surveyID
 }
 
 func KeyUserTeamMembershipFilterCache(userID, surveyID string) string {
 	return fmt.Sprintf("user_team_filter_cache_%s_%s", userID, surveyID)
 }
 
+func KeyUserSurveyOpened(userID, surveyID string) string {
+	return UserSurveyOpenedKeyPrefix + userID + "_" + surveyID
+}
+
+// ParseUserSurveyKey extracts the user and survey IDs from a
+// per user survey key with the given prefix, such as KeyUserSurveySentStatus.
+func ParseUserSurveyKey(key, prefix string) (userID, surveyID string, ok bool) {
+	ids, found := strings.CutPrefix(key, prefix)
+	if !found {
+		return "", "", false
+	}
+
+	userID, surveyID, found = strings.Cut(ids, "_")
+	if !found || userID == "" || surveyID == "" {
+		return "", "", false
+	}
+
+	return userID, surveyID, true
+}
+
+func KeyUserSurveyOptOut(userID string) string {
+	return fmt.Sprintf("user_survey_opt_out_%s", userID)
+}
+
 func KeyUserSendSurveyLock(userID string) string {
 	return UserLockKeyPrefix + userID
 }
+
+func KeyUserSubmitResponseLock(userID string) string {
+	return UserLockKeyPrefix + "submit_response_" + userID
+}
@@ -5,15 +5,21 @@ package utils
 
 import (
 	"archive/zip"
+	"crypto/hmac"
+	"crypto/sha256"
 	"encoding/base32"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
 
 	"github.com/google/uuid"
 )
 
+// npsConfidenceZScore is the z-score of a 95% confidence level.
+const npsConfidenceZScore = 1.96
+
 var encoding = base32.NewEncoding("ybndrfg8ejkmcpqxot1uwisza345h769").WithPadding(base32.NoPadding)
 
 // NewID is a globally unique identifier.  It is a [A-Z0-9] string 26
@@ -23,6 +29,19 @@ func NewID() string {
 	return encoding.EncodeToString(newRandom()[:])
 }
 
+// NewPseudonymID derives a one-way pseudonym from the values using the secret.
+// The same secret and values always produce the same pseudonym, which has the
+// same format as the IDs generated by NewID.
+func NewPseudonymID(secret []byte, values ...string) string {
+	mac := hmac.New(sha256.New, secret)
+	for _, value := range values {
+		// the length prefix keeps ("ab", "c") and ("a", "bc") from producing the same pseudonym
+		_, _ = fmt.Fprintf(mac, "%d:%s", len(value), value)
+	}
+
+	return encoding.EncodeToString(mac.Sum(nil)[:16])
+}
+
 func newRandom() *uuid.UUID {
 	id, err := uuid.NewRandom()
 	if err != nil {
@@ -51,6 +70,62 @@ func CalculateNPS(promoters, detractors, passives int64) float64 {
 	return nps
 }
 
+// CalculateNPSStandardError returns the standard error of the NPS, in NPS points.
+// The NPS is the mean of scoring promoters as 100, passives as 0 and detractors as -100,
+// so its standard error follows from the variance of those scores.
+func CalculateNPSStandardError(promoters, detractors, passives int64) float64 {
+	totalResponses := float64(promoters + detractors + passives)
+	if totalResponses == 0 {
+		return 0.0
+	}
+
+	promoterShare := float64(promoters) / totalResponses
+	detractorShare := float64(detractors) / totalResponses
+	variance := promoterShare + detractorShare - math.Pow(promoterShare-detractorShare, 2)
+
+	return math.Sqrt(variance/totalResponses) * 100
+}
+
+// CalculateProportionStandardError returns the standard error of the percentage
+// of the responses counted, in percentage points.
+func CalculateProportionStandardError(count, totalResponses int64) float64 {
+	if totalResponses == 0 {
+		return 0.0
+	}
+
+	share := float64(count) / float64(totalResponses)
+	return math.Sqrt(share*(1-share)/float64(totalResponses)) * 100
+}
+
+// CalculateNPSMarginOfError returns the margin of error of the NPS at 95% confidence, in NPS points.
+func CalculateNPSMarginOfError(promoters, detractors, passives int64) float64 {
+	return npsConfidenceZScore * CalculateNPSStandardError(promoters, detractors, passives)
+}
+
+// CalculateNPSConfidenceInterval returns the 95% confidence interval of the NPS,
+// bounded to the range of NPS from -100 to 100.
+func CalculateNPSConfidenceInterval(promoters, detractors, passives int64) (lower, upper float64) {
+	nps := CalculateNPS(promoters, detractors, passives)
+	marginOfError := CalculateNPSMarginOfError(promoters, detractors, passives)
+
+	return math.Max(nps-marginOfError, -100), math.Min(nps+marginOfError, 100)
+}
+
+// CalculateNPSDifferencePValue returns the two-sided p-value of a z-test for the difference
+// between the NPS of two independent groups of responses, given each NPS and its standard error.
+// A small p-value means the difference is unlikely to be due to sampling noise alone.
+func CalculateNPSDifferencePValue(npsA, standardErrorA, npsB, standardErrorB float64) float64 {
+	combinedStandardError := math.Sqrt(standardErrorA*standardErrorA + standardErrorB*standardErrorB)
+	if combinedStandardError == 0 {
+		// the responses of both groups don't vary, such as a single response each, so
+		// there's no sampling noise to test the difference against
+		return 1.0
+	}
+
+	zScore := math.Abs(npsA-npsB) / combinedStandardError
+	return math.Erfc(zScore / math.Sqrt2)
+}
+
 // CreateZip creates a zip file at the specified `zipFilePath` containing the files listed in `files`.
 func CreateZip(zipFilePath string, files []string) error {
 	// Create a new zip file
@@ -14,3 +14,88 @@ func TestNewID(t *testing.T) {
 		require.Equal(t, 26, len(NewID()))
 	})
 }
+
+func TestNewPseudonymID(t *testing.T) {
+	secret := []byte("secret")
+
+	t.Run("should have the same format as generated IDs", func(t *testing.T) {
+		require.Equal(t, 26, len(NewPseudonymID(secret, "survey_id", "user_id")))
+	})
+
+	t.Run("should be deterministic", func(t *testing.T) {
+		require.Equal(t, NewPseudonymID(secret, "survey_id", "user_id"), NewPseudonymID(secret, "survey_id", "user_id"))
+	})
+
+	t.Run("should depend on the secret and all values", func(t *testing.T) {
+		pseudonym := NewPseudonymID(secret, "survey_id", "user_id")
+		require.NotEqual(t, pseudonym, NewPseudonymID([]byte("other_secret"), "survey_id", "user_id"))
+		require.NotEqual(t, pseudonym, NewPseudonymID(secret, "other_survey_id", "user_id"))
+		require.NotEqual(t, pseudonym, NewPseudonymID(secret, "survey_id", "other_user_id"))
+		require.NotEqual(t, NewPseudonymID(secret, "ab", "c"), NewPseudonymID(secret, "a", "bc"))
+	})
+}
+
+func TestParseUserSurveyKey(t *testing.T) {
+	t.Run("should extract the user and survey IDs", func(t *testing.T) {
+		userID, surveyID, ok := ParseUserSurveyKey(KeyUserSurveySentStatus("userid", "surveyid"), UserSurveySentStatusKeyPrefix)
+		require.True(t, ok)
+		require.Equal(t, "userid", userID)
+		require.Equal(t, "surveyid", surveyID)
+	})
+
+	t.Run("should not match keys with a different prefix", func(t *testing.T) {
+		_, _, ok := ParseUserSurveyKey(KeyUserSurveyOpened("userid", "surveyid"), UserSurveySentStatusKeyPrefix)
+		require.False(t, ok)
+	})
+
+	t.Run("should not match keys without both IDs", func(t *testing.T) {
+		_, _, ok := ParseUserSurveyKey(UserSurveySentStatusKeyPrefix+"userid", UserSurveySentStatusKeyPrefix)
+		require.False(t, ok)
+	})
+}
+
+func TestCalculateNPSConfidenceInterval(t *testing.T) {
+	t.Run("should narrow the margin of error as responses grow", func(t *testing.T) {
+		smallSample := CalculateNPSMarginOfError(5, 3, 2)
+		largeSample := CalculateNPSMarginOfError(500, 300, 200)
+		require.InDelta(t, 54.03, smallSample, 0.01)
+		require.InDelta(t, 5.40, largeSample, 0.01)
+	})
+
+	t.Run("should bound the interval to the NPS range", func(t *testing.T) {
+		lower, upper := CalculateNPSConfidenceInterval(3, 0, 1)
+		require.Greater(t, lower, 0.0)
+		require.Equal(t, 100.0, upper)
+	})
+
+	t.Run("should have no margin of error without responses", func(t *testing.T) {
+		require.Zero(t, CalculateNPSMarginOfError(0, 0, 0))
+	})
+}
+
+func TestCalculateNPSDifferencePValue(t *testing.T) {
+	t.Run("should not find a small swing between small samples significant", func(t *testing.T) {
+		npsA, npsB := CalculateNPS(45, 25, 30), CalculateNPS(48, 25, 27)
+		pValue := CalculateNPSDifferencePValue(npsA, CalculateNPSStandardError(45, 25, 30), npsB, CalculateNPSStandardError(48, 25, 27))
+		require.Greater(t, pValue, 0.05)
+	})
+
+	t.Run("should find a large swing between large samples significant", func(t *testing.T) {
+		npsA, npsB := CalculateNPS(450, 250, 300), CalculateNPS(550, 200, 250)
+		pValue := CalculateNPSDifferencePValue(npsA, CalculateNPSStandardError(450, 250, 300), npsB, CalculateNPSStandardError(550, 200, 250))
+		require.Less(t, pValue, 0.05)
+	})
+
+	t.Run("should not find a difference between groups without variance significant", func(t *testing.T) {
+		require.Equal(t, 1.0, CalculateNPSDifferencePValue(100, 0, 100, 0))
+
+		// a single promoter and a single detractor
+		require.Equal(t, 1.0, CalculateNPSDifferencePValue(100, CalculateNPSStandardError(1, 0, 0), -100, CalculateNPSStandardError(0, 1, 0)))
+	})
+}
+
+func TestCalculateProportionStandardError(t *testing.T) {
+	require.InDelta(t, 5.0, CalculateProportionStandardError(50, 100), 0.001)
+	require.Zero(t, CalculateProportionStandardError(100, 100))
+	require.Zero(t, CalculateProportionStandardError(0, 0))
+}
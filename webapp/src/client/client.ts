@@ -23,13 +23,14 @@ class SurveyClient extends HttpClient {
         return this.doPost(`${this.url}/connected`);
     };
 
-    submitSurveyResponse = async (surveyID: string, response: SurveyResponse) => {
+    // submissions retried with the same idempotency key are only saved once
+    submitSurveyResponse = async (surveyID: string, response: SurveyResponse, idempotencyKey: string) => {
         if (!surveyID || !ID_PATH_PATTERN.test(surveyID)) {
             return Promise.reject(new Error('invalid survey ID encountered. Survey ID should be a 26 character, lowercase alphanumeric string'));
         }
 
         const url = `${this.url}/survey/${surveyID}/response`;
-        return this.doPost(url, response);
+        return this.doPost(url, response, {'Idempotency-Key': idempotencyKey});
     };
 
     getSurveyResults = async () => {
@@ -18,6 +18,7 @@ export function useUserSurvey(post: Post) {
 
     const surveySubmitted = post.props.survey_status === 'submitted';
     const surveyExpired = post.props.survey_status === 'ended';
+    const responsesEditable = Boolean(post.props.survey_responses_editable);
 
     useEffect(() => {
         if (!post.props.survey_questions) {
@@ -67,6 +68,7 @@ export function useUserSurvey(post: Post) {
         linearScaleQuestionID,
         surveySubmitted,
         surveyExpired,
+        responsesEditable,
         setResponses,
         submittedAtDate,
         surveyExpireAtDate,
@@ -0,0 +1,24 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+import {isSurveyPostDisabled} from './editing';
+
+describe('isSurveyPostDisabled', () => {
+    test('should enable a survey that is not submitted yet', () => {
+        expect(isSurveyPostDisabled(false, false, false)).toBe(false);
+        expect(isSurveyPostDisabled(false, false, true)).toBe(false);
+    });
+
+    test('should disable a submitted response if responses are not editable', () => {
+        expect(isSurveyPostDisabled(true, false, false)).toBe(true);
+    });
+
+    test('should keep a submitted response editable while the survey is running', () => {
+        expect(isSurveyPostDisabled(true, false, true)).toBe(false);
+    });
+
+    test('should disable an ended survey', () => {
+        expect(isSurveyPostDisabled(false, true, false)).toBe(true);
+        expect(isSurveyPostDisabled(true, true, true)).toBe(true);
+    });
+});
@@ -0,0 +1,8 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// isSurveyPostDisabled returns whether the survey post's answers can no longer be changed.
+// A submitted response stays editable until the survey ends if the survey allows editing responses.
+export function isSurveyPostDisabled(surveySubmitted: boolean, surveyExpired: boolean, responsesEditable: boolean): boolean {
+    return surveyExpired || (surveySubmitted && !responsesEditable);
+}
@@ -23,8 +23,8 @@ function LinearScaleQuestion({
     responseChangeHandler,
     disabled,
     value,
-    scaleStart = 1,
-    scaleEnd = 10,
+    scaleStart = question.scale?.min ?? 1,
+    scaleEnd = question.scale?.max ?? 10,
 }: Props) {
     const [selectedValue, setSelectedValue] = useState<number | undefined>(value ? Number.parseInt(value, 10) : undefined);
 
@@ -4,11 +4,13 @@
 import client from 'client/client';
 import React, {useCallback, useEffect, useMemo, useRef, useState} from 'react';
 import {useSelector} from 'react-redux';
+import utils from 'utils/utils';
 
 import {getCurrentUser} from 'mattermost-redux/selectors/entities/users';
 
 import Button from 'components/common/button/button';
 import {useUserSurvey} from 'components/hooks/survey';
+import {isSurveyPostDisabled} from 'components/surveyPost/editing';
 import LinearScaleQuestion from 'components/surveyPost/linearScaleQuestion/linearScaleQuestion';
 import TextQuestion from 'components/surveyPost/textQuestion/textQuestion';
 
@@ -29,18 +31,22 @@ function SurveyPost({post}: CustomPostTypeComponentProps) {
     const draftResponse = useRef<SurveyResponse>();
     const [questionErrorMessages, setQuestionErrorMessages] = useState<{[key: string]: string}>({});
 
+    // kept while retrying the submission of an unchanged draft, so it isn't saved twice
+    const submissionKey = useRef<string>();
+
     const {
         questions,
         responses,
         linearScaleQuestionID,
         surveyExpired,
         surveySubmitted,
+        responsesEditable,
         setResponses,
         submittedAtDate,
         surveyExpireAtDate,
     } = useUserSurvey(post);
 
-    const disabled = surveySubmitted || surveyExpired;
+    const disabled = isSurveyPostDisabled(surveySubmitted, surveyExpired, responsesEditable);
 
     useEffect(() => {
         if (!draftResponse.current && responses) {
@@ -82,10 +88,15 @@ function SurveyPost({post}: CustomPostTypeComponentProps) {
             return {success: false, error: true};
         }
 
+        if (!submissionKey.current) {
+            submissionKey.current = utils.uuid();
+        }
+
         let success: boolean;
 
         try {
-            await client.submitSurveyResponse(post.props.survey_id, draftResponse.current);
+            await client.submitSurveyResponse(post.props.survey_id, draftResponse.current, submissionKey.current);
+            submissionKey.current = undefined;
             success = true;
         } catch (error) {
             success = false;
@@ -116,7 +127,8 @@ function SurveyPost({post}: CustomPostTypeComponentProps) {
     // this function is to submit the linear scale rating as soon as a user selects it,
     // even without pressing the submit button.
     const submitRating = useCallback(async () => {
-        if (!linearScaleQuestionID.current || !draftResponse.current) {
+        // changes to a submitted response are only saved when it's submitted again
+        if (!linearScaleQuestionID.current || !draftResponse.current || surveySubmitted) {
             return;
         }
 
@@ -128,8 +140,8 @@ function SurveyPost({post}: CustomPostTypeComponentProps) {
             },
         };
 
-        client.submitSurveyResponse(post.props.survey_id, payload);
-    }, [linearScaleQuestionID, post.props.survey_id]);
+        client.submitSurveyResponse(post.props.survey_id, payload, utils.uuid());
+    }, [linearScaleQuestionID, post.props.survey_id, surveySubmitted]);
 
     const questionResponseChangeHandler = useCallback(
         (questionID: string, response: string) => {
@@ -138,6 +150,7 @@ function SurveyPost({post}: CustomPostTypeComponentProps) {
             }
 
             draftResponse.current.response[questionID] = response;
+            submissionKey.current = undefined;
 
             // if this is the system rating question, submit response ASAP
             if (questionID === linearScaleQuestionID.current) {
@@ -228,9 +241,14 @@ function SurveyPost({post}: CustomPostTypeComponentProps) {
                 }
 
                 {
-                    disabled && !surveyExpired &&
+                    surveySubmitted && !surveyExpired &&
                     <div className='surveyMessage submitted'>
-                        {`Response submitted on ${submittedAtDate?.toLocaleDateString()}`}
+                        {
+                            submittedAtDate ? (`Response submitted on ${submittedAtDate.toLocaleDateString()}`) : ('Response submitted')
+                        }
+                        {
+                            !disabled && '. You can edit it until the survey ends.'
+                        }
                     </div>
                 }
 
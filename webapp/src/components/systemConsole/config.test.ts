@@ -0,0 +1,49 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+import {getSavedConfig, mergeSetting} from './config';
+
+import type {Config} from 'types/mattermost-webapp';
+
+describe('System Console config', () => {
+    const savedSettings = {
+        SurveyDateTime: {timestamp: 1000},
+        SurveyExpiry: {days: 30},
+        TeamFilter: {filteredTeamIDs: [], filterType: 'exclude'},
+        SurveyQuestions: {surveyMessageText: 'Message', questions: []},
+        Customization: {botDisplayName: 'Feedback'},
+        Anonymity: {enabled: true, minGroupSize: 5},
+        Redaction: {enabled: true},
+        Encryption: {enabled: true, activeKeyId: 'key1', keys: [{id: 'key1', key: 'secret'}]},
+        ResponsesEditable: true,
+    };
+
+    const config = {
+        PluginSettings: {
+            Plugins: {
+                'com.mattermost.user-survey': {
+                    systemconsolesetting: savedSettings,
+                },
+            },
+        },
+    } as unknown as Config;
+
+    test('keeps the settings the console does not render when saving a setting', () => {
+        const merged = mergeSetting(getSavedConfig(config), 'SurveyExpiry', {days: 10});
+
+        expect(merged).toEqual({
+            ...savedSettings,
+            SurveyExpiry: {days: 10},
+        });
+    });
+
+    test('does not modify the saved config', () => {
+        mergeSetting(getSavedConfig(config), 'SurveyExpiry', {days: 10});
+
+        expect(savedSettings.SurveyExpiry).toEqual({days: 30});
+    });
+
+    test('starts from an empty config when nothing is saved yet', () => {
+        expect(getSavedConfig({PluginSettings: {}} as unknown as Config)).toEqual({});
+    });
+});
@@ -0,0 +1,22 @@
+// Copyright (c) 2024-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+import type {Config} from 'types/mattermost-webapp';
+import type {CombinedConfig, CustomConfigTypes} from 'types/plugin';
+
+// getSavedConfig returns the saved plugin config. The System Console only renders some of its
+// settings, the others, such as the encryption keys, are set in config.json. Starting from the
+// saved config keeps them when the console saves the settings it renders.
+export function getSavedConfig(config: Config): CombinedConfig {
+    return {
+        ...config.PluginSettings?.Plugins?.['com.mattermost.user-survey']?.systemconsolesetting,
+    } as CombinedConfig;
+}
+
+// mergeSetting returns the config with one of its settings replaced, keeping all the other settings.
+export function mergeSetting(config: CombinedConfig, settingId: string, settings: CustomConfigTypes): CombinedConfig {
+    return {
+        ...config,
+        [settingId]: settings,
+    };
+}
@@ -4,6 +4,7 @@
 import React, {useCallback, useMemo, useState} from 'react';
 
 import Panel from 'components/common/panel/panel';
+import {getSavedConfig, mergeSetting} from 'components/systemConsole/config';
 import Expiry from 'components/systemConsole/expiry/expiry';
 import Questions from 'components/systemConsole/questions/questions';
 import SurveyDateTime from 'components/systemConsole/surveyDateTime/surveyDateTime';
@@ -23,14 +24,12 @@ export type CustomSettingChildComponentProp = CustomComponentProps & {
 function SystemConsoleSetting(props: CustomComponentProps) {
     const {id, onChange, setSaveNeeded} = props;
 
-    // This holds the combined config of all sub-configs
-    const [config, setConfig] = useState<CombinedConfig>({} as CombinedConfig);
+    // This holds the combined config of all sub-configs, starting from the saved config
+    // so the settings without a sub-config component aren't lost when saving.
+    const [config, setConfig] = useState<CombinedConfig>(() => getSavedConfig(props.config));
 
     const onChangeWrapper = useCallback((settingId: string, settings: CustomConfigTypes) => {
-        const newConfig = {
-            ...config,
-            [settingId]: settings,
-        };
+        const newConfig = mergeSetting(config, settingId, settings);
 
         setConfig(newConfig);
         onChange(id, newConfig);
@@ -46,10 +45,7 @@ function SystemConsoleSetting(props: CustomComponentProps) {
     // can save the entire setting object on server side.
     // Otherwise, the displayed default values will never get saved unless a user changes them.
     const setDefaults = useCallback((settingId: string, settings: CustomConfigTypes) => {
-        setConfig((existingConfig) => ({
-            ...existingConfig,
-            [settingId]: settings,
-        }));
+        setConfig((existingConfig) => mergeSetting(existingConfig, settingId, settings));
     }, []);
 
     const modifiedProps = useMemo((): CustomSettingChildComponentProp => ({
@@ -14,16 +14,29 @@ import './style.scss';
 export type QuestionType = 'linear_scale' | 'text';
 
 const questionTypeDisplayName = new Map<QuestionType, string>([
-    ['linear_scale', 'Linear scale question (1 to 10)'],
+    ['linear_scale', 'Linear scale question'],
     ['text', 'Textual question'],
 ]);
 
+// the range shown matches the one the survey post renders, which is 1 to 10 for questions without a scale configured
+function getQuestionDisplayName(question: Question): string {
+    const displayName = questionTypeDisplayName.get(question.type);
+    if (question.type !== 'linear_scale') {
+        return displayName || '';
+    }
+
+    return `${displayName} (${question.scale?.min ?? 1} to ${question.scale?.max ?? 10})`;
+}
+
 const DEFAULT_SURVEY_MESSAGE_TEXT = 'Please take a few moments to help us improve your experience.';
 
 function SurveyQuestions({id, setSaveNeeded, onChange, config, setInitialSetting}: CustomSettingChildComponentProp) {
     const [questions, setQuestions] = useState<Question[]>([]);
     const [surveyMessageText, setSurveyMessageText] = useState<string>();
 
+    // the saved config holds settings not edited here, such as the translations, which are kept when saving
+    const [savedQuestionConfig, setSavedQuestionConfig] = useState<Partial<SurveyQuestionsConfig>>({});
+
     const generateDefaultQuestions = (): Question[] => {
         return [
             {
@@ -59,10 +72,12 @@ function SurveyQuestions({id, setSaveNeeded, onChange, config, setInitialSetting
         const initialSurveyMessageText = questionConfig?.surveyMessageText;
 
         const initialSetting: SurveyQuestionsConfig = {
+            ...questionConfig,
             questions: initialSavedQuestions || generateDefaultQuestions(),
             surveyMessageText: initialSurveyMessageText || DEFAULT_SURVEY_MESSAGE_TEXT,
         };
 
+        setSavedQuestionConfig(questionConfig || {});
         setQuestions(initialSetting.questions);
         setSurveyMessageText(initialSetting.surveyMessageText);
         setInitialSetting(id, initialSetting);
@@ -70,8 +85,11 @@ function SurveyQuestions({id, setSaveNeeded, onChange, config, setInitialSetting
 
     const saveSettings = useCallback((setting: SurveyQuestionsConfig) => {
         setSaveNeeded();
-        onChange(id, setting);
-    }, [id, onChange, setSaveNeeded]);
+        onChange(id, {
+            ...savedQuestionConfig,
+            ...setting,
+        });
+    }, [id, onChange, savedQuestionConfig, setSaveNeeded]);
 
     const questionOnChangeHandler = useDebouncedCallback(
         (e: React.ChangeEvent<HTMLInputElement>, questionID: string) => {
@@ -109,7 +127,7 @@ function SurveyQuestions({id, setSaveNeeded, onChange, config, setInitialSetting
                     className='horizontal question'
                 >
                     <span className='questionTitle settingLabel'>
-                        {`${questionTypeDisplayName.get(question.type)} ${question.mandatory ? '' : '(Optional)'}`}
+                        {`${getQuestionDisplayName(question)} ${question.mandatory ? '' : '(Optional)'}`}
                     </span>
 
                     <div className='vertical questionBody'>
@@ -19,16 +19,27 @@ export type TeamFilterConfig = {
     filterType: TeamFilterType;
 }
 
+export type SurveyTranslation = {
+    surveyMessageText: string;
+    questions: {[questionID: string]: {text: string}};
+};
+
 export type SurveyQuestionsConfig = {
     surveyMessageText: string;
     questions: Question[];
+    defaultLocale?: string;
+    translations?: {[locale: string]: SurveyTranslation};
 };
 
+// CombinedConfig is the plugin config. Besides the settings rendered in the System Console,
+// it holds the settings only set in config.json, such as Anonymity, Redaction and Encryption,
+// which are kept as they are when saving the console settings.
 export type CombinedConfig = {
     SurveyDateTime: DateTimeConfig;
     SurveyExpiry: ExpiryConfig;
     TeamFilter: TeamFilterConfig;
     SurveyQuestions: SurveyQuestionsConfig;
+    [settingId: string]: unknown;
 };
 
 export type CustomConfigTypes =
@@ -229,6 +240,14 @@ export type SurveyResult = Survey & {
     npsScore: number;
 }
 
+export type RatingScale = {
+    min: number;
+    max: number;
+    detractorMax: number;
+    passiveMax: number;
+    scoreType?: 'net' | 'top_group';
+};
+
 export type Question = {
     id: string;
     text?: string;
@@ -236,6 +255,7 @@ export type Question = {
     system: boolean;
     mandatory: boolean;
     helpText?: string;
+    scale?: RatingScale;
 };
 
 export type UserSurvey = Survey & {